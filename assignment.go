@@ -13,9 +13,44 @@ type Assignment struct {
 
 func (a Assignment) assign() {}
 
+// Assign create an assignment.
+//
+// value is bound as an argument, unless it is an expression like Col("Count").Add(Excluded("Count")).
 func Assign(fieldName string, value any) Assignment {
 	return Assignment{
 		filedName: fieldName,
 		value:     value,
 	}
 }
+
+var _ Expr = (*ExcludedColumn)(nil)
+
+// ExcludedColumn the value proposed for insertion in an upsert,
+// "EXCLUDED.col" on postgres and the row alias or "VALUES(col)" on mysql.
+type ExcludedColumn struct {
+	fieldName string
+}
+
+func (e ExcludedColumn) expr() {}
+
+func (e ExcludedColumn) Add(val any) MathExpr {
+	return MathExpr{
+		left:  e,
+		op:    opAdd,
+		right: valueOf(val),
+	}
+}
+
+func (e ExcludedColumn) Sub(val any) MathExpr {
+	return MathExpr{
+		left:  e,
+		op:    opSub,
+		right: valueOf(val),
+	}
+}
+
+func Excluded(fieldName string) ExcludedColumn {
+	return ExcludedColumn{
+		fieldName: fieldName,
+	}
+}
@@ -0,0 +1,59 @@
+package easyorm
+
+import (
+	"reflect"
+	"slices"
+	"time"
+
+	"github.com/JrMarcco/easy-orm/model"
+)
+
+// fillAutoTime fill the auto time fields of rows which are not set yet,
+// and insert the fields if fields are specified.
+func (i *Inserter[T]) fillAutoTime(now time.Time) {
+	for _, autoTime := range []*model.AutoTime{i.model.AutoCreateTime, i.model.AutoUpdateTime} {
+		if autoTime == nil || len(i.rows) == 0 {
+			continue
+		}
+
+		if len(i.fields) > 0 && !slices.Contains(i.fields, autoTime.FiledName) {
+			i.fields = append(slices.Clip(i.fields), autoTime.FiledName)
+		}
+
+		for _, row := range i.rows {
+			field := reflect.ValueOf(row).Elem().FieldByName(autoTime.FiledName)
+			if field.IsZero() {
+				setAutoTime(field, autoTime, now)
+			}
+		}
+	}
+}
+
+// autoTimeValue returns the value of auto time field at now.
+func autoTimeValue(autoTime *model.AutoTime, now time.Time) any {
+	typ := autoTime.Typ
+	if typ.Kind() == reflect.Pointer {
+		typ = typ.Elem()
+	}
+
+	switch {
+	case typ == reflect.TypeOf(time.Time{}):
+		return now
+	case autoTime.Milli:
+		return now.UnixMilli()
+	default:
+		return now.Unix()
+	}
+}
+
+// setAutoTime set the auto time field to now.
+func setAutoTime(field reflect.Value, autoTime *model.AutoTime, now time.Time) {
+	val := reflect.ValueOf(autoTimeValue(autoTime, now))
+	if field.Kind() == reflect.Pointer {
+		ptr := reflect.New(field.Type().Elem())
+		ptr.Elem().Set(val.Convert(field.Type().Elem()))
+		field.Set(ptr)
+		return
+	}
+	field.Set(val.Convert(field.Type()))
+}
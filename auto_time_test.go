@@ -0,0 +1,130 @@
+package easyorm
+
+import (
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type autoTimeTestModel struct {
+	Id        uint64
+	Name      string
+	CreatedAt time.Time  `orm:"auto_create_time"`
+	UpdatedAt *time.Time `orm:"auto_update_time"`
+}
+
+type autoUnixTestModel struct {
+	Id        uint64
+	CreatedAt int64  `orm:"auto_create_time"`
+	UpdatedAt uint64 `orm:"auto_update_time=milli"`
+}
+
+func TestInserter_AutoTime(t *testing.T) {
+	now := time.Date(2024, 1, 2, 3, 4, 5, 6000000, time.UTC)
+	created := time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	mockDB, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer func() { _ = mockDB.Close() }()
+
+	db, err := OpenDB(mockDB, MySQLDialect, DBWithClock(func() time.Time { return now }))
+	require.NoError(t, err)
+
+	t.Run("time", func(t *testing.T) {
+		mock.ExpectExec("INSERT INTO `auto_time_test_model` .*").
+			WithArgs(uint64(1), "Tom", now, now, uint64(2), "Jerry", created, now).
+			WillReturnResult(sqlmock.NewResult(2, 2))
+
+		rows := []*autoTimeTestModel{
+			{Id: 1, Name: "Tom"},
+			{Id: 2, Name: "Jerry", CreatedAt: created},
+		}
+		res := NewInserter[autoTimeTestModel](db).Rows(rows...).Exec(t.Context())
+		require.NoError(t, res.Err())
+
+		assert.Equal(t, now, rows[0].CreatedAt)
+		assert.Equal(t, &now, rows[0].UpdatedAt)
+		assert.Equal(t, created, rows[1].CreatedAt)
+	})
+
+	t.Run("fields", func(t *testing.T) {
+		mock.ExpectExec("INSERT INTO `auto_unix_test_model` \\(`id`, `created_at`, `updated_at`\\) VALUES \\(\\?, \\?, \\?\\);").
+			WithArgs(uint64(1), now.Unix(), uint64(now.UnixMilli())).
+			WillReturnResult(sqlmock.NewResult(1, 1))
+
+		row := &autoUnixTestModel{Id: 1}
+		res := NewInserter[autoUnixTestModel](db).Fields("Id").Rows(row).Exec(t.Context())
+		require.NoError(t, res.Err())
+
+		assert.Equal(t, now.Unix(), row.CreatedAt)
+		assert.Equal(t, uint64(now.UnixMilli()), row.UpdatedAt)
+	})
+
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestUpdater_AutoTime(t *testing.T) {
+	now := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+
+	mockDB, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+	require.NoError(t, err)
+	defer func() { _ = mockDB.Close() }()
+
+	db, err := OpenDB(mockDB, MySQLDialect, DBWithClock(func() time.Time { return now }))
+	require.NoError(t, err)
+
+	t.Run("entity", func(t *testing.T) {
+		mock.ExpectExec("UPDATE `auto_time_test_model` SET `name` = ?, `updated_at` = ? WHERE `id` = ?;").
+			WithArgs("Tom", now, 1).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+
+		entity := &autoTimeTestModel{Id: 1, Name: "Tom"}
+		res := NewUpdater[autoTimeTestModel](db).Update(entity).Set(Col("Name")).Where(Col("Id").Eq(1)).Exec(t.Context())
+		require.NoError(t, res.Err())
+		assert.Equal(t, &now, entity.UpdatedAt)
+	})
+
+	t.Run("all fields of entity", func(t *testing.T) {
+		mock.ExpectExec("UPDATE `auto_unix_test_model` SET `created_at` = ?, `updated_at` = ? WHERE `id` = ?;").
+			WithArgs(int64(100), now.UnixMilli(), 1).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+
+		entity := &autoUnixTestModel{Id: 1, CreatedAt: 100, UpdatedAt: 200}
+		res := NewUpdater[autoUnixTestModel](db).Update(entity).Where(Col("Id").Eq(1)).Exec(t.Context())
+		require.NoError(t, res.Err())
+		assert.Equal(t, uint64(now.UnixMilli()), entity.UpdatedAt)
+	})
+
+	t.Run("assigned", func(t *testing.T) {
+		mock.ExpectExec("UPDATE `auto_unix_test_model` SET `updated_at` = ?;").
+			WithArgs(0).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+
+		res := NewUpdater[autoUnixTestModel](db).Set(Assign("UpdatedAt", 0)).Exec(t.Context())
+		require.NoError(t, res.Err())
+	})
+
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestDeleter_SoftDeleteClock(t *testing.T) {
+	now := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+
+	mockDB, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+	require.NoError(t, err)
+	defer func() { _ = mockDB.Close() }()
+
+	db, err := OpenDB(mockDB, MySQLDialect, DBWithClock(func() time.Time { return now }))
+	require.NoError(t, err)
+
+	mock.ExpectExec("UPDATE `soft_delete_item` SET `deleted_at` = ? WHERE (`id` = ?) AND (`deleted_at` = ?);").
+		WithArgs(now.Unix(), 1, 0).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	res := NewDeleter[softDeleteItem](db).Where(Col("Id").Eq(1)).Exec(t.Context())
+	require.NoError(t, res.Err())
+	require.NoError(t, mock.ExpectationsWereMet())
+}
@@ -11,26 +11,73 @@ type builder struct {
 	model    *model.Model
 	registry model.Registry
 
-	dialect Dialect
-	quote   byte
+	dialect    Dialect
+	quoteOpen  byte
+	quoteClose byte
+
+	// qualify columns without table reference with the table name,
+	// used where the column name is ambiguous, e.g. in "ON CONFLICT DO UPDATE" on postgres.
+	qualify bool
+	// rowAlias the alias of the rows proposed for insertion in an upsert.
+	rowAlias string
+	// table overrides the table name of model, used by sharding to route statement to the table of shard.
+	table string
+	// schema overrides the schema of tables, resolved from context on execution, see WithSchema.
+	schema string
+	// tableScope restricts the rows of tables in statement and its sub queries, set by middlewares.
+	tableScope TableScope
 
 	sqlBuffer strings.Builder
 	args      []any
 }
 
+// reset clear the statement built before, so that Build can be called more than once, e.g. by middlewares.
+func (b *builder) reset() {
+	b.sqlBuffer.Reset()
+	b.args = nil
+}
+
 func (b *builder) writeWithQuote(name string) {
-	b.sqlBuffer.WriteByte(b.quote)
+	b.sqlBuffer.WriteByte(b.quoteOpen)
 	b.sqlBuffer.WriteString(name)
-	b.sqlBuffer.WriteByte(b.quote)
+	b.sqlBuffer.WriteByte(b.quoteClose)
+}
+
+// writeTableAlias write the alias of table or sub query.
+func (b *builder) writeTableAlias(alias string) {
+	b.sqlBuffer.WriteString(b.dialect.tableAliasKeyword())
+	b.writeWithQuote(alias)
+}
+
+// writeTerminator write the terminator at the end of statement.
+func (b *builder) writeTerminator() {
+	b.sqlBuffer.WriteString(b.dialect.terminator())
 }
 
+// writeTable write the table of model.
 func (b *builder) writeTable() {
-	segments := strings.Split(b.model.TableName, ".")
-	b.writeWithQuote(segments[0])
+	b.writeModelTable(b.model, b.table)
+}
+
+// writeModelTable write the table of model qualified by schema and catalog like "catalog"."schema"."table".
+// The table name of model is replaced by table if not empty, and the schema by the schema of builder if any.
+func (b *builder) writeModelTable(m *model.Model, table string) {
+	if table == "" {
+		table = m.TableName
+	}
+
+	schema := m.Schema
+	if b.schema != "" {
+		schema = b.schema
+	}
 
-	if len(segments) > 1 {
-		b.writeWithQuote(segments[1])
+	for _, segment := range []string{m.Catalog, schema} {
+		if segment != "" {
+			b.writeWithQuote(segment)
+			b.sqlBuffer.WriteByte('.')
+		}
 	}
+	b.writeWithQuote(table)
 }
 
 func (b *builder) writeField(name string) error {
@@ -86,10 +133,28 @@ func (b *builder) buildExpr(expr Expr) error {
 				b.sqlBuffer.WriteByte(')')
 			}
 		}
+	case MathExpr:
+		if err := b.buildMathOperand(exprTyp.left); err != nil {
+			return err
+		}
+		b.sqlBuffer.WriteByte(' ')
+		b.sqlBuffer.WriteString(exprTyp.op.String())
+		b.sqlBuffer.WriteByte(' ')
+		if err := b.buildMathOperand(exprTyp.right); err != nil {
+			return err
+		}
 	case Column:
 		if err := b.buildColumn(exprTyp.tableRef, exprTyp.fieldName); err != nil {
 			return err
 		}
+	case tableColumn:
+		if err := b.buildTableColumn(exprTyp); err != nil {
+			return err
+		}
+	case ExcludedColumn:
+		if err := b.dialect.excluded(b, exprTyp.fieldName); err != nil {
+			return err
+		}
 	case columnValue:
 		b.buildColumnValue(exprTyp.value)
 	case SubQuery:
@@ -105,6 +170,19 @@ func (b *builder) buildExpr(expr Expr) error {
 	return nil
 }
 
+func (b *builder) buildMathOperand(expr Expr) error {
+	if _, ok := expr.(MathExpr); !ok {
+		return b.buildExpr(expr)
+	}
+
+	b.sqlBuffer.WriteByte('(')
+	if err := b.buildExpr(expr); err != nil {
+		return err
+	}
+	b.sqlBuffer.WriteByte(')')
+	return nil
+}
+
 func (b *builder) buildColumn(tableRef TableRef, fieldName string) error {
 	var tableAlias string
 	if tableRef != nil {
@@ -114,6 +192,9 @@ func (b *builder) buildColumn(tableRef TableRef, fieldName string) error {
 	if tableAlias != "" {
 		b.writeWithQuote(tableAlias)
 		b.sqlBuffer.WriteByte('.')
+	} else if tableRef == nil && b.qualify {
+		b.writeTable()
+		b.sqlBuffer.WriteByte('.')
 	}
 
 	columnName, err := b.columnName(tableRef, fieldName)
@@ -124,6 +205,27 @@ func (b *builder) buildColumn(tableRef TableRef, fieldName string) error {
 	return nil
 }
 
+func (b *builder) buildTableColumn(col tableColumn) error {
+	m, err := b.registry.GetModel(col.table.entity)
+	if err != nil {
+		return err
+	}
+
+	field, ok := m.Fields[col.fieldName]
+	if !ok {
+		return errs.ErrInvalidField(col.fieldName)
+	}
+
+	if alias := col.table.tableAlias(); alias != "" {
+		b.writeWithQuote(alias)
+	} else {
+		b.writeModelTable(m, "")
+	}
+	b.sqlBuffer.WriteByte('.')
+	b.writeWithQuote(field.ColumnName)
+	return nil
+}
+
 func (b *builder) buildColumnValue(value any) {
 	vals, ok := value.([]any)
 	if ok {
@@ -165,22 +267,86 @@ func (b *builder) buildAggregate(aggregate Aggregate) error {
 
 func (b *builder) buildSubQuery(subQ SubQuery) error {
 	b.sqlBuffer.WriteByte('(')
-	sql := subQ.statement.SQL
-	// remove ';' at the end of SQL
-	b.sqlBuffer.WriteString(sql[:len(sql)-1])
+	if err := b.writeSubQuery(subQ); err != nil {
+		return err
+	}
 	b.sqlBuffer.WriteByte(')')
 
-	if len(subQ.statement.Args) > 0 {
-		b.addArgs(subQ.statement.Args...)
+	if alias := subQ.tableAlias(); alias != "" {
+		b.writeTableAlias(alias)
 	}
+	return nil
+}
 
-	if alias := subQ.tableAlias(); alias != "" {
-		b.sqlBuffer.WriteString(" AS ")
-		b.writeWithQuote(alias)
+// writeSubQuery write the statement of sub query without terminator,
+// the sub query is rebuilt in the schema and with the table scope of builder if any.
+func (b *builder) writeSubQuery(subQ SubQuery) error {
+	statement := subQ.statement
+	if (b.schema != "" || b.tableScope != nil) && subQ.query != nil {
+		var err error
+		if statement, err = subQ.query.buildIn(b.schema, b.tableScope); err != nil {
+			return err
+		}
+	}
+
+	// remove terminator at the end of SQL
+	b.sqlBuffer.WriteString(strings.TrimSuffix(statement.SQL, b.dialect.terminator()))
+	if len(statement.Args) > 0 {
+		b.addArgs(statement.Args...)
 	}
 	return nil
 }
 
+// buildAssigns build the assignments of "SET" clause.
+// Column assignment sets the column to the value proposed for insertion.
+func (b *builder) buildAssigns(assigns []Assignable) error {
+	for index, assign := range assigns {
+		if index > 0 {
+			b.sqlBuffer.WriteString(", ")
+		}
+
+		switch assignTyp := assign.(type) {
+		case Assignment:
+			if err := b.writeField(assignTyp.filedName); err != nil {
+				return err
+			}
+			b.sqlBuffer.WriteString(" = ")
+
+			if expr, ok := assignTyp.value.(Expr); ok {
+				if err := b.buildExpr(expr); err != nil {
+					return err
+				}
+				continue
+			}
+
+			b.addArgs(assignTyp.value)
+			b.dialect.bindArg(b)
+		case Column:
+			if err := b.writeField(assignTyp.fieldName); err != nil {
+				return err
+			}
+
+			b.sqlBuffer.WriteString(" = ")
+			if err := b.dialect.excluded(b, assignTyp.fieldName); err != nil {
+				return err
+			}
+		default:
+			return errs.ErrInvalidAssignable
+		}
+	}
+	return nil
+}
+
+func (b *builder) buildPredicates(pds []Predicate) error {
+	if len(pds) == 0 {
+		return nil
+	}
+
+	c := NewCondition(condTypWhere, pds)
+	b.sqlBuffer.WriteString(c.typ.String())
+	return b.buildExpr(c.expr)
+}
+
 func (b *builder) columnName(tableRef TableRef, fieldName string) (string, error) {
 	switch refTyp := tableRef.(type) {
 	case nil:
@@ -223,12 +389,61 @@ func (b *builder) addArgs(val ...any) {
 	b.args = append(b.args, val...)
 }
 
+// primaryKeyOf returns the predicate matching the rows of entities by primary key,
+// like "id IN (?, ?)", or "((code = ?) AND (region = ?)) OR (...)" of composite primary key.
+func primaryKeyOf[T any](c *core, m *model.Model, entities []*T) (Predicate, error) {
+	pks := m.PrimaryKeys()
+	if len(pks) == 0 {
+		return Predicate{}, errs.ErrWithoutPrimaryKey
+	}
+
+	vals := make([][]any, 0, len(entities))
+	for _, entity := range entities {
+		resolver := c.resolverCreator(m, entity)
+
+		val := make([]any, 0, len(pks))
+		for _, pk := range pks {
+			v, err := resolver.ReadColumn(pk.FiledName)
+			if err != nil {
+				return Predicate{}, err
+			}
+			val = append(val, v)
+		}
+		vals = append(vals, val)
+	}
+
+	if len(pks) == 1 && len(vals) > 1 {
+		in := make([]any, 0, len(vals))
+		for _, val := range vals {
+			in = append(in, val[0])
+		}
+		return Col(pks[0].FiledName).In(in...), nil
+	}
+
+	var res Predicate
+	for i, val := range vals {
+		pd := Col(pks[0].FiledName).Eq(val[0])
+		for j, pk := range pks[1:] {
+			pd = pd.And(Col(pk.FiledName).Eq(val[j+1]))
+		}
+
+		if i == 0 {
+			res = pd
+			continue
+		}
+		res = res.Or(pd)
+	}
+	return res, nil
+}
+
 func newBuilder(orm orm) builder {
 	dialect := orm.getCore().dialect
+	quoteOpen, quoteClose := dialect.quote()
 	return builder{
-		registry:  orm.getCore().registry,
-		dialect:   dialect,
-		quote:     dialect.quote(),
-		sqlBuffer: strings.Builder{},
+		registry:   orm.getCore().registry,
+		dialect:    dialect,
+		quoteOpen:  quoteOpen,
+		quoteClose: quoteClose,
+		sqlBuffer:  strings.Builder{},
 	}
 }
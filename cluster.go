@@ -0,0 +1,75 @@
+package easyorm
+
+import (
+	"context"
+	"database/sql"
+	"math/rand/v2"
+	"sync/atomic"
+)
+
+// LoadBalancer choose a replica for reads.
+type LoadBalancer interface {
+	// Next returns the replica the next read executed on, replicas is never empty.
+	Next(replicas []*sql.DB) *sql.DB
+}
+
+// LoadBalancerFunc an adapter to allow the use of ordinary function as LoadBalancer.
+type LoadBalancerFunc func(replicas []*sql.DB) *sql.DB
+
+func (f LoadBalancerFunc) Next(replicas []*sql.DB) *sql.DB {
+	return f(replicas)
+}
+
+type roundRobinBalancer struct {
+	cnt atomic.Uint64
+}
+
+func (b *roundRobinBalancer) Next(replicas []*sql.DB) *sql.DB {
+	return replicas[(b.cnt.Add(1)-1)%uint64(len(replicas))]
+}
+
+// RoundRobinBalancer choose replicas in turn.
+func RoundRobinBalancer() LoadBalancer {
+	return &roundRobinBalancer{}
+}
+
+// RandomBalancer choose replica randomly.
+func RandomBalancer() LoadBalancer {
+	return LoadBalancerFunc(func(replicas []*sql.DB) *sql.DB {
+		return replicas[rand.N(len(replicas))]
+	})
+}
+
+// LeastInUseBalancer choose the replica with the least connections in use reported by sql.DBStats,
+// the first one wins on a tie.
+func LeastInUseBalancer() LoadBalancer {
+	return LoadBalancerFunc(func(replicas []*sql.DB) *sql.DB {
+		res, inUse := replicas[0], replicas[0].Stats().InUse
+		for _, replica := range replicas[1:] {
+			if n := replica.Stats().InUse; n < inUse {
+				res, inUse = replica, n
+			}
+		}
+		return res
+	})
+}
+
+type primaryKey struct{}
+
+// WithPrimary returns a context forcing reads of DB executed with it to primary,
+// e.g. for reading your own writes before replicas catch up.
+func WithPrimary(ctx context.Context) context.Context {
+	return context.WithValue(ctx, primaryKey{}, true)
+}
+
+// replicaOf returns the sql.DB the read executed on.
+func (db *DB) replicaOf(ctx context.Context) *sql.DB {
+	if len(db.replicas) == 0 {
+		return db.sqlDB
+	}
+
+	if primary, _ := ctx.Value(primaryKey{}).(bool); primary {
+		return db.sqlDB
+	}
+	return db.balancer.Next(db.replicas)
+}
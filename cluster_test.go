@@ -0,0 +1,122 @@
+package easyorm
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRoundRobinBalancer(t *testing.T) {
+	replicas := []*sql.DB{{}, {}, {}}
+
+	balancer := RoundRobinBalancer()
+	for i := 0; i < 6; i++ {
+		assert.Same(t, replicas[i%3], balancer.Next(replicas))
+	}
+}
+
+func TestRandomBalancer(t *testing.T) {
+	replicas := []*sql.DB{{}, {}}
+
+	balancer := RandomBalancer()
+	for i := 0; i < 10; i++ {
+		assert.Contains(t, replicas, balancer.Next(replicas))
+	}
+}
+
+func TestLeastInUseBalancer(t *testing.T) {
+	busyDB, busyMock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer func() {
+		_ = busyDB.Close()
+	}()
+
+	idleDB, _, err := sqlmock.New()
+	require.NoError(t, err)
+	defer func() {
+		_ = idleDB.Close()
+	}()
+
+	// hold a connection of busyDB in use
+	busyMock.ExpectBegin()
+	busyMock.ExpectRollback()
+	tx, err := busyDB.Begin()
+	require.NoError(t, err)
+	defer func() {
+		_ = tx.Rollback()
+	}()
+
+	assert.Same(t, idleDB, LeastInUseBalancer().Next([]*sql.DB{busyDB, idleDB}))
+}
+
+func TestDB_Replicas(t *testing.T) {
+	primaryDB, primaryMock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+	require.NoError(t, err)
+	defer func() {
+		_ = primaryDB.Close()
+	}()
+
+	replicaDB, replicaMock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+	require.NoError(t, err)
+	defer func() {
+		_ = replicaDB.Close()
+	}()
+
+	db, err := OpenDB(primaryDB, MySQLDialect, DBWithReplicas(replicaDB))
+	require.NoError(t, err)
+
+	ctx := context.Background()
+	query := "SELECT * FROM `tx_test_model` WHERE `id` = ? LIMIT 1;"
+
+	// reads go to replica
+	replicaMock.ExpectQuery(query).
+		WithArgs(1).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "name"}).AddRow(1, "replica"))
+	res, err := NewSelector[txTestModel](db).Where(Col("Id").Eq(1)).FindOne(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, "replica", res.Name)
+
+	replicaMock.ExpectQuery("SELECT `name` FROM `tx_test_model`").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "name"}).AddRow(1, "raw"))
+	res, err = NewRaw[txTestModel](db, "SELECT `name` FROM `tx_test_model`").FindOne(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, "raw", res.Name)
+
+	// forced reads go to primary
+	primaryMock.ExpectQuery(query).
+		WithArgs(1).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "name"}).AddRow(1, "primary"))
+	res, err = NewSelector[txTestModel](db).Where(Col("Id").Eq(1)).FindOne(WithPrimary(ctx))
+	require.NoError(t, err)
+	assert.Equal(t, "primary", res.Name)
+
+	// writes go to primary
+	primaryMock.ExpectExec("INSERT INTO `tx_test_model` (`id`, `name`) VALUES (?, ?);").
+		WithArgs(uint64(1), "foo").
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	err = NewInserter[txTestModel](db).Rows(&txTestModel{Id: 1, Name: "foo"}).Exec(ctx).Err()
+	require.NoError(t, err)
+
+	// reads in transaction go to primary
+	primaryMock.ExpectBegin()
+	primaryMock.ExpectQuery(query).
+		WithArgs(1).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "name"}).AddRow(1, "tx"))
+	primaryMock.ExpectCommit()
+	err = db.DoTx(ctx, func(ctx context.Context, tx *Tx) error {
+		res, err := NewSelector[txTestModel](db).Where(Col("Id").Eq(1)).FindOne(ctx)
+		if err != nil {
+			return err
+		}
+		assert.Equal(t, "tx", res.Name)
+		return nil
+	}, nil)
+	require.NoError(t, err)
+
+	assert.NoError(t, primaryMock.ExpectationsWereMet())
+	assert.NoError(t, replicaMock.ExpectationsWereMet())
+}
@@ -1,8 +1,10 @@
 package main
 
 import (
+	"errors"
 	"fmt"
 	"go/ast"
+	"go/types"
 )
 
 type FileVisitEntry struct {
@@ -16,6 +18,19 @@ func (fve *FileVisitEntry) Get() File {
 	return File{}
 }
 
+// Err returns the errors of fields whose types are not supported.
+func (fve *FileVisitEntry) Err() error {
+	if fve.fv == nil {
+		return nil
+	}
+
+	var errs []error
+	for _, tv := range fve.fv.types {
+		errs = append(errs, tv.errs...)
+	}
+	return errors.Join(errs...)
+}
+
 func (fve *FileVisitEntry) Visit(node ast.Node) ast.Visitor {
 	if n, ok := node.(*ast.File); ok {
 		fve.fv = &fileVisitor{
@@ -60,7 +75,11 @@ func (fv *fileVisitor) Visit(node ast.Node) ast.Visitor {
 		fv.types = append(fv.types, tv)
 		return tv
 	case *ast.ImportSpec:
-		fv.imports = append(fv.imports, fmt.Sprintf("%s %s", n.Name.String(), n.Path.Value))
+		if n.Name != nil {
+			fv.imports = append(fv.imports, fmt.Sprintf("%s %s", n.Name.String(), n.Path.Value))
+		} else {
+			fv.imports = append(fv.imports, n.Path.Value)
+		}
 	}
 
 	return fv
@@ -79,6 +98,7 @@ type Field struct {
 type typeVisitor struct {
 	name   string
 	fields []Field
+	errs   []error
 }
 
 func (tv *typeVisitor) Get() Type {
@@ -90,19 +110,10 @@ func (tv *typeVisitor) Get() Type {
 
 func (tv *typeVisitor) Visit(node ast.Node) ast.Visitor {
 	if fn, ok := node.(*ast.Field); ok {
-		var typeName string
-		switch n := fn.Type.(type) {
-		case *ast.Ident:
-			typeName = n.String()
-		case *ast.StarExpr:
-			switch xType := n.X.(type) {
-			case *ast.Ident:
-				typeName = fmt.Sprintf("*%s", xType.String())
-			case *ast.SelectorExpr:
-				typeName = fmt.Sprintf("*%s.%s", xType.X.(*ast.Ident).String(), xType.Sel.String())
-			}
-		default:
-			fmt.Println(n)
+		typeName := typeString(fn.Type)
+		if typeName == "" {
+			tv.errs = append(tv.errs, fmt.Errorf("unsupported type of field %s.%s: %s",
+				tv.name, fn.Names[0].String(), types.ExprString(fn.Type)))
 		}
 
 		tv.fields = append(tv.fields, Field{
@@ -114,3 +125,24 @@ func (tv *typeVisitor) Visit(node ast.Node) ast.Visitor {
 
 	return tv
 }
+
+// typeString returns the type of field expression, like "*sql.NullString", "time.Time" or "[]byte".
+func typeString(expr ast.Expr) string {
+	switch n := expr.(type) {
+	case *ast.Ident:
+		return n.String()
+	case *ast.StarExpr:
+		if x := typeString(n.X); x != "" {
+			return "*" + x
+		}
+	case *ast.SelectorExpr:
+		if x, ok := n.X.(*ast.Ident); ok {
+			return fmt.Sprintf("%s.%s", x.String(), n.Sel.String())
+		}
+	case *ast.ArrayType:
+		if x := typeString(n.Elt); x != "" && n.Len == nil {
+			return "[]" + x
+		}
+	}
+	return ""
+}
@@ -0,0 +1,133 @@
+package main
+
+import (
+	_ "embed"
+	"flag"
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"text/template"
+)
+
+//go:embed tpl.gohtml
+var tpl string
+
+type OrmFile struct {
+	File
+	Ops []string
+}
+
+// gen generates the predicates of models in go file:
+//
+//	gen model.go
+//
+// or generates the models of tables in database or DDL file first, then generates their predicates:
+//
+//	gen -dialect mysql -dsn "user:password@tcp(127.0.0.1:3306)/db" -out model/model.go
+//	gen -dialect postgres -ddl schema.sql -out model/model.go
+//
+// sqlite is supported by DDL file only, as no sqlite driver is linked into gen.
+func main() {
+	dialectName := flag.String("dialect", "mysql", "dialect of database, mysql, postgres or sqlite")
+	dsn := flag.String("dsn", "", "data source name of database to generate models from, mysql or postgres only")
+	ddl := flag.String("ddl", "", "DDL file to generate models from without connecting database, required by sqlite")
+	out := flag.String("out", "model.go", "file of models generated")
+	pkg := flag.String("pkg", "", "package of models generated, the name of directory of out by default")
+	tables := flag.String("tables", "", "comma separated tables to generate models, all tables by default")
+	flag.Parse()
+
+	opts := reverseOpts{
+		dialect: *dialectName,
+		dsn:     *dsn,
+		ddl:     *ddl,
+		pkg:     *pkg,
+	}
+	if *tables != "" {
+		opts.tables = strings.Split(*tables, ",")
+	}
+
+	if err := run(flag.Arg(0), *out, opts); err != nil {
+		_, _ = fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+}
+
+// run generates the models to out first if the dsn or DDL file is given, then generates the predicates of src or out.
+func run(src string, out string, opts reverseOpts) error {
+	if opts.dsn != "" || opts.ddl != "" {
+		if err := reverse(out, opts); err != nil {
+			return err
+		}
+		fmt.Println("success to generate: ", out)
+		src = out
+	}
+
+	if src == "" {
+		return fmt.Errorf("usage: gen <model.go> or gen -dsn <dsn> -out <model.go> or gen -ddl <schema.sql> -out <model.go>")
+	}
+
+	dst, err := generate(src)
+	if err != nil {
+		return err
+	}
+	fmt.Println("success to generate: ", dst)
+	return nil
+}
+
+// generate writes the predicates of models in src to the file suffixed by ".gen.go".
+func generate(src string) (string, error) {
+	dstDir := filepath.Dir(src)
+	filename := filepath.Base(src)
+
+	index := strings.LastIndex(filename, ".")
+	dst := filepath.Join(dstDir, filename[:index]+".gen.go")
+
+	f, err := os.Create(dst)
+	if err != nil {
+		return "", err
+	}
+	defer func() { _ = f.Close() }()
+
+	if err = gen(f, src); err != nil {
+		return "", err
+	}
+
+	return dst, gofmt(dst)
+}
+
+func gofmt(file string) error {
+	return exec.Command("gofmt", "-w", file).Run()
+}
+
+func gen(w io.Writer, srcFile string) error {
+	var err error
+
+	fs := token.NewFileSet()
+	f, err := parser.ParseFile(fs, srcFile, nil, parser.ParseComments)
+	if err != nil {
+		return err
+	}
+
+	fve := &FileVisitEntry{}
+	ast.Walk(fve, f)
+	if err = fve.Err(); err != nil {
+		return err
+	}
+	file := fve.Get()
+
+	t := template.New("easyorm_gen")
+	if t, err = t.Parse(tpl); err != nil {
+		return err
+	}
+
+	return t.Execute(w, OrmFile{
+		File: file,
+		Ops:  []string{"Eq", "Ne", "Gt", "Ge", "Lt", "Le"},
+	})
+}
@@ -0,0 +1,332 @@
+package main
+
+import (
+	"context"
+	_ "embed"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"text/template"
+	"time"
+	"unicode"
+
+	easyorm "github.com/JrMarcco/easy-orm"
+	"github.com/JrMarcco/easy-orm/model"
+	"github.com/JrMarcco/easy-orm/schema"
+	_ "github.com/go-sql-driver/mysql"
+	_ "github.com/lib/pq"
+)
+
+//go:embed model.gohtml
+var modelTpl string
+
+// drivers the drivers of dialects connecting database, sqlite is supported by DDL file only.
+var drivers = map[string]struct {
+	driver  string
+	dialect easyorm.Dialect
+}{
+	"mysql":    {driver: "mysql", dialect: easyorm.MySQLDialect},
+	"postgres": {driver: "postgres", dialect: easyorm.PostgresDialect},
+}
+
+type reverseOpts struct {
+	dialect string
+	dsn     string
+	ddl     string
+	pkg     string
+	tables  []string
+}
+
+type ModelFile struct {
+	Pkg     string
+	Imports []string
+	Models  []Model
+}
+
+type Model struct {
+	Name  string
+	Table string
+	// TableOpt the table name differs from the one derived from the model name.
+	TableOpt bool
+	Fields   []ModelField
+}
+
+type ModelField struct {
+	Name string
+	Type string
+	Tag  string
+}
+
+// reverse writes the models of tables read from database or DDL file to out.
+func reverse(out string, opts reverseOpts) error {
+	tables, err := readTables(opts)
+	if err != nil {
+		return err
+	}
+
+	if len(opts.tables) > 0 {
+		tables = slices.DeleteFunc(tables, func(t *schema.Table) bool {
+			return !slices.Contains(opts.tables, t.Name)
+		})
+	}
+	if len(tables) == 0 {
+		return fmt.Errorf("no table to generate")
+	}
+
+	pkg := opts.pkg
+	if pkg == "" {
+		dir, err := filepath.Abs(filepath.Dir(out))
+		if err != nil {
+			return err
+		}
+		pkg = filepath.Base(dir)
+	}
+
+	f, err := os.Create(out)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = f.Close() }()
+
+	t, err := template.New("easyorm_model").Parse(modelTpl)
+	if err != nil {
+		return err
+	}
+	if err = t.Execute(f, modelFile(pkg, opts.dialect, tables)); err != nil {
+		return err
+	}
+	return gofmt(out)
+}
+
+// readTables read the tables from DDL file if any, otherwise from database.
+func readTables(opts reverseOpts) ([]*schema.Table, error) {
+	if opts.ddl != "" {
+		content, err := os.ReadFile(opts.ddl)
+		if err != nil {
+			return nil, err
+		}
+		return schema.ParseDDL(string(content))
+	}
+
+	d, ok := drivers[opts.dialect]
+	if !ok {
+		return nil, fmt.Errorf("unsupported dialect to connect: %s, generate from DDL file by -ddl instead", opts.dialect)
+	}
+
+	db, err := easyorm.Open(d.driver, opts.dsn, d.dialect)
+	if err != nil {
+		return nil, err
+	}
+
+	dialect, err := schema.DialectOf(d.dialect)
+	if err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+	return schema.Tables(ctx, db, dialect)
+}
+
+func modelFile(pkg string, dialect string, tables []*schema.Table) ModelFile {
+	file := ModelFile{Pkg: pkg}
+	for _, t := range tables {
+		m := Model{Name: goName(t.Name), Table: t.Name}
+		m.TableOpt = model.TableNameOf(m.Name) != t.Name
+
+		for _, col := range t.Columns {
+			typ, tags := goType(dialect, col)
+			if strings.HasPrefix(strings.TrimPrefix(typ, "*"), "time.") && !slices.Contains(file.Imports, "time") {
+				file.Imports = append(file.Imports, "time")
+			}
+
+			name := goName(col.Name)
+			if model.TableNameOf(name) != col.Name {
+				tags = append([]string{"column=" + col.Name}, tags...)
+			}
+			if slices.Contains(t.PrimaryKey, col.Name) {
+				tags = append(tags, "pk")
+			}
+			if col.Default != nil && validTagValue(*col.Default) {
+				tags = append(tags, "default="+*col.Default)
+			}
+			tags = append(tags, indexTags(t, col.Name)...)
+
+			m.Fields = append(m.Fields, ModelField{Name: name, Type: typ, Tag: strings.Join(tags, ",")})
+		}
+		file.Models = append(file.Models, m)
+	}
+	return file
+}
+
+// goType returns the go type of column and the tags declaring the type,
+// the nullable column is pointer except bytes tagged nullable.
+func goType(dialect string, col schema.Column) (string, []string) {
+	typ := strings.ToLower(col.Type)
+	unsigned := strings.Contains(typ, "unsigned")
+	typ = strings.TrimSpace(strings.NewReplacer("unsigned", "", "zerofill", "").Replace(typ))
+
+	base, params := typ, ""
+	if i := strings.IndexByte(typ, '('); i >= 0 {
+		base = strings.TrimSpace(typ[:i])
+		params = strings.Trim(typ[i:], "() ")
+	}
+
+	var goTyp string
+	var tags []string
+	integer := func(signed string, unsignedTyp string) string {
+		if unsigned {
+			return unsignedTyp
+		}
+		return signed
+	}
+
+	switch base {
+	case "bool", "boolean":
+		goTyp = "bool"
+	case "bit":
+		goTyp = "bool"
+		if params != "" && params != "1" {
+			goTyp, tags = "[]byte", []string{"type=" + col.Type}
+		}
+	case "tinyint":
+		goTyp = integer("int8", "uint8")
+		if params == "1" {
+			goTyp = "bool"
+		}
+	case "smallint", "int2", "smallserial":
+		goTyp = integer("int16", "uint16")
+	case "mediumint", "int", "int4", "serial":
+		goTyp = integer("int32", "uint32")
+	case "integer":
+		// the integer of sqlite is 64-bit
+		goTyp = integer("int32", "uint32")
+		if dialect == "sqlite" {
+			goTyp = integer("int64", "uint64")
+		}
+	case "bigint", "int8", "bigserial":
+		goTyp = integer("int64", "uint64")
+	case "decimal", "numeric":
+		// the decimal is string to keep its precision, except the integer fitting in uint64 or int64
+		goTyp, tags = "string", []string{"type=" + col.Type}
+		if precision, scale, ok := strings.Cut(params, ","); !ok || strings.TrimSpace(scale) == "0" {
+			switch p, _ := strconv.Atoi(strings.TrimSpace(precision)); {
+			case p == 20:
+				goTyp, tags = "uint64", nil
+			case p > 0 && p <= 18:
+				goTyp = "int64"
+			}
+		}
+	case "float", "real", "float4":
+		goTyp = "float32"
+		if dialect == "sqlite" {
+			goTyp = "float64"
+		}
+	case "double", "double precision", "float8":
+		goTyp = "float64"
+	case "char", "varchar", "character", "character varying", "nchar", "nvarchar":
+		goTyp = "string"
+		if size, err := strconv.Atoi(params); err == nil && size > 0 {
+			tags = []string{"size=" + params}
+		}
+	case "text", "tinytext", "mediumtext", "longtext", "clob":
+		goTyp = "string"
+	case "blob", "tinyblob", "mediumblob", "longblob", "binary", "varbinary", "bytea":
+		goTyp = "[]byte"
+	case "date", "datetime", "timestamp", "timestamptz",
+		"timestamp with time zone", "timestamp without time zone":
+		goTyp = "time.Time"
+	default:
+		goTyp, tags = "string", []string{"type=" + col.Type}
+	}
+
+	if tags != nil && !validTagValue(col.Type) {
+		tags = nil
+	}
+
+	if col.Nullable {
+		if goTyp == "[]byte" {
+			return goTyp, append(tags, "nullable")
+		}
+		return "*" + goTyp, tags
+	}
+	return goTyp, tags
+}
+
+// indexTags returns the tags of indexes the column belongs to,
+// the index of the column alone named like "uk_user_email" or "idx_user_name" is tagged without name.
+func indexTags(t *schema.Table, col string) []string {
+	var unique, index string
+	for _, idx := range t.Indexes {
+		if !slices.Contains(idx.Columns, col) {
+			continue
+		}
+
+		flag, name, prefix := &index, idx.Name, "idx_"
+		if idx.Unique {
+			flag, prefix = &unique, "uk_"
+		}
+		if *flag != "" {
+			// only one unique and one index can be tagged
+			continue
+		}
+
+		if len(idx.Columns) == 1 && idx.Name == prefix+t.Name+"_"+col {
+			name = ""
+		}
+		*flag = name
+		if name == "" {
+			*flag = model.TagValUnnamed
+		}
+	}
+
+	var tags []string
+	for _, idx := range []struct {
+		flag string
+		name string
+	}{
+		{flag: "unique", name: unique},
+		{flag: "index", name: index},
+	} {
+		switch idx.name {
+		case "":
+		case model.TagValUnnamed:
+			tags = append(tags, idx.flag)
+		default:
+			tags = append(tags, idx.flag+"="+idx.name)
+		}
+	}
+	return tags
+}
+
+// validTagValue reports whether the value can be written in orm tag.
+func validTagValue(val string) bool {
+	return !strings.ContainsAny(val, "\"`\n") && (!strings.Contains(val, "=") || strings.HasPrefix(val, "'"))
+}
+
+// goName returns the exported go name of sql name, like "UserInfo" of "user_info".
+func goName(name string) string {
+	var sb strings.Builder
+	upper := true
+	for _, r := range name {
+		if !unicode.IsLetter(r) && !unicode.IsDigit(r) {
+			upper = true
+			continue
+		}
+
+		if upper {
+			r = unicode.ToUpper(r)
+			upper = false
+		}
+		sb.WriteRune(r)
+	}
+
+	res := sb.String()
+	if res == "" || !unicode.IsLetter(rune(res[0])) {
+		res = "T" + res
+	}
+	return res
+}
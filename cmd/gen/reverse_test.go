@@ -0,0 +1,212 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/JrMarcco/easy-orm/schema"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGoType(t *testing.T) {
+	tcs := []struct {
+		name     string
+		dialect  string
+		col      schema.Column
+		wantTyp  string
+		wantTags []string
+	}{
+		{
+			name:    "mysql unsigned bigint",
+			dialect: "mysql",
+			col:     schema.Column{Type: "bigint(20) unsigned"},
+			wantTyp: "uint64",
+		}, {
+			name:    "mysql nullable int",
+			dialect: "mysql",
+			col:     schema.Column{Type: "int", Nullable: true},
+			wantTyp: "*int32",
+		}, {
+			name:    "mysql tinyint bool",
+			dialect: "mysql",
+			col:     schema.Column{Type: "tinyint(1)"},
+			wantTyp: "bool",
+		}, {
+			name:     "mysql varchar",
+			dialect:  "mysql",
+			col:      schema.Column{Type: "varchar(64)"},
+			wantTyp:  "string",
+			wantTags: []string{"size=64"},
+		}, {
+			name:     "mysql nullable varchar",
+			dialect:  "mysql",
+			col:      schema.Column{Type: "varchar(64)", Nullable: true},
+			wantTyp:  "*string",
+			wantTags: []string{"size=64"},
+		}, {
+			name:     "mysql decimal",
+			dialect:  "mysql",
+			col:      schema.Column{Type: "decimal(10,2)"},
+			wantTyp:  "string",
+			wantTags: []string{"type=decimal(10,2)"},
+		}, {
+			name:    "mysql decimal of uint64",
+			dialect: "mysql",
+			col:     schema.Column{Type: "decimal(20,0)"},
+			wantTyp: "uint64",
+		}, {
+			name:     "mysql decimal of int64",
+			dialect:  "mysql",
+			col:      schema.Column{Type: "decimal(18,0)"},
+			wantTyp:  "int64",
+			wantTags: []string{"type=decimal(18,0)"},
+		}, {
+			name:    "mysql nullable datetime",
+			dialect: "mysql",
+			col:     schema.Column{Type: "datetime", Nullable: true},
+			wantTyp: "*time.Time",
+		}, {
+			name:     "mysql nullable blob",
+			dialect:  "mysql",
+			col:      schema.Column{Type: "blob", Nullable: true},
+			wantTyp:  "[]byte",
+			wantTags: []string{"nullable"},
+		}, {
+			name:     "mysql enum",
+			dialect:  "mysql",
+			col:      schema.Column{Type: "enum('a','b')"},
+			wantTyp:  "string",
+			wantTags: []string{"type=enum('a','b')"},
+		}, {
+			name:    "postgres bigserial",
+			dialect: "postgres",
+			col:     schema.Column{Type: "bigserial"},
+			wantTyp: "int64",
+		}, {
+			name:     "postgres nullable numeric",
+			dialect:  "postgres",
+			col:      schema.Column{Type: "numeric(12,4)", Nullable: true},
+			wantTyp:  "*string",
+			wantTags: []string{"type=numeric(12,4)"},
+		}, {
+			name:     "postgres character varying",
+			dialect:  "postgres",
+			col:      schema.Column{Type: "character varying(32)"},
+			wantTyp:  "string",
+			wantTags: []string{"size=32"},
+		}, {
+			name:    "postgres double precision",
+			dialect: "postgres",
+			col:     schema.Column{Type: "double precision"},
+			wantTyp: "float64",
+		}, {
+			name:    "postgres nullable timestamptz",
+			dialect: "postgres",
+			col:     schema.Column{Type: "timestamp with time zone", Nullable: true},
+			wantTyp: "*time.Time",
+		}, {
+			name:    "postgres bytea",
+			dialect: "postgres",
+			col:     schema.Column{Type: "bytea"},
+			wantTyp: "[]byte",
+		}, {
+			name:    "postgres jsonb",
+			dialect: "postgres",
+			col:     schema.Column{Type: "jsonb"},
+			wantTyp: "string",
+			// the type of unknown column is declared
+			wantTags: []string{"type=jsonb"},
+		}, {
+			name:    "sqlite integer",
+			dialect: "sqlite",
+			col:     schema.Column{Type: "INTEGER"},
+			wantTyp: "int64",
+		},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			typ, tags := goType(tc.dialect, tc.col)
+			assert.Equal(t, tc.wantTyp, typ)
+			assert.Equal(t, tc.wantTags, tags)
+		})
+	}
+}
+
+func TestIndexTags(t *testing.T) {
+	table := &schema.Table{
+		Name: "user",
+		Indexes: []schema.Index{
+			{Name: "uk_user_email", Columns: []string{"email"}, Unique: true},
+			{Name: "idx_user_name", Columns: []string{"name"}},
+			{Name: "idx_name_age", Columns: []string{"name", "age"}},
+			{Name: "uk_phone", Columns: []string{"phone"}, Unique: true},
+		},
+	}
+
+	tcs := []struct {
+		name     string
+		col      string
+		wantTags []string
+	}{
+		{name: "unnamed unique", col: "email", wantTags: []string{"unique"}},
+		{name: "first index of column", col: "name", wantTags: []string{"index"}},
+		{name: "composite index", col: "age", wantTags: []string{"index=idx_name_age"}},
+		{name: "named unique", col: "phone", wantTags: []string{"unique=uk_phone"}},
+		{name: "without index", col: "id"},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.wantTags, indexTags(table, tc.col))
+		})
+	}
+}
+
+func TestGoName(t *testing.T) {
+	tcs := []struct {
+		name     string
+		sqlName  string
+		wantName string
+	}{
+		{name: "snake case", sqlName: "user_info", wantName: "UserInfo"},
+		{name: "single word", sqlName: "user", wantName: "User"},
+		{name: "camel case", sqlName: "userInfo", wantName: "UserInfo"},
+		{name: "separators", sqlName: "user-info.v2", wantName: "UserInfoV2"},
+		{name: "leading digit", sqlName: "2fa_code", wantName: "T2faCode"},
+		{name: "empty", sqlName: "__", wantName: "T"},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.wantName, goName(tc.sqlName))
+		})
+	}
+}
+
+func TestValidTagValue(t *testing.T) {
+	tcs := []struct {
+		name  string
+		val   string
+		valid bool
+	}{
+		{name: "number", val: "0", valid: true},
+		{name: "quoted", val: "'active'", valid: true},
+		{name: "quoted with equal", val: "'a=b'", valid: true},
+		{name: "function", val: "CURRENT_TIMESTAMP", valid: true},
+		{name: "equal", val: "a=b"},
+		{name: "double quote", val: `"active"`},
+		{name: "back quote", val: "`active`"},
+		{name: "new line", val: "a\nb"},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.valid, validTagValue(tc.val))
+		})
+	}
+}
+
+func TestReadTables(t *testing.T) {
+	_, err := readTables(reverseOpts{dialect: "sqlite", dsn: "file:test.db"})
+	assert.EqualError(t, err, "unsupported dialect to connect: sqlite, generate from DDL file by -ddl instead")
+}
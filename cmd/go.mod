@@ -0,0 +1,19 @@
+module github.com/JrMarcco/easy-orm/cmd
+
+go 1.24
+
+require (
+	github.com/JrMarcco/easy-orm v0.0.0
+	github.com/go-sql-driver/mysql v1.9.3
+	github.com/lib/pq v1.9.0
+	github.com/stretchr/testify v1.10.0
+)
+
+require (
+	filippo.io/edwards25519 v1.1.0 // indirect
+	github.com/davecgh/go-spew v1.1.1 // indirect
+	github.com/pmezard/go-difflib v1.0.0 // indirect
+	gopkg.in/yaml.v3 v3.0.1 // indirect
+)
+
+replace github.com/JrMarcco/easy-orm => ../
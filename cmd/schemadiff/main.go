@@ -0,0 +1,95 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"time"
+
+	easyorm "github.com/JrMarcco/easy-orm"
+	"github.com/JrMarcco/easy-orm/model"
+	"github.com/JrMarcco/easy-orm/schema"
+	_ "github.com/go-sql-driver/mysql"
+	_ "github.com/lib/pq"
+)
+
+// dialects the dialects supported by the command and the name of their drivers.
+var dialects = map[string]struct {
+	driver  string
+	dialect easyorm.Dialect
+}{
+	"mysql":    {driver: "mysql", dialect: easyorm.MySQLDialect},
+	"postgres": {driver: "postgres", dialect: easyorm.PostgresDialect},
+}
+
+// schemadiff prints the statements migrating the database to the models declared in go files, like:
+//
+//	schemadiff -dialect mysql -dsn "user:password@tcp(127.0.0.1:3306)/db" model.go
+//
+// every struct in the files is a model, whose table is named after the struct like "user_info" of UserInfo.
+func main() {
+	dialectName := flag.String("dialect", "mysql", "dialect of database, mysql or postgres")
+	dsn := flag.String("dsn", "", "data source name of database")
+	timeout := flag.Duration("timeout", 30*time.Second, "timeout of reading database")
+	drop := flag.Bool("drop", false, "drop the columns not in models")
+	dropIndexes := flag.Bool("drop-indexes", false, "drop the indexes not in models")
+	flag.Parse()
+
+	if err := run(*dialectName, *dsn, *timeout, *drop, *dropIndexes, flag.Args()); err != nil {
+		_, _ = fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+}
+
+func run(dialectName string, dsn string, timeout time.Duration, drop bool, dropIndexes bool, srcFiles []string) error {
+	d, ok := dialects[dialectName]
+	if !ok {
+		return fmt.Errorf("unsupported dialect: %s", dialectName)
+	}
+	if dsn == "" || len(srcFiles) == 0 {
+		return fmt.Errorf("usage: schemadiff -dialect mysql -dsn <dsn> <model.go>...")
+	}
+
+	db, err := easyorm.Open(d.driver, dsn, d.dialect)
+	if err != nil {
+		return err
+	}
+
+	var entities []any
+	for _, src := range srcFiles {
+		structs, err := parseStructs(src)
+		if err != nil {
+			return err
+		}
+
+		for _, s := range structs {
+			entity := s.entity()
+			if _, err = db.Registry().RegisterModel(entity, model.WithTableOpt(model.TableNameOf(s.name))); err != nil {
+				return fmt.Errorf("%s: %w", s.name, err)
+			}
+			entities = append(entities, entity)
+		}
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	var opts []schema.AlterOpt
+	if drop {
+		opts = append(opts, schema.AlterWithDropColumns())
+	}
+	if dropIndexes {
+		opts = append(opts, schema.AlterWithDropIndexes())
+	}
+
+	stmts, err := schema.Diff(ctx, db, entities, opts...)
+	if err != nil {
+		return err
+	}
+
+	for _, stmt := range stmts {
+		fmt.Println(stmt)
+	}
+	return nil
+}
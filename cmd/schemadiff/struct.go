@@ -0,0 +1,161 @@
+package main
+
+import (
+	"database/sql"
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// goTypes the types of fields supported, keyed by the type name qualified by import path.
+var goTypes = map[string]reflect.Type{
+	"bool":    reflect.TypeOf(false),
+	"string":  reflect.TypeOf(""),
+	"int":     reflect.TypeOf(0),
+	"int8":    reflect.TypeOf(int8(0)),
+	"int16":   reflect.TypeOf(int16(0)),
+	"int32":   reflect.TypeOf(int32(0)),
+	"int64":   reflect.TypeOf(int64(0)),
+	"uint":    reflect.TypeOf(uint(0)),
+	"uint8":   reflect.TypeOf(uint8(0)),
+	"uint16":  reflect.TypeOf(uint16(0)),
+	"uint32":  reflect.TypeOf(uint32(0)),
+	"uint64":  reflect.TypeOf(uint64(0)),
+	"float32": reflect.TypeOf(float32(0)),
+	"float64": reflect.TypeOf(float64(0)),
+	"byte":    reflect.TypeOf(byte(0)),
+	"rune":    reflect.TypeOf(rune(0)),
+
+	"time.Time": reflect.TypeOf(time.Time{}),
+
+	"database/sql.NullBool":    reflect.TypeOf(sql.NullBool{}),
+	"database/sql.NullByte":    reflect.TypeOf(sql.NullByte{}),
+	"database/sql.NullFloat64": reflect.TypeOf(sql.NullFloat64{}),
+	"database/sql.NullInt16":   reflect.TypeOf(sql.NullInt16{}),
+	"database/sql.NullInt32":   reflect.TypeOf(sql.NullInt32{}),
+	"database/sql.NullInt64":   reflect.TypeOf(sql.NullInt64{}),
+	"database/sql.NullString":  reflect.TypeOf(sql.NullString{}),
+	"database/sql.NullTime":    reflect.TypeOf(sql.NullTime{}),
+}
+
+// structType the struct declared in source file.
+type structType struct {
+	name   string
+	fields []reflect.StructField
+}
+
+// entity returns the pointer to struct built from the fields declared, whose model is the same as the declared one.
+func (s structType) entity() any {
+	return reflect.New(reflect.StructOf(s.fields)).Interface()
+}
+
+// parseStructs parse the structs declared in source file.
+func parseStructs(srcFile string) ([]structType, error) {
+	fs := token.NewFileSet()
+	f, err := parser.ParseFile(fs, srcFile, nil, parser.SkipObjectResolution)
+	if err != nil {
+		return nil, err
+	}
+
+	// import name -> import path
+	imports := make(map[string]string, len(f.Imports))
+	for _, spec := range f.Imports {
+		path, _ := strconv.Unquote(spec.Path.Value)
+
+		name := path[strings.LastIndexByte(path, '/')+1:]
+		if spec.Name != nil {
+			name = spec.Name.Name
+		}
+		imports[name] = path
+	}
+
+	var structs []structType
+	for _, decl := range f.Decls {
+		genDecl, ok := decl.(*ast.GenDecl)
+		if !ok || genDecl.Tok != token.TYPE {
+			continue
+		}
+
+		for _, spec := range genDecl.Specs {
+			typeSpec := spec.(*ast.TypeSpec)
+			st, ok := typeSpec.Type.(*ast.StructType)
+			if !ok || typeSpec.TypeParams != nil {
+				continue
+			}
+
+			s := structType{name: typeSpec.Name.Name}
+			for _, field := range st.Fields.List {
+				typ, err := resolveType(field.Type, imports)
+				if err != nil {
+					return nil, fmt.Errorf("%s: %w", s.name, err)
+				}
+
+				var tag reflect.StructTag
+				if field.Tag != nil {
+					val, _ := strconv.Unquote(field.Tag.Value)
+					tag = reflect.StructTag(val)
+				}
+
+				if len(field.Names) == 0 {
+					return nil, fmt.Errorf("%s: unsupported embedded field %s", s.name, exprString(field.Type))
+				}
+				for _, name := range field.Names {
+					if !name.IsExported() {
+						return nil, fmt.Errorf("%s: unsupported unexported field %s", s.name, name.Name)
+					}
+					s.fields = append(s.fields, reflect.StructField{Name: name.Name, Type: typ, Tag: tag})
+				}
+			}
+			structs = append(structs, s)
+		}
+	}
+	return structs, nil
+}
+
+// resolveType resolve the type of field expression.
+func resolveType(expr ast.Expr, imports map[string]string) (reflect.Type, error) {
+	switch e := expr.(type) {
+	case *ast.StarExpr:
+		typ, err := resolveType(e.X, imports)
+		if err != nil {
+			return nil, err
+		}
+		return reflect.PointerTo(typ), nil
+	case *ast.ArrayType:
+		if e.Len == nil {
+			if elem, ok := e.Elt.(*ast.Ident); ok && (elem.Name == "byte" || elem.Name == "uint8") {
+				return reflect.TypeOf([]byte(nil)), nil
+			}
+		}
+	case *ast.Ident:
+		if typ, ok := goTypes[e.Name]; ok {
+			return typ, nil
+		}
+	case *ast.SelectorExpr:
+		if pkg, ok := e.X.(*ast.Ident); ok {
+			if typ, ok := goTypes[imports[pkg.Name]+"."+e.Sel.Name]; ok {
+				return typ, nil
+			}
+		}
+	}
+	return nil, fmt.Errorf("unsupported field type %s", exprString(expr))
+}
+
+func exprString(expr ast.Expr) string {
+	switch e := expr.(type) {
+	case *ast.Ident:
+		return e.Name
+	case *ast.StarExpr:
+		return "*" + exprString(e.X)
+	case *ast.ArrayType:
+		return "[]" + exprString(e.Elt)
+	case *ast.SelectorExpr:
+		return exprString(e.X) + "." + e.Sel.Name
+	}
+	return fmt.Sprintf("%T", expr)
+}
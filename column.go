@@ -76,6 +76,20 @@ func (c Column) In(vals ...any) Predicate {
 	}
 }
 
+func (c Column) IsNull() Predicate {
+	return Predicate{
+		left: c,
+		op:   opIsNull,
+	}
+}
+
+func (c Column) IsNotNull() Predicate {
+	return Predicate{
+		left: c,
+		op:   opIsNotNull,
+	}
+}
+
 func (c Column) InSubQuery(subQuery SubQuery) Predicate {
 	return Predicate{
 		left:  c,
@@ -84,6 +98,38 @@ func (c Column) InSubQuery(subQuery SubQuery) Predicate {
 	}
 }
 
+func (c Column) Add(val any) MathExpr {
+	return MathExpr{
+		left:  c,
+		op:    opAdd,
+		right: valueOf(val),
+	}
+}
+
+func (c Column) Sub(val any) MathExpr {
+	return MathExpr{
+		left:  c,
+		op:    opSub,
+		right: valueOf(val),
+	}
+}
+
+func (c Column) Mul(val any) MathExpr {
+	return MathExpr{
+		left:  c,
+		op:    opMul,
+		right: valueOf(val),
+	}
+}
+
+func (c Column) Div(val any) MathExpr {
+	return MathExpr{
+		left:  c,
+		op:    opDiv,
+		right: valueOf(val),
+	}
+}
+
 // Col create a column expression.
 //
 // fieldName is the field name of the model.
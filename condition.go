@@ -49,9 +49,16 @@ const (
 	opIn        op = "IN"
 	opExists    op = "EXISTS"
 	opNotExists op = "NOT EXISTS"
+	opIsNull    op = "IS NULL"
+	opIsNotNull op = "IS NOT NULL"
 	opAll       op = "ALL"
 	opAny       op = "ANY"
 	opSome      op = "SOME"
+
+	opAdd op = "+"
+	opSub op = "-"
+	opMul op = "*"
+	opDiv op = "/"
 )
 
 var _ Expr = (*Predicate)(nil)
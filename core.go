@@ -3,6 +3,8 @@ package easyorm
 import (
 	"context"
 	"database/sql"
+	"sync"
+	"time"
 
 	"github.com/JrMarcco/easy-orm/internal/errs"
 	"github.com/JrMarcco/easy-orm/internal/value"
@@ -15,15 +17,32 @@ type core struct {
 	resolverCreator value.ResolverCreator
 
 	middlewareChain MiddlewareChain
+
+	// schemaFunc resolve the schema of tables from context, see DBWithSchemaFunc.
+	schemaFunc func(ctx context.Context) string
+	// clock the current time of auto time fields and soft delete, see DBWithClock.
+	clock func() time.Time
+	// snapshots the snapshots of entities tracked, keyed by the weak pointer of entity, see Selector.Track.
+	snapshots sync.Map
+}
+
+// now returns the current time of clock, time.Now if no clock set.
+func (c *core) now() time.Time {
+	if c.clock == nil {
+		return time.Now()
+	}
+	return c.clock()
 }
 
 func findOneHF[T any](ctx context.Context, ormCtx *OrmContext, orm orm) *OrmResult {
+	orm = ormOf(ctx, orm)
+
 	statement, err := ormCtx.Builder.Build()
 	if err != nil {
 		return &OrmResult{Err: err}
 	}
 
-	rows, err := orm.queryContext(ctx, statement.SQL, statement.Args...)
+	rows, err := orm.readContext(ctx, statement.SQL, statement.Args...)
 	if err != nil {
 		return &OrmResult{Err: err}
 	}
@@ -43,6 +62,9 @@ func findOneHF[T any](ctx context.Context, ormCtx *OrmContext, orm orm) *OrmResu
 		return &OrmResult{Err: err}
 	}
 
+	if err = runHooks(ctx, m, model.HookAfterFind, res); err != nil {
+		return &OrmResult{Err: err}
+	}
 	return &OrmResult{Res: res}
 }
 
@@ -64,12 +86,14 @@ func findOne[T any](ctx context.Context, ormCtx *OrmContext, orm orm) (*T, error
 }
 
 func findMultiHF[T any](ctx context.Context, ormCtx *OrmContext, orm orm) *OrmResult {
+	orm = ormOf(ctx, orm)
+
 	statement, err := ormCtx.Builder.Build()
 	if err != nil {
 		return &OrmResult{Err: err}
 	}
 
-	rows, err := orm.queryContext(ctx, statement.SQL, statement.Args...)
+	rows, err := orm.readContext(ctx, statement.SQL, statement.Args...)
 	if err != nil {
 		return &OrmResult{Err: err}
 	}
@@ -90,6 +114,9 @@ func findMultiHF[T any](ctx context.Context, ormCtx *OrmContext, orm orm) *OrmRe
 		res = append(res, v)
 	}
 
+	if err = runHooks(ctx, m, model.HookAfterFind, res...); err != nil {
+		return &OrmResult{Err: err}
+	}
 	return &OrmResult{Res: res}
 }
 
@@ -111,6 +138,8 @@ func findMulti[T any](ctx context.Context, ormCtx *OrmContext, orm orm) ([]*T, e
 }
 
 func execHF(ctx context.Context, statementCtx *OrmContext, orm orm) *OrmResult {
+	orm = ormOf(ctx, orm)
+
 	statement, err := statementCtx.Builder.Build()
 	if err != nil {
 		return &OrmResult{Err: err}
@@ -144,6 +173,71 @@ func exec(ctx context.Context, ormCtx *OrmContext, orm orm) Result {
 	}
 }
 
+// returningHF execute the statement with "RETURNING" clause,
+// and write the returned columns back into the entities in order.
+func returningHF[T any](ctx context.Context, ormCtx *OrmContext, orm orm, entities []*T) *OrmResult {
+	orm = ormOf(ctx, orm)
+
+	statement, err := ormCtx.Builder.Build()
+	if err != nil {
+		return &OrmResult{Err: err}
+	}
+
+	rows, err := orm.queryContext(ctx, statement.SQL, statement.Args...)
+	if err != nil {
+		return &OrmResult{Err: err}
+	}
+	defer func() {
+		_ = rows.Close()
+	}()
+
+	var affected int64
+	for rows.Next() {
+		if int(affected) < len(entities) {
+			resolver := orm.getCore().resolverCreator(ormCtx.Model, entities[affected])
+			if err = resolver.WriteColumns(rows); err != nil {
+				return &OrmResult{Err: err}
+			}
+		}
+		affected++
+	}
+
+	if err = rows.Err(); err != nil {
+		return &OrmResult{Err: err}
+	}
+	return &OrmResult{Res: returningResult(affected)}
+}
+
+func execReturning[T any](ctx context.Context, ormCtx *OrmContext, orm orm, entities []*T) Result {
+	handleFunc := func(innerCtx context.Context, innerOrmCtx *OrmContext) *OrmResult {
+		return returningHF[T](innerCtx, innerOrmCtx, orm, entities)
+	}
+
+	c := orm.getCore()
+	for i := len(c.middlewareChain) - 1; i >= 0; i-- {
+		handleFunc = c.middlewareChain[i](handleFunc)
+	}
+
+	sr := handleFunc(ctx, ormCtx)
+	if sr.Res == nil {
+		return Result{err: sr.Err}
+	}
+
+	return Result{
+		res: sr.Res.(sql.Result),
+	}
+}
+
+// handle run the handle function wrapped by middleware chain.
+func (c *core) handle(ctx context.Context, ormCtx *OrmContext, handleFunc HandleFunc) *OrmResult {
+	for i := len(c.middlewareChain) - 1; i >= 0; i-- {
+		handleFunc = c.middlewareChain[i](handleFunc)
+	}
+	return handleFunc(ctx, ormCtx)
+}
+
+// OrmContext the context of operation passed through middleware chain.
+// Model is nil for raw queries and transaction operations.
 type OrmContext struct {
 	Typ     string
 	Model   *model.Model
@@ -17,53 +17,164 @@ import (
 type DB struct {
 	*core
 	sqlDB *sql.DB
+
+	// replicas read replicas of sqlDB, reads are routed to them by balancer.
+	replicas []*sql.DB
+	balancer LoadBalancer
 }
 
 func (db *DB) getCore() *core {
 	return db.core
 }
 
+// Dialect returns the dialect of DB.
+func (db *DB) Dialect() Dialect {
+	return db.dialect
+}
+
+// Registry returns the model registry of DB, models registered on it are used by builders created on DB.
+func (db *DB) Registry() model.Registry {
+	return db.registry
+}
+
 func (db *DB) queryContext(ctx context.Context, sql string, args ...any) (*sql.Rows, error) {
 	return db.sqlDB.QueryContext(ctx, sql, args...)
 }
 
+func (db *DB) readContext(ctx context.Context, sql string, args ...any) (*sql.Rows, error) {
+	return db.replicaOf(ctx).QueryContext(ctx, sql, args...)
+}
+
 func (db *DB) execContext(ctx context.Context, sql string, args ...any) (sql.Result, error) {
 	return db.sqlDB.ExecContext(ctx, sql, args...)
 }
 
+// beginTx begin a transaction through middleware chain,
+// the context passed to the handle function is kept by transaction for committing and rolling back.
 func (db *DB) beginTx(ctx context.Context, opts *sql.TxOptions) (*Tx, error) {
-	sqlTx, err := db.sqlDB.BeginTx(ctx, opts)
-	if err != nil {
-		return nil, err
+	tx := &Tx{core: db.core}
+
+	res := db.handle(ctx, &OrmContext{
+		Typ:     ScTypBEGIN,
+		Builder: txStatement(ScTypBEGIN),
+	}, func(ctx context.Context, _ *OrmContext) *OrmResult {
+		sqlTx, err := db.sqlDB.BeginTx(ctx, opts)
+		if err != nil {
+			return &OrmResult{Err: err}
+		}
+
+		tx.ctx = ctx
+		tx.sqlTx = sqlTx
+		return &OrmResult{Res: sqlTx}
+	})
+	if res.Err != nil {
+		return nil, res.Err
+	}
+	return tx, nil
+}
+
+// DoTx run the business function in a transaction.
+// The transaction is stored in the context passed to business function,
+// builders created on DB execute on it when executed with that context, see WithoutTx for opting out.
+// If the context already carries a transaction of DB, the business function runs in a nested transaction
+// backed by savepoint, opts and txOpts are ignored.
+//
+// With TxWithRetry, the business function is re-run in a fresh transaction when the transaction fails
+// with a retryable error, so it must be safe to re-run.
+func (db *DB) DoTx(
+	ctx context.Context, bizFunc func(ctx context.Context, tx *Tx) error, opts *sql.TxOptions, txOpts ...TxOpt,
+) error {
+	if tx, ok := db.txFromContext(ctx); ok {
+		return tx.DoTx(ctx, bizFunc)
+	}
+
+	options := &txOptions{
+		maxAttempts: 1,
+		baseDelay:   10 * time.Millisecond,
+		maxDelay:    time.Second,
+		retryable:   db.dialect.retryable,
 	}
+	for _, opt := range txOpts {
+		opt(options)
+	}
+
+	for attempt := 1; ; attempt++ {
+		txErr := db.doTx(ctx, bizFunc, opts)
+		if txErr == nil {
+			return nil
+		}
+
+		err := txErr.error
+		if attempt >= options.maxAttempts || !options.retryable(txErr.cause) {
+			return err
+		}
+
+		if sleepErr := sleepContext(ctx, options.backoff(attempt)); sleepErr != nil {
+			return err
+		}
+	}
+}
 
-	return &Tx{
-		core:  db.core,
-		sqlTx: sqlTx,
-	}, nil
+// txError the error of transaction with its cause,
+// the original error of beginning, business function or committing deciding whether to retry.
+type txError struct {
+	error
+	cause error
 }
 
-func (db *DB) DoTx(ctx context.Context, bizFunc func(ctx context.Context, tx *Tx) error, opts *sql.TxOptions) (err error) {
+// doTx run the business function in a transaction once, then run the hooks of transaction,
+// nil if the transaction is committed and hooks succeeded.
+func (db *DB) doTx(
+	ctx context.Context, bizFunc func(ctx context.Context, tx *Tx) error, opts *sql.TxOptions,
+) (txErr *txError) {
 	tx, err := db.beginTx(ctx, opts)
 	if err != nil {
-		return err
+		return &txError{error: err, cause: err}
 	}
 
 	panicked := true
 	defer func() {
+		var cause error
 		if panicked || err != nil {
+			cause = err
 			rbErr := tx.Rollback()
 			err = errs.ErrRollback(err, rbErr, panicked)
-			return
+		} else if err = tx.Commit(); err != nil {
+			cause = err
 		}
 
-		err = tx.Commit()
+		// the transaction is finished, hooks run without it,
+		// rollback hooks of nested transactions run first as they were rolled back earlier.
+		hookErr := runCommitHooks(ctx, tx.nestedRollback)
+		if err != nil {
+			hookErr = errors.Join(hookErr, runRollbackHooks(ctx, tx.onRollback, err))
+		} else {
+			hookErr = errors.Join(hookErr, runCommitHooks(ctx, tx.onCommit))
+		}
+
+		if hookErr != nil {
+			err = errors.Join(err, hookErr)
+		}
+
+		if err != nil {
+			txErr = &txError{error: err, cause: cause}
+		}
 	}()
 
-	err = bizFunc(ctx, tx)
+	err = bizFunc(contextWithTx(tx.ctx, tx), tx)
 	panicked = false
 
-	return err
+	return nil
+}
+
+// txFromContext returns the transaction started by DB in context.
+func (db *DB) txFromContext(ctx context.Context) (*Tx, bool) {
+	if bypass, _ := ctx.Value(bypassTxKey{}).(bool); bypass {
+		return nil, false
+	}
+
+	tx, ok := ctx.Value(txKey{core: db.core}).(*Tx)
+	return tx, ok
 }
 
 func (db *DB) Wait() error {
@@ -96,6 +207,36 @@ func DBWithMiddlewareChain(middlewareChain MiddlewareChain) DBOpt {
 	}
 }
 
+// DBWithSchemaFunc set the function resolving the schema of tables from context,
+// e.g. mapping the tenant in context to its schema, default is SchemaFromContext.
+func DBWithSchemaFunc(schemaFunc func(ctx context.Context) string) DBOpt {
+	return func(db *DB) {
+		db.schemaFunc = schemaFunc
+	}
+}
+
+// DBWithReplicas route reads of Selector and Raw to replicas,
+// writes and transactions are always executed on primary, see WithPrimary for forcing reads to primary.
+func DBWithReplicas(replicas ...*sql.DB) DBOpt {
+	return func(db *DB) {
+		db.replicas = append(db.replicas, replicas...)
+	}
+}
+
+// DBWithLoadBalancer set the load balancer choosing replica for reads, default is RoundRobinBalancer.
+func DBWithLoadBalancer(balancer LoadBalancer) DBOpt {
+	return func(db *DB) {
+		db.balancer = balancer
+	}
+}
+
+// DBWithClock set the clock filling auto time fields and marking rows soft deleted, default is time.Now.
+func DBWithClock(clock func() time.Time) DBOpt {
+	return func(db *DB) {
+		db.clock = clock
+	}
+}
+
 func Open(driverName string, dsn string, dialect Dialect, opts ...DBOpt) (*DB, error) {
 	sqlDB, err := sql.Open(driverName, dsn)
 	if err != nil {
@@ -112,8 +253,9 @@ func OpenDB(sqlDB *sql.DB, dialect Dialect, opts ...DBOpt) (*DB, error) {
 	}
 
 	db := &DB{
-		core:  core,
-		sqlDB: sqlDB,
+		core:     core,
+		sqlDB:    sqlDB,
+		balancer: RoundRobinBalancer(),
 	}
 
 	db.dialect = dialect
@@ -2,6 +2,8 @@ package easyorm
 
 import (
 	"context"
+
+	"github.com/JrMarcco/easy-orm/model"
 )
 
 var _ Executor[any] = (*Deleter[any])(nil)
@@ -9,15 +11,43 @@ var _ Executor[any] = (*Deleter[any])(nil)
 type Deleter[T any] struct {
 	builder
 
-	orm   orm
-	where []Condition
+	orm      orm
+	entities []*T
+	where    []Condition
+	scopes   []Predicate
+	// unscoped deletes the rows physically even if the model is soft deleted.
+	unscoped bool
 }
 
+// Exec executes the delete statement,
+// BeforeDelete and AfterDelete hooks are invoked on the entities deleted by Delete, and skipped if deleted by condition only.
 func (d *Deleter[T]) Exec(ctx context.Context) Result {
+	if len(d.entities) == 0 {
+		return d.execute(ctx)
+	}
+
+	if d.model == nil {
+		if err := d.initModel(); err != nil {
+			return Result{err: err}
+		}
+	}
+	return execWithHooks(ctx, d.model, model.HookBeforeDelete, model.HookAfterDelete, d.entities, func() Result {
+		return d.execute(ctx)
+	})
+}
+
+func (d *Deleter[T]) execute(ctx context.Context) Result {
+	d.scopes = nil
+	d.tableScope = nil
+	d.schema = d.orm.getCore().schemaOf(ctx)
 	if err := d.initModel(); err != nil {
 		return Result{err: err}
 	}
 
+	if sdb, ok := d.orm.(*ShardingDB); ok {
+		return d.execSharding(ctx, sdb)
+	}
+
 	return exec(ctx, &OrmContext{
 		Typ:     ScTypDELETE,
 		Model:   d.model,
@@ -26,11 +56,21 @@ func (d *Deleter[T]) Exec(ctx context.Context) Result {
 }
 
 func (d *Deleter[T]) initModel() error {
+	if d.model != nil {
+		return nil
+	}
+
 	var err error
 	d.model, err = d.orm.getCore().registry.GetModel(new(T))
 	return err
 }
 
+// Delete deletes the rows of entities by their primary key, restricted by Where as well if any.
+func (d *Deleter[T]) Delete(entities ...*T) *Deleter[T] {
+	d.entities = entities
+	return d
+}
+
 func (d *Deleter[T]) Where(pds ...Predicate) *Deleter[T] {
 	if len(pds) == 0 {
 		return d
@@ -52,24 +92,44 @@ func (d *Deleter[T]) Build() (*Statement, error) {
 		}
 	}
 
-	d.sqlBuffer.WriteString("DELETE FROM ")
-	d.writeTable()
+	d.reset()
+
+	scopes, err := d.modelScopes(d.scopes)
+	if err != nil {
+		return nil, err
+	}
+	if len(d.entities) > 0 {
+		pd, err := primaryKeyOf(d.orm.getCore(), d.model, d.entities)
+		if err != nil {
+			return nil, err
+		}
+		scopes = append([]Predicate{pd}, scopes...)
+	}
 
-	if d.where != nil {
-		if err = d.buildCondition(); err != nil {
+	if d.model.SoftDelete != nil && !d.unscoped {
+		if err = d.buildSoftDelete(scopes); err != nil {
 			return nil, err
 		}
+	} else {
+		d.sqlBuffer.WriteString("DELETE FROM ")
+		d.writeTable()
+
+		if where := scopedWhere(d.where, scopes); len(where) > 0 {
+			if err = d.buildCondition(where); err != nil {
+				return nil, err
+			}
+		}
 	}
 
-	d.sqlBuffer.WriteByte(';')
+	d.writeTerminator()
 	return &Statement{
 		SQL:  d.sqlBuffer.String(),
 		Args: d.args,
 	}, nil
 }
 
-func (d *Deleter[T]) buildCondition() error {
-	for _, c := range d.where {
+func (d *Deleter[T]) buildCondition(where []Condition) error {
+	for _, c := range where {
 		d.sqlBuffer.WriteString(c.typ.String())
 		if err := d.buildExpr(c.expr); err != nil {
 			return err
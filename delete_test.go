@@ -55,6 +55,20 @@ func TestDeleter_Build(t *testing.T) {
 				SQL:  "DELETE FROM `delete_test_model` WHERE NOT (`id` = ?);",
 				Args: []any{1},
 			},
+		}, {
+			name:    "entities",
+			deleter: NewDeleter[deleteTestModel](db).Delete(&deleteTestModel{Id: 1}, &deleteTestModel{Id: 2}),
+			wantRes: &Statement{
+				SQL:  "DELETE FROM `delete_test_model` WHERE `id` IN (?,?);",
+				Args: []any{uint64(1), uint64(2)},
+			},
+		}, {
+			name:    "entities with where",
+			deleter: NewDeleter[deleteTestModel](db).Delete(&deleteTestModel{Id: 1}).Where(Col("Age").Lt(18)),
+			wantRes: &Statement{
+				SQL:  "DELETE FROM `delete_test_model` WHERE (`age` < ?) AND (`id` = ?);",
+				Args: []any{18, uint64(1)},
+			},
 		}, {
 			name:    "with invalid where",
 			deleter: NewDeleter[deleteTestModel](db).Where(Col("ID").Eq(1)),
@@ -4,41 +4,136 @@ import (
 	"strconv"
 
 	"github.com/JrMarcco/easy-orm/internal/errs"
+	"github.com/JrMarcco/easy-orm/model"
 )
 
 var (
 	StandardSQL     = standardSQL{}
 	PostgresDialect = postgres{}
-	MySQLDialect    = mysql{}
+	MySQLDialect    = mysql{rowAlias: "new"}
+	// MySQLLegacyDialect mysql before 8.0.19 and mariadb, refer the values proposed for insertion by "VALUES(col)".
+	MySQLLegacyDialect = mysql{}
+	SQLiteDialect      = sqlite{}
+	SQLServerDialect   = sqlServer{}
+	OracleDialect      = oracle{}
 )
 
 type Dialect interface {
-	quote() byte
+	// quote the opening and closing quote of identifier.
+	quote() (byte, byte)
 	bindArg(b *builder)
+	// tableAliasKeyword the keyword between table and its alias, like " AS ".
+	tableAliasKeyword() string
+	// terminator the terminator at the end of statement.
+	terminator() string
+	// insertInto write the beginning of insert statement, like "INSERT INTO ".
+	insertInto(b *builder, conflict *Conflict)
 	onConflict(b *builder, conflict *Conflict) error
+	// excluded write the reference to the value proposed for insertion in an upsert.
+	excluded(b *builder, fieldName string) error
+	// returning write the "RETURNING" clause.
+	returning(b *builder, fields []string) error
+	// paginate write the pagination clause, limit or offset less than or equal to 0 means not set.
+	// ordered reports whether the statement has "ORDER BY" clause.
+	paginate(b *builder, limit int64, offset int64, ordered bool)
+	// maxArgs the maximum number of bind arguments a single statement can carry.
+	maxArgs() int
+	// savepoint the statements to create, rollback to and release the savepoint,
+	// empty release means releasing savepoint is not supported.
+	savepoint(name string) (create string, rollback string, release string)
+	// retryable whether the transaction failed with the error can succeed by retrying,
+	// like serialization failure or deadlock.
+	retryable(err error) bool
+	// nullsFirst whether NULL is sorted before other values in ascending order, and after them in descending order.
+	nullsFirst() bool
 }
 
 type Conflict struct {
-	conflicts []string // conflict fields
-	assigns   []Assignable
+	conflicts  []string    // conflict fields
+	constraint string      // conflict constraint name
+	where      []Predicate // predicate of partial unique index
+	assigns    []Assignable
+	updateIf   []Predicate // condition of "DO UPDATE ... WHERE"
+
+	fromSelect bool // insert rows from select statement
+}
+
+// doNothing whether to ignore the conflicted rows.
+func (c *Conflict) doNothing() bool {
+	return len(c.assigns) == 0
 }
 
 var _ Dialect = (*standardSQL)(nil)
 
 type standardSQL struct{}
 
-func (s standardSQL) quote() byte {
-	return '"'
+func (s standardSQL) quote() (byte, byte) {
+	return '"', '"'
 }
 
 func (s standardSQL) bindArg(b *builder) {
 	b.sqlBuffer.WriteByte('?')
 }
 
+func (s standardSQL) tableAliasKeyword() string {
+	return " AS "
+}
+
+func (s standardSQL) terminator() string {
+	return ";"
+}
+
+func (s standardSQL) insertInto(b *builder, _ *Conflict) {
+	b.sqlBuffer.WriteString("INSERT INTO ")
+}
+
 func (s standardSQL) onConflict(_ *builder, _ *Conflict) error {
 	return errs.ErrUnsupportedOnConflict
 }
 
+func (s standardSQL) excluded(_ *builder, _ string) error {
+	return errs.ErrUnsupportedOnConflict
+}
+
+func (s standardSQL) returning(_ *builder, _ []string) error {
+	return errs.ErrUnsupportedReturning
+}
+
+func (s standardSQL) paginate(b *builder, limit int64, offset int64, _ bool) {
+	if limit > 0 {
+		b.sqlBuffer.WriteString(" LIMIT ")
+		b.sqlBuffer.WriteString(strconv.FormatInt(limit, 10))
+	}
+
+	if offset > 0 {
+		b.sqlBuffer.WriteString(" OFFSET ")
+		b.sqlBuffer.WriteString(strconv.FormatInt(offset, 10))
+	}
+}
+
+func (s standardSQL) maxArgs() int {
+	return 65535
+}
+
+func (s standardSQL) savepoint(name string) (string, string, string) {
+	return "SAVEPOINT " + name, "ROLLBACK TO SAVEPOINT " + name, "RELEASE SAVEPOINT " + name
+}
+
+// nullsFirst NULL is larger than any other value like postgres and oracle.
+func (s standardSQL) nullsFirst() bool {
+	return false
+}
+
+// retryable SQLSTATE 40001 serialization failure and 40P01 deadlock detected.
+func (s standardSQL) retryable(err error) bool {
+	switch sqlStateOf(err) {
+	case "40001", "40P01":
+		return true
+	default:
+		return false
+	}
+}
+
 var _ Dialect = (*postgres)(nil)
 
 type postgres struct {
@@ -51,51 +146,75 @@ func (p postgres) bindArg(b *builder) {
 }
 
 func (p postgres) onConflict(b *builder, conflict *Conflict) error {
-	b.sqlBuffer.WriteString(" ON CONFLICT (")
+	b.sqlBuffer.WriteString(" ON CONFLICT")
 
-	for index, c := range conflict.conflicts {
-		if index > 0 {
-			b.sqlBuffer.WriteString(", ")
+	if conflict.constraint != "" {
+		b.sqlBuffer.WriteString(" ON CONSTRAINT ")
+		b.writeWithQuote(conflict.constraint)
+	}
+	return buildConflictClause(b, conflict)
+}
+
+func (p postgres) excluded(b *builder, fieldName string) error {
+	b.sqlBuffer.WriteString("EXCLUDED.")
+	return b.writeField(fieldName)
+}
+
+func (p postgres) returning(b *builder, fields []string) error {
+	return buildReturning(b, fields)
+}
+
+// buildConflictClause build the conflict target and action of "ON CONFLICT" clause.
+// The predicates of partial index are only permitted with the conflict columns.
+func buildConflictClause(b *builder, conflict *Conflict) error {
+	if len(conflict.where) > 0 && (conflict.constraint != "" || len(conflict.conflicts) == 0) {
+		return errs.ErrConflictWhereWithoutCols
+	}
+
+	if conflict.constraint == "" && len(conflict.conflicts) > 0 {
+		b.sqlBuffer.WriteString(" (")
+		for index, c := range conflict.conflicts {
+			if index > 0 {
+				b.sqlBuffer.WriteString(", ")
+			}
+			if err := b.writeField(c); err != nil {
+				return err
+			}
 		}
-		if err := b.writeField(c); err != nil {
+		b.sqlBuffer.WriteByte(')')
+
+		if err := b.buildPredicates(conflict.where); err != nil {
 			return err
 		}
 	}
 
-	b.sqlBuffer.WriteByte(')')
-
-	if len(conflict.assigns) == 0 {
+	if conflict.doNothing() {
 		b.sqlBuffer.WriteString(" DO NOTHING")
 		return nil
 	}
 
+	// the existing row and the excluded row are both visible in "DO UPDATE",
+	// columns of the existing row must be qualified by the table name.
+	b.qualify = true
+	defer func() {
+		b.qualify = false
+	}()
+
 	b.sqlBuffer.WriteString(" DO UPDATE SET ")
-	for index, assign := range conflict.assigns {
+	if err := b.buildAssigns(conflict.assigns); err != nil {
+		return err
+	}
+	return b.buildPredicates(conflict.updateIf)
+}
+
+func buildReturning(b *builder, fields []string) error {
+	b.sqlBuffer.WriteString(" RETURNING ")
+	for index, f := range fields {
 		if index > 0 {
 			b.sqlBuffer.WriteString(", ")
 		}
-
-		switch assignTyp := assign.(type) {
-		case Assignment:
-			if err := b.writeField(assignTyp.filedName); err != nil {
-				return err
-			}
-
-			b.addArgs(assignTyp.value)
-			b.sqlBuffer.WriteString(" = ")
-			b.dialect.bindArg(b)
-		case Column:
-			assignTyp.alias = ""
-			if err := b.buildColumn(assignTyp.tableRef, assignTyp.fieldName); err != nil {
-				return err
-			}
-
-			b.sqlBuffer.WriteString(" = EXCLUDED.")
-			if err := b.writeField(assignTyp.fieldName); err != nil {
-				return err
-			}
-		default:
-			return errs.ErrInvalidAssignable
+		if err := b.writeField(f); err != nil {
+			return err
 		}
 	}
 	return nil
@@ -105,43 +224,443 @@ var _ Dialect = (*mysql)(nil)
 
 type mysql struct {
 	standardSQL
+	// rowAlias alias of the rows proposed for insertion, supported since mysql 8.0.19.
+	rowAlias string
+}
+
+func (m mysql) quote() (byte, byte) {
+	return '`', '`'
 }
 
-func (m mysql) quote() byte {
-	return '`'
+func (m mysql) insertInto(b *builder, conflict *Conflict) {
+	if conflict != nil && conflict.doNothing() {
+		b.sqlBuffer.WriteString("INSERT IGNORE INTO ")
+		return
+	}
+	b.sqlBuffer.WriteString("INSERT INTO ")
 }
 
+// onConflict mysql updates on the conflict of any unique key, the conflict fields are ignored.
 func (m mysql) onConflict(b *builder, conflict *Conflict) error {
+	if conflict.constraint != "" || len(conflict.where) > 0 || len(conflict.updateIf) > 0 {
+		return errs.ErrUnsupportedConflictTarget
+	}
+
+	// ignored by "INSERT IGNORE"
+	if conflict.doNothing() {
+		return nil
+	}
+
+	// row alias is not permitted in "INSERT ... SELECT"
+	if m.rowAlias != "" && !conflict.fromSelect {
+		b.sqlBuffer.WriteString(" AS ")
+		b.writeWithQuote(m.rowAlias)
+		b.rowAlias = m.rowAlias
+	}
+
 	b.sqlBuffer.WriteString(" ON DUPLICATE KEY UPDATE ")
+	return b.buildAssigns(conflict.assigns)
+}
+
+// retryable error 1213 deadlock found when trying to get lock.
+func (m mysql) retryable(err error) bool {
+	if number, ok := errNumberOf(err); ok && number == 1213 {
+		return true
+	}
+	return m.standardSQL.retryable(err)
+}
+
+// nullsFirst NULL is smaller than any other value in mysql.
+func (m mysql) nullsFirst() bool {
+	return true
+}
+
+func (m mysql) excluded(b *builder, fieldName string) error {
+	if b.rowAlias != "" {
+		b.writeWithQuote(b.rowAlias)
+		b.sqlBuffer.WriteByte('.')
+		return b.writeField(fieldName)
+	}
+
+	b.sqlBuffer.WriteString("VALUES(")
+	if err := b.writeField(fieldName); err != nil {
+		return err
+	}
+	b.sqlBuffer.WriteByte(')')
+	return nil
+}
+
+var _ Dialect = (*sqlite)(nil)
+
+type sqlite struct {
+	standardSQL
+}
+
+// onConflict sqlite upsert, "ON CONSTRAINT" is not supported.
+//
+// to avoid the parsing ambiguity, the select statement of "INSERT ... SELECT" must have a "WHERE" clause.
+func (s sqlite) onConflict(b *builder, conflict *Conflict) error {
+	if conflict.constraint != "" {
+		return errs.ErrUnsupportedConflictTarget
+	}
+
+	b.sqlBuffer.WriteString(" ON CONFLICT")
+	return buildConflictClause(b, conflict)
+}
+
+func (s sqlite) excluded(b *builder, fieldName string) error {
+	b.sqlBuffer.WriteString("excluded.")
+	return b.writeField(fieldName)
+}
+
+func (s sqlite) returning(b *builder, fields []string) error {
+	return buildReturning(b, fields)
+}
+
+// paginate sqlite does not support "OFFSET" without "LIMIT", use "LIMIT -1" instead.
+func (s sqlite) paginate(b *builder, limit int64, offset int64, _ bool) {
+	if limit <= 0 && offset > 0 {
+		limit = -1
+	}
+
+	if limit != 0 {
+		b.sqlBuffer.WriteString(" LIMIT ")
+		b.sqlBuffer.WriteString(strconv.FormatInt(limit, 10))
+	}
+
+	if offset > 0 {
+		b.sqlBuffer.WriteString(" OFFSET ")
+		b.sqlBuffer.WriteString(strconv.FormatInt(offset, 10))
+	}
+}
+
+// retryable SQLITE_BUSY and SQLITE_LOCKED.
+func (s sqlite) retryable(err error) bool {
+	code, ok := errNumberOf(err)
+	return ok && (code == 5 || code == 6)
+}
+
+// maxArgs SQLITE_MAX_VARIABLE_NUMBER defaults to 32766 since sqlite 3.32.0.
+func (s sqlite) maxArgs() int {
+	return 32766
+}
+
+// nullsFirst NULL is smaller than any other value in sqlite.
+func (s sqlite) nullsFirst() bool {
+	return true
+}
+
+// merger dialect implements upsert by "MERGE" statement,
+// the rows proposed for insertion are aliased as the row alias of builder.
+type merger interface {
+	// mergeUsing write the source of "USING" clause, rows are the values of fields when query is nil.
+	mergeUsing(b *builder, fields []*model.Field, rows [][]any, query *SubQuery) error
+	// mergeMatched write the "WHEN MATCHED" clause.
+	mergeMatched(b *builder, conflict *Conflict) error
+}
+
+// mergeSourceAlias alias of the rows proposed for insertion in "MERGE" statement.
+const mergeSourceAlias = "source"
+
+// buildMerge build the upsert by "MERGE" statement, like:
+//
+//	MERGE INTO t USING (...) source ON (t.id = source.id)
+//	WHEN MATCHED THEN UPDATE SET ...
+//	WHEN NOT MATCHED THEN INSERT (...) VALUES (...)
+func buildMerge(b *builder, m merger, fields []*model.Field, rows [][]any, query *SubQuery, conflict *Conflict) error {
+	if len(conflict.conflicts) == 0 || conflict.constraint != "" || len(conflict.where) > 0 {
+		return errs.ErrUnsupportedConflictTarget
+	}
+
+	b.sqlBuffer.WriteString("MERGE INTO ")
+	b.writeTable()
+	b.sqlBuffer.WriteString(" USING ")
+
+	b.rowAlias = mergeSourceAlias
+	if err := m.mergeUsing(b, fields, rows, query); err != nil {
+		return err
+	}
+
+	b.sqlBuffer.WriteString(" ON (")
+	for index, c := range conflict.conflicts {
+		if index > 0 {
+			b.sqlBuffer.WriteString(" AND ")
+		}
 
-	for index, assign := range conflict.assigns {
+		b.writeTable()
+		b.sqlBuffer.WriteByte('.')
+		if err := b.writeField(c); err != nil {
+			return err
+		}
+		b.sqlBuffer.WriteString(" = ")
+		if err := b.dialect.excluded(b, c); err != nil {
+			return err
+		}
+	}
+	b.sqlBuffer.WriteByte(')')
+
+	if !conflict.doNothing() {
+		// the existing row and the source row are both visible in "WHEN MATCHED",
+		// columns of the existing row must be qualified by the table name.
+		b.qualify = true
+		err := m.mergeMatched(b, conflict)
+		b.qualify = false
+		if err != nil {
+			return err
+		}
+	}
+
+	b.sqlBuffer.WriteString(" WHEN NOT MATCHED THEN INSERT (")
+	for index, field := range fields {
+		if index > 0 {
+			b.sqlBuffer.WriteString(", ")
+		}
+		b.writeWithQuote(field.ColumnName)
+	}
+	b.sqlBuffer.WriteString(") VALUES (")
+	for index, field := range fields {
 		if index > 0 {
 			b.sqlBuffer.WriteString(", ")
 		}
+		if err := b.dialect.excluded(b, field.FiledName); err != nil {
+			return err
+		}
+	}
+	b.sqlBuffer.WriteByte(')')
+	return nil
+}
 
-		switch assignTyp := assign.(type) {
-		case Assignment:
-			if err := b.writeField(assignTyp.filedName); err != nil {
-				return err
-			}
+// writeExcluded write the column of the rows proposed for insertion by the row alias.
+func writeExcluded(b *builder, fieldName string) error {
+	b.writeWithQuote(b.rowAlias)
+	b.sqlBuffer.WriteByte('.')
+	return b.writeField(fieldName)
+}
 
-			b.addArgs(assignTyp.value)
-			b.sqlBuffer.WriteString(" = ")
-			b.dialect.bindArg(b)
-		case Column:
-			assignTyp.alias = ""
-			if err := b.buildColumn(assignTyp.tableRef, assignTyp.fieldName); err != nil {
-				return err
+var (
+	_ Dialect = (*sqlServer)(nil)
+	_ merger  = (*sqlServer)(nil)
+)
+
+type sqlServer struct {
+	standardSQL
+}
+
+func (s sqlServer) quote() (byte, byte) {
+	return '[', ']'
+}
+
+func (s sqlServer) bindArg(b *builder) {
+	b.sqlBuffer.WriteString("@p")
+	b.sqlBuffer.WriteString(strconv.Itoa(len(b.args)))
+}
+
+// onConflict sql server upsert by "MERGE" statement, see buildMerge.
+func (s sqlServer) onConflict(_ *builder, _ *Conflict) error {
+	return errs.ErrUnsupportedOnConflict
+}
+
+func (s sqlServer) excluded(b *builder, fieldName string) error {
+	return writeExcluded(b, fieldName)
+}
+
+// paginate sql server requires "ORDER BY" with "OFFSET ... FETCH", use "ORDER BY (SELECT NULL)" when unordered.
+func (s sqlServer) paginate(b *builder, limit int64, offset int64, ordered bool) {
+	if limit <= 0 && offset <= 0 {
+		return
+	}
+
+	if !ordered {
+		b.sqlBuffer.WriteString(" ORDER BY (SELECT NULL)")
+	}
+
+	b.sqlBuffer.WriteString(" OFFSET ")
+	b.sqlBuffer.WriteString(strconv.FormatInt(max(offset, 0), 10))
+	b.sqlBuffer.WriteString(" ROWS")
+
+	if limit > 0 {
+		b.sqlBuffer.WriteString(" FETCH NEXT ")
+		b.sqlBuffer.WriteString(strconv.FormatInt(limit, 10))
+		b.sqlBuffer.WriteString(" ROWS ONLY")
+	}
+}
+
+// maxArgs sql server supports at most 2100 parameters.
+func (s sqlServer) maxArgs() int {
+	return 2100
+}
+
+// nullsFirst NULL is smaller than any other value in sql server.
+func (s sqlServer) nullsFirst() bool {
+	return true
+}
+
+// retryable error 1205 transaction was deadlocked and chosen as the victim.
+func (s sqlServer) retryable(err error) bool {
+	number, ok := errNumberOf(err)
+	return ok && number == 1205
+}
+
+// savepoint sql server releases savepoints on commit only.
+func (s sqlServer) savepoint(name string) (string, string, string) {
+	return "SAVE TRANSACTION " + name, "ROLLBACK TRANSACTION " + name, ""
+}
+
+// mergeUsing like "(VALUES (@p1, @p2), (@p3, @p4)) AS [source] ([id], [name])".
+func (s sqlServer) mergeUsing(b *builder, fields []*model.Field, rows [][]any, query *SubQuery) error {
+	b.sqlBuffer.WriteByte('(')
+	if query != nil {
+		if err := b.writeSubQuery(*query); err != nil {
+			return err
+		}
+	} else {
+		b.sqlBuffer.WriteString("VALUES ")
+		for rowIndex, row := range rows {
+			if rowIndex > 0 {
+				b.sqlBuffer.WriteString(", ")
 			}
 
-			b.sqlBuffer.WriteString(" = VALUES(")
-			if err := b.writeField(assignTyp.fieldName); err != nil {
-				return err
+			b.sqlBuffer.WriteByte('(')
+			for valIndex, val := range row {
+				if valIndex > 0 {
+					b.sqlBuffer.WriteString(", ")
+				}
+				b.addArgs(val)
+				s.bindArg(b)
 			}
 			b.sqlBuffer.WriteByte(')')
-		default:
-			return errs.ErrInvalidAssignable
 		}
 	}
+	b.sqlBuffer.WriteByte(')')
+
+	b.writeTableAlias(b.rowAlias)
+	b.sqlBuffer.WriteString(" (")
+	for index, field := range fields {
+		if index > 0 {
+			b.sqlBuffer.WriteString(", ")
+		}
+		b.writeWithQuote(field.ColumnName)
+	}
+	b.sqlBuffer.WriteByte(')')
 	return nil
 }
+
+// mergeMatched like "WHEN MATCHED AND ... THEN UPDATE SET ...".
+func (s sqlServer) mergeMatched(b *builder, conflict *Conflict) error {
+	b.sqlBuffer.WriteString(" WHEN MATCHED")
+	if len(conflict.updateIf) > 0 {
+		c := NewCondition(condTypWhere, conflict.updateIf)
+		b.sqlBuffer.WriteString(" AND ")
+		if err := b.buildExpr(c.expr); err != nil {
+			return err
+		}
+	}
+
+	b.sqlBuffer.WriteString(" THEN UPDATE SET ")
+	return b.buildAssigns(conflict.assigns)
+}
+
+var (
+	_ Dialect = (*oracle)(nil)
+	_ merger  = (*oracle)(nil)
+)
+
+// oracle dialect for oracle 12c and later.
+// Inserting multiple rows in one statement requires oracle 23ai, set Inserter.BatchSize to 1 for earlier versions.
+type oracle struct {
+	standardSQL
+}
+
+func (o oracle) bindArg(b *builder) {
+	b.sqlBuffer.WriteByte(':')
+	b.sqlBuffer.WriteString(strconv.Itoa(len(b.args)))
+}
+
+// tableAliasKeyword oracle does not permit "AS" between table and its alias.
+func (o oracle) tableAliasKeyword() string {
+	return " "
+}
+
+// terminator oracle drivers reject the trailing ';' of sql statement.
+func (o oracle) terminator() string {
+	return ""
+}
+
+// onConflict oracle upsert by "MERGE" statement, see buildMerge.
+func (o oracle) onConflict(_ *builder, _ *Conflict) error {
+	return errs.ErrUnsupportedOnConflict
+}
+
+func (o oracle) excluded(b *builder, fieldName string) error {
+	return writeExcluded(b, fieldName)
+}
+
+// retryable ORA-08177 can't serialize access and ORA-00060 deadlock detected.
+func (o oracle) retryable(err error) bool {
+	code, ok := errNumberOf(err)
+	return ok && (code == 8177 || code == 60)
+}
+
+// savepoint oracle releases savepoints on commit only.
+func (o oracle) savepoint(name string) (string, string, string) {
+	return "SAVEPOINT " + name, "ROLLBACK TO SAVEPOINT " + name, ""
+}
+
+func (o oracle) paginate(b *builder, limit int64, offset int64, _ bool) {
+	if offset > 0 {
+		b.sqlBuffer.WriteString(" OFFSET ")
+		b.sqlBuffer.WriteString(strconv.FormatInt(offset, 10))
+		b.sqlBuffer.WriteString(" ROWS")
+	}
+
+	if limit > 0 {
+		b.sqlBuffer.WriteString(" FETCH NEXT ")
+		b.sqlBuffer.WriteString(strconv.FormatInt(limit, 10))
+		b.sqlBuffer.WriteString(" ROWS ONLY")
+	}
+}
+
+// mergeUsing like "(SELECT :1 "id", :2 "name" FROM DUAL UNION ALL SELECT :3, :4 FROM DUAL) "source"".
+// The columns of select statement must be named as the insert columns.
+func (o oracle) mergeUsing(b *builder, fields []*model.Field, rows [][]any, query *SubQuery) error {
+	b.sqlBuffer.WriteByte('(')
+	if query != nil {
+		if err := b.writeSubQuery(*query); err != nil {
+			return err
+		}
+	} else {
+		for rowIndex, row := range rows {
+			if rowIndex > 0 {
+				b.sqlBuffer.WriteString(" UNION ALL ")
+			}
+
+			b.sqlBuffer.WriteString("SELECT ")
+			for valIndex, val := range row {
+				if valIndex > 0 {
+					b.sqlBuffer.WriteString(", ")
+				}
+				b.addArgs(val)
+				o.bindArg(b)
+
+				if rowIndex == 0 {
+					b.sqlBuffer.WriteByte(' ')
+					b.writeWithQuote(fields[valIndex].ColumnName)
+				}
+			}
+			b.sqlBuffer.WriteString(" FROM DUAL")
+		}
+	}
+	b.sqlBuffer.WriteByte(')')
+
+	b.writeTableAlias(b.rowAlias)
+	return nil
+}
+
+// mergeMatched like "WHEN MATCHED THEN UPDATE SET ... WHERE ...".
+func (o oracle) mergeMatched(b *builder, conflict *Conflict) error {
+	b.sqlBuffer.WriteString(" WHEN MATCHED THEN UPDATE SET ")
+	if err := b.buildAssigns(conflict.assigns); err != nil {
+		return err
+	}
+	return b.buildPredicates(conflict.updateIf)
+}
@@ -5,3 +5,46 @@ package easyorm
 type Expr interface {
 	expr()
 }
+
+var _ Expr = (*MathExpr)(nil)
+
+// MathExpr arithmetic expression, like "`count` + 1".
+type MathExpr struct {
+	left  Expr
+	op    op
+	right Expr
+}
+
+func (m MathExpr) expr() {}
+
+func (m MathExpr) Add(val any) MathExpr {
+	return MathExpr{
+		left:  m,
+		op:    opAdd,
+		right: valueOf(val),
+	}
+}
+
+func (m MathExpr) Sub(val any) MathExpr {
+	return MathExpr{
+		left:  m,
+		op:    opSub,
+		right: valueOf(val),
+	}
+}
+
+func (m MathExpr) Mul(val any) MathExpr {
+	return MathExpr{
+		left:  m,
+		op:    opMul,
+		right: valueOf(val),
+	}
+}
+
+func (m MathExpr) Div(val any) MathExpr {
+	return MathExpr{
+		left:  m,
+		op:    opDiv,
+		right: valueOf(val),
+	}
+}
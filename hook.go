@@ -0,0 +1,71 @@
+package easyorm
+
+import (
+	"context"
+
+	"github.com/JrMarcco/easy-orm/model"
+)
+
+// Lifecycle hooks implemented by entities, which are cached in model registry.
+// Hooks are invoked on entities only, so Deleter and Updater updating by condition only skip their hooks.
+type (
+	BeforeInserter = model.BeforeInserter
+	AfterInserter  = model.AfterInserter
+	BeforeUpdater  = model.BeforeUpdater
+	AfterUpdater   = model.AfterUpdater
+	BeforeDeleter  = model.BeforeDeleter
+	AfterDeleter   = model.AfterDeleter
+	AfterFinder    = model.AfterFinder
+)
+
+// execWithHooks invoke the before hook on entities, execute and then invoke the after hook,
+// the error of before hook aborts the execution.
+func execWithHooks[T any](ctx context.Context, m *model.Model, before, after model.Hook, entities []*T, execFunc func() Result) Result {
+	if err := runHooks(ctx, m, before, entities...); err != nil {
+		return Result{err: err}
+	}
+
+	res := execFunc()
+	if res.Err() != nil {
+		return res
+	}
+
+	if err := runHooks(ctx, m, after, entities...); err != nil {
+		return Result{res: res.res, err: err}
+	}
+	return res
+}
+
+// runHooks invoke the hook on each entity if the model implements it, and stop at the first error.
+func runHooks[T any](ctx context.Context, m *model.Model, hook model.Hook, entities ...*T) error {
+	if !m.Hooks.Has(hook) {
+		return nil
+	}
+
+	for _, entity := range entities {
+		if err := runEntityHook(ctx, hook, entity); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func runEntityHook(ctx context.Context, hook model.Hook, entity any) error {
+	switch hook {
+	case model.HookBeforeInsert:
+		return entity.(BeforeInserter).BeforeInsert(ctx)
+	case model.HookAfterInsert:
+		return entity.(AfterInserter).AfterInsert(ctx)
+	case model.HookBeforeUpdate:
+		return entity.(BeforeUpdater).BeforeUpdate(ctx)
+	case model.HookAfterUpdate:
+		return entity.(AfterUpdater).AfterUpdate(ctx)
+	case model.HookBeforeDelete:
+		return entity.(BeforeDeleter).BeforeDelete(ctx)
+	case model.HookAfterDelete:
+		return entity.(AfterDeleter).AfterDelete(ctx)
+	case model.HookAfterFind:
+		return entity.(AfterFinder).AfterFind(ctx)
+	}
+	return nil
+}
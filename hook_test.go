@@ -0,0 +1,208 @@
+package easyorm
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type hookTestModel struct {
+	Id   uint64
+	Name string
+
+	calls []string
+	err   error
+}
+
+func (h *hookTestModel) call(hook string) error {
+	h.calls = append(h.calls, hook)
+	return h.err
+}
+
+func (h *hookTestModel) BeforeInsert(_ context.Context) error {
+	if h.Name == "" {
+		h.Name = "default"
+	}
+	return h.call("BeforeInsert")
+}
+
+func (h *hookTestModel) AfterInsert(_ context.Context) error  { return h.call("AfterInsert") }
+func (h *hookTestModel) BeforeUpdate(_ context.Context) error { return h.call("BeforeUpdate") }
+func (h *hookTestModel) AfterUpdate(_ context.Context) error  { return h.call("AfterUpdate") }
+func (h *hookTestModel) AfterFind(_ context.Context) error    { return h.call("AfterFind") }
+
+type findHookTestModel struct {
+	Id   uint64
+	Name string
+}
+
+func (f *findHookTestModel) AfterFind(_ context.Context) error {
+	if f.Name == "" {
+		return errHookTest
+	}
+	return nil
+}
+
+type deleteHookTestModel struct {
+	Id uint64
+	// deleted written by hooks
+	deleted bool
+}
+
+var errHookTest = errors.New("hook test error")
+
+func (d *deleteHookTestModel) BeforeDelete(ctx context.Context) error {
+	if ctx.Value(deleteHookKey{}) == nil {
+		return errHookTest
+	}
+	return nil
+}
+
+func (d *deleteHookTestModel) AfterDelete(_ context.Context) error {
+	d.deleted = true
+	return nil
+}
+
+type deleteHookKey struct{}
+
+func TestInserter_Hooks(t *testing.T) {
+	mockDB, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+	require.NoError(t, err)
+	defer func() { _ = mockDB.Close() }()
+
+	db, err := OpenDB(mockDB, MySQLDialect)
+	require.NoError(t, err)
+
+	t.Run("invoked", func(t *testing.T) {
+		mock.ExpectExec("INSERT INTO `hook_test_model` (`id`, `name`) VALUES (?, ?), (?, ?);").
+			WithArgs(uint64(1), "default", uint64(2), "Tom").
+			WillReturnResult(sqlmock.NewResult(2, 2))
+
+		rows := []*hookTestModel{{Id: 1}, {Id: 2, Name: "Tom"}}
+		res := NewInserter[hookTestModel](db).Fields("Id", "Name").Rows(rows...).Exec(t.Context())
+		require.NoError(t, res.Err())
+
+		for _, row := range rows {
+			assert.Equal(t, []string{"BeforeInsert", "AfterInsert"}, row.calls)
+		}
+	})
+
+	t.Run("aborted", func(t *testing.T) {
+		row := &hookTestModel{Id: 1, err: errHookTest}
+		res := NewInserter[hookTestModel](db).Fields("Id", "Name").Rows(row).Exec(t.Context())
+		assert.Equal(t, errHookTest, res.Err())
+		assert.Equal(t, []string{"BeforeInsert"}, row.calls)
+	})
+
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestUpdater_Hooks(t *testing.T) {
+	mockDB, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+	require.NoError(t, err)
+	defer func() { _ = mockDB.Close() }()
+
+	db, err := OpenDB(mockDB, MySQLDialect)
+	require.NoError(t, err)
+
+	mock.ExpectExec("UPDATE `hook_test_model` SET `name` = ? WHERE `id` = ?;").
+		WithArgs("Tom", 1).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	entity := &hookTestModel{Id: 1, Name: "Tom"}
+	res := NewUpdater[hookTestModel](db).Update(entity).Set(Col("Name")).Where(Col("Id").Eq(1)).Exec(t.Context())
+	require.NoError(t, res.Err())
+	assert.Equal(t, []string{"BeforeUpdate", "AfterUpdate"}, entity.calls)
+
+	entity = &hookTestModel{Id: 1, Name: "Tom", err: errHookTest}
+	res = NewUpdater[hookTestModel](db).Update(entity).Set(Col("Name")).Where(Col("Id").Eq(1)).Exec(t.Context())
+	assert.Equal(t, errHookTest, res.Err())
+	assert.Equal(t, []string{"BeforeUpdate"}, entity.calls)
+
+	// hooks writing the receiver are skipped when updating by condition only
+	mock.ExpectExec("UPDATE `hook_test_model` SET `name` = ? WHERE `id` = ?;").
+		WithArgs("Tom", 1).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	res = NewUpdater[hookTestModel](db).Set(Assign("Name", "Tom")).Where(Col("Id").Eq(1)).Exec(t.Context())
+	require.NoError(t, res.Err())
+
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestDeleter_Hooks(t *testing.T) {
+	mockDB, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+	require.NoError(t, err)
+	defer func() { _ = mockDB.Close() }()
+
+	db, err := OpenDB(mockDB, MySQLDialect)
+	require.NoError(t, err)
+
+	// hooks are skipped as rows are deleted by condition, BeforeDelete fails without deleteHookKey if invoked
+	mock.ExpectExec("DELETE FROM `delete_hook_test_model` WHERE `id` = ?;").
+		WithArgs(1).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	res := NewDeleter[deleteHookTestModel](db).Where(Col("Id").Eq(1)).Exec(t.Context())
+	require.NoError(t, res.Err())
+
+	entity := &deleteHookTestModel{Id: 1}
+	res = NewDeleter[deleteHookTestModel](db).Delete(entity).Exec(t.Context())
+	assert.Equal(t, errHookTest, res.Err())
+	assert.False(t, entity.deleted)
+
+	mock.ExpectExec("DELETE FROM `delete_hook_test_model` WHERE `id` = ?;").
+		WithArgs(uint64(1)).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	ctx := context.WithValue(t.Context(), deleteHookKey{}, true)
+	res = NewDeleter[deleteHookTestModel](db).Delete(entity).Exec(ctx)
+	require.NoError(t, res.Err())
+	assert.True(t, entity.deleted)
+
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestSelector_Hooks(t *testing.T) {
+	mockDB, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer func() { _ = mockDB.Close() }()
+
+	db, err := OpenDB(mockDB, MySQLDialect)
+	require.NoError(t, err)
+
+	t.Run("find one", func(t *testing.T) {
+		mock.ExpectQuery("SELECT .*").
+			WillReturnRows(sqlmock.NewRows([]string{"id", "name"}).AddRow(1, "Tom"))
+
+		res, err := NewSelector[hookTestModel](db).FindOne(t.Context())
+		require.NoError(t, err)
+		assert.Equal(t, []string{"AfterFind"}, res.calls)
+	})
+
+	t.Run("find multi", func(t *testing.T) {
+		mock.ExpectQuery("SELECT .*").
+			WillReturnRows(sqlmock.NewRows([]string{"id", "name"}).AddRow(1, "Tom").AddRow(2, "Jerry"))
+
+		res, err := NewSelector[hookTestModel](db).FindMulti(t.Context())
+		require.NoError(t, err)
+		require.Len(t, res, 2)
+		for _, v := range res {
+			assert.Equal(t, []string{"AfterFind"}, v.calls)
+		}
+	})
+
+	t.Run("aborted", func(t *testing.T) {
+		mock.ExpectQuery("SELECT .*").
+			WillReturnRows(sqlmock.NewRows([]string{"id", "name"}).AddRow(1, "Tom").AddRow(2, ""))
+
+		_, err := NewSelector[findHookTestModel](db).FindMulti(t.Context())
+		assert.Equal(t, errHookTest, err)
+	})
+
+	require.NoError(t, mock.ExpectationsWereMet())
+}
@@ -16,22 +16,172 @@ type Inserter[T any] struct {
 	rows   []*T
 	fields []string
 
-	conflict *Conflict
+	query     *SubQuery
+	returning []string
+
+	batchSize int
+	conflict  *Conflict
 }
 
+// Exec executes the insert statement.
+// Auto time fields of rows are filled with the current time if not set,
+// after BeforeInsert hook of rows invoked, and then rows are validated before any SQL is sent.
+// Rows are split into multiple statements when they exceed the batch size,
+// and the batches are executed in one transaction when the inserter is created on DB.
 func (i *Inserter[T]) Exec(ctx context.Context) Result {
-	if err := i.initModel(); err != nil {
+	if i.model == nil {
+		if err := i.initModel(); err != nil {
+			return Result{err: err}
+		}
+	}
+
+	return execWithHooks(ctx, i.model, model.HookBeforeInsert, model.HookAfterInsert, i.rows, func() Result {
+		return i.execute(ctx)
+	})
+}
+
+func (i *Inserter[T]) execute(ctx context.Context) Result {
+	i.tableScope = nil
+	i.schema = i.orm.getCore().schemaOf(ctx)
+	i.fillAutoTime(i.orm.getCore().now())
+
+	if err := i.validate(); err != nil {
+		return Result{err: err}
+	}
+
+	if sdb, ok := i.orm.(*ShardingDB); ok {
+		return i.execSharding(ctx, sdb)
+	}
+
+	batches, err := i.batches()
+	if err != nil {
+		return Result{err: err}
+	}
+
+	if len(batches) <= 1 {
+		return i.exec(ctx, i.orm, i)
+	}
+
+	orm := ormOf(ctx, i.orm)
+	db, ok := orm.(*DB)
+	if !ok {
+		return i.execBatches(ctx, orm, batches)
+	}
+
+	var res Result
+	err = db.DoTx(ctx, func(ctx context.Context, tx *Tx) error {
+		res = i.execBatches(ctx, tx, batches)
+		return res.Err()
+	}, nil)
+	if err != nil {
 		return Result{err: err}
 	}
+	return res
+}
 
-	return exec(ctx, &OrmContext{
+func (i *Inserter[T]) execBatches(ctx context.Context, orm orm, batches [][]*T) Result {
+	results := make(batchResult, 0, len(batches))
+	for _, rows := range batches {
+		batch := &Inserter[T]{
+			builder:  newBuilder(orm),
+			orm:      orm,
+			rows:     rows,
+			fields:   i.fields,
+			conflict: i.conflict,
+		}
+		batch.model = i.model
+		batch.table = i.table
+		batch.schema = i.schema
+		batch.returning = i.returning
+
+		res := i.exec(ctx, orm, batch)
+		if res.Err() != nil {
+			return res
+		}
+		results = append(results, res.res)
+	}
+	return Result{res: results}
+}
+
+func (i *Inserter[T]) exec(ctx context.Context, orm orm, inserter *Inserter[T]) Result {
+	ormCtx := &OrmContext{
 		Typ:     ScTypINSERT,
 		Model:   i.model,
-		Builder: i,
-	}, i.orm)
+		Builder: inserter,
+	}
+
+	if len(inserter.returning) > 0 {
+		return execReturning[T](ctx, ormCtx, orm, inserter.rows)
+	}
+	return exec(ctx, ormCtx, orm)
+}
+
+// batches split rows by the batch size,
+// which is capped by the maximum number of bind arguments the dialect allowed.
+func (i *Inserter[T]) batches() ([][]*T, error) {
+	if len(i.rows) == 0 || i.query != nil {
+		return [][]*T{i.rows}, nil
+	}
+
+	fields, err := i.insertFields()
+	if err != nil {
+		return nil, err
+	}
+
+	size := i.batchSize
+	if size <= 0 || size > len(i.rows) {
+		size = len(i.rows)
+	}
+
+	if maxArgs := i.dialect.maxArgs(); maxArgs > 0 && len(fields) > 0 {
+		// arguments of the on conflict clause are bound once per statement.
+		conflictArgs, err := i.conflictArgs()
+		if err != nil {
+			return nil, err
+		}
+		maxArgs -= conflictArgs
+
+		if limit := maxArgs / len(fields); limit > 0 && size > limit {
+			size = limit
+		}
+	}
+
+	batches := make([][]*T, 0, (len(i.rows)+size-1)/size)
+	for start := 0; start < len(i.rows); start += size {
+		end := min(start+size, len(i.rows))
+		batches = append(batches, i.rows[start:end])
+	}
+	return batches, nil
+}
+
+// conflictArgs count the arguments of on conflict clause by building it alone,
+// including those of assignments, conflict target and update predicates.
+func (i *Inserter[T]) conflictArgs() (int, error) {
+	if i.conflict == nil {
+		return 0, nil
+	}
+
+	b := newBuilder(i.orm)
+	b.model = i.model
+	b.table = i.table
+	b.schema = i.schema
+
+	var err error
+	if m, ok := i.dialect.(merger); ok {
+		b.rowAlias = mergeSourceAlias
+		b.qualify = true
+		err = m.mergeMatched(&b, i.conflict)
+	} else {
+		err = i.dialect.onConflict(&b, i.conflict)
+	}
+	return len(b.args), err
 }
 
 func (i *Inserter[T]) initModel() error {
+	if i.model != nil {
+		return nil
+	}
+
 	var err error
 	i.model, err = i.orm.getCore().registry.GetModel(new(T))
 	return err
@@ -47,8 +197,30 @@ func (i *Inserter[T]) Fields(fields ...string) *Inserter[T] {
 	return i
 }
 
+// FromSelect insert the rows selected by the sub query, like "INSERT INTO t (cols) SELECT ...".
+// The selected columns must be in the same order as the insert fields.
+func (i *Inserter[T]) FromSelect(subQuery SubQuery) *Inserter[T] {
+	i.query = &subQuery
+	return i
+}
+
+// Returning write the returned columns back into the rows, like "INSERT ... RETURNING id".
+// The returned rows are written back in order, so rows skipped by on conflict will misalign them.
+// Only supported on postgres and sqlite.
+func (i *Inserter[T]) Returning(fields ...string) *Inserter[T] {
+	i.returning = fields
+	return i
+}
+
+// BatchSize set the maximum number of rows in a single insert statement.
+// The size is also limited by the maximum number of bind arguments of the dialect.
+func (i *Inserter[T]) BatchSize(size int) *Inserter[T] {
+	i.batchSize = size
+	return i
+}
+
 // OnConflict upsert support.
-// conflicts only supported on postgres, conflicts are field in entity, not columns in db tableAlias.
+// conflicts are fields in entity, not columns in db table, mysql ignores them and updates on any unique key.
 func (i *Inserter[T]) OnConflict(conflicts ...string) *OnConflictBuilder[T] {
 	ocb := &OnConflictBuilder[T]{
 		inserter: i,
@@ -68,7 +240,17 @@ func (i *Inserter[T]) Build() (*Statement, error) {
 		}
 	}
 
-	i.sqlBuffer.WriteString("INSERT INTO ")
+	i.reset()
+
+	if i.conflict != nil {
+		i.conflict.fromSelect = i.query != nil
+
+		if m, ok := i.dialect.(merger); ok {
+			return i.buildMerge(m)
+		}
+	}
+
+	i.dialect.insertInto(&i.builder, i.conflict)
 	i.writeTable()
 
 	if err = i.buildInsertColumns(); err != nil {
@@ -81,7 +263,13 @@ func (i *Inserter[T]) Build() (*Statement, error) {
 		}
 	}
 
-	i.sqlBuffer.WriteByte(';')
+	if len(i.returning) > 0 {
+		if err = i.dialect.returning(&i.builder, i.returning); err != nil {
+			return nil, err
+		}
+	}
+
+	i.writeTerminator()
 
 	return &Statement{
 		SQL:  i.sqlBuffer.String(),
@@ -89,23 +277,97 @@ func (i *Inserter[T]) Build() (*Statement, error) {
 	}, nil
 }
 
-func (i *Inserter[T]) buildInsertColumns() error {
-	if len(i.rows) == 0 {
-		return errs.ErrInsertWithoutRows
+// buildMerge build the upsert by "MERGE" statement for dialects without "ON CONFLICT".
+func (i *Inserter[T]) buildMerge(m merger) (*Statement, error) {
+	if err := i.checkSource(); err != nil {
+		return nil, err
+	}
+
+	if len(i.returning) > 0 {
+		return nil, errs.ErrUnsupportedReturning
+	}
+
+	fields, err := i.insertFields()
+	if err != nil {
+		return nil, err
 	}
-	fields := i.model.SeqFields
 
-	if len(i.fields) > 0 {
-		fields = make([]*model.Field, 0, len(i.fields))
-		for _, f := range i.fields {
-			field, ok := i.model.Fields[f]
-			if !ok {
-				return errs.ErrInvalidField(f)
+	var rows [][]any
+	if i.query == nil {
+		rows = make([][]any, 0, len(i.rows))
+		for _, row := range i.rows {
+			vals, err := i.rowValues(row, fields)
+			if err != nil {
+				return nil, err
 			}
-			fields = append(fields, field)
+			rows = append(rows, vals)
 		}
 	}
 
+	if err = buildMerge(&i.builder, m, fields, rows, i.query, i.conflict); err != nil {
+		return nil, err
+	}
+
+	i.writeTerminator()
+
+	return &Statement{
+		SQL:  i.sqlBuffer.String(),
+		Args: i.args,
+	}, nil
+}
+
+// checkSource check the rows proposed for insertion, either rows or select statement.
+func (i *Inserter[T]) checkSource() error {
+	if i.query != nil && len(i.rows) > 0 {
+		return errs.ErrInsertRowsWithSelect
+	}
+
+	if i.query == nil && len(i.rows) == 0 {
+		return errs.ErrInsertWithoutRows
+	}
+	return nil
+}
+
+func (i *Inserter[T]) rowValues(row *T, fields []*model.Field) ([]any, error) {
+	resolver := i.orm.getCore().resolverCreator(i.model, row)
+
+	vals := make([]any, 0, len(fields))
+	for _, field := range fields {
+		val, err := resolver.ReadColumn(field.FiledName)
+		if err != nil {
+			return nil, err
+		}
+		vals = append(vals, val)
+	}
+	return vals, nil
+}
+
+func (i *Inserter[T]) insertFields() ([]*model.Field, error) {
+	if len(i.fields) == 0 {
+		return i.model.SeqFields, nil
+	}
+
+	fields := make([]*model.Field, 0, len(i.fields))
+	for _, f := range i.fields {
+		field, ok := i.model.Fields[f]
+		if !ok {
+			return nil, errs.ErrInvalidField(f)
+		}
+		fields = append(fields, field)
+	}
+	return fields, nil
+}
+
+func (i *Inserter[T]) buildInsertColumns() error {
+	if err := i.checkSource(); err != nil {
+		return err
+	}
+
+	fields, err := i.insertFields()
+	if err != nil {
+		return err
+	}
+
 	i.sqlBuffer.WriteString(" (")
 	for index, field := range fields {
 		if index > 0 {
@@ -114,7 +376,13 @@ func (i *Inserter[T]) buildInsertColumns() error {
 
 		i.writeWithQuote(field.ColumnName)
 	}
-	i.sqlBuffer.WriteString(") VALUES ")
+	i.sqlBuffer.WriteByte(')')
+
+	if i.query != nil {
+		return i.buildInsertSelect()
+	}
+
+	i.sqlBuffer.WriteString(" VALUES ")
 
 	i.args = make([]any, 0, len(fields)*len(i.rows))
 	for rowIndex, row := range i.rows {
@@ -122,17 +390,17 @@ func (i *Inserter[T]) buildInsertColumns() error {
 			i.sqlBuffer.WriteString(", ")
 		}
 
+		vals, err := i.rowValues(row, fields)
+		if err != nil {
+			return err
+		}
+
 		i.sqlBuffer.WriteByte('(')
-		resolver := i.orm.getCore().resolverCreator(i.model, row)
-		for fieldIndex, field := range fields {
-			if fieldIndex > 0 {
+		for valIndex, val := range vals {
+			if valIndex > 0 {
 				i.sqlBuffer.WriteString(", ")
 			}
 
-			val, err := resolver.ReadColumn(field.FiledName)
-			if err != nil {
-				return err
-			}
 			i.args = append(i.args, val)
 			i.dialect.bindArg(&i.builder)
 		}
@@ -141,6 +409,11 @@ func (i *Inserter[T]) buildInsertColumns() error {
 	return nil
 }
 
+func (i *Inserter[T]) buildInsertSelect() error {
+	i.sqlBuffer.WriteByte(' ')
+	return i.writeSubQuery(*i.query)
+}
+
 func NewInserter[T any](orm orm) *Inserter[T] {
 	return &Inserter[T]{
 		builder: newBuilder(orm),
@@ -149,14 +422,48 @@ func NewInserter[T any](orm orm) *Inserter[T] {
 }
 
 type OnConflictBuilder[T any] struct {
-	inserter  *Inserter[T]
-	conflicts []string
+	inserter   *Inserter[T]
+	conflicts  []string
+	constraint string
+	where      []Predicate
+	updateIf   []Predicate
+}
+
+// OnConstraint use the constraint as the conflict target, like "ON CONFLICT ON CONSTRAINT name".
+// Only supported on postgres.
+func (o *OnConflictBuilder[T]) OnConstraint(name string) *OnConflictBuilder[T] {
+	o.constraint = name
+	return o
+}
+
+// Where set the predicate of partial unique index, like "ON CONFLICT (col) WHERE ...",
+// it requires the conflict columns and can not be used with OnConstraint.
+// Only supported on postgres.
+func (o *OnConflictBuilder[T]) Where(pds ...Predicate) *OnConflictBuilder[T] {
+	o.where = pds
+	return o
+}
+
+// UpdateWhere only update the conflicted rows matched the predicates, like "DO UPDATE SET ... WHERE ...".
+// Only supported on postgres.
+func (o *OnConflictBuilder[T]) UpdateWhere(pds ...Predicate) *OnConflictBuilder[T] {
+	o.updateIf = pds
+	return o
+}
+
+// DoNothing ignore the conflicted rows,
+// "ON CONFLICT DO NOTHING" on postgres and "INSERT IGNORE" on mysql.
+func (o *OnConflictBuilder[T]) DoNothing() *Inserter[T] {
+	return o.Update()
 }
 
 func (o *OnConflictBuilder[T]) Update(assigns ...Assignable) *Inserter[T] {
 	o.inserter.conflict = &Conflict{
-		conflicts: o.conflicts,
-		assigns:   assigns,
+		conflicts:  o.conflicts,
+		constraint: o.constraint,
+		where:      o.where,
+		assigns:    assigns,
+		updateIf:   o.updateIf,
 	}
 	return o.inserter
 }
@@ -114,7 +114,7 @@ func TestInserter_Build(t *testing.T) {
 				Balance: 100,
 			}).OnConflict().Update(Assign("Age", 19), Assign("Balance", 200)),
 			wantRes: &Statement{
-				SQL: "INSERT INTO `insert_test_model` (`id`, `age`, `name`, `email`, `balance`) VALUES (?, ?, ?, ?, ?) ON DUPLICATE KEY UPDATE `age` = ?, `balance` = ?;",
+				SQL: "INSERT INTO `insert_test_model` (`id`, `age`, `name`, `email`, `balance`) VALUES (?, ?, ?, ?, ?) AS `new` ON DUPLICATE KEY UPDATE `age` = ?, `balance` = ?;",
 				Args: []any{
 					uint64(1), int8(18), "foo", &sql.NullString{String: "<EMAIL>", Valid: true}, float64(100), 19, 200,
 				},
@@ -129,7 +129,7 @@ func TestInserter_Build(t *testing.T) {
 				Balance: 100,
 			}).OnConflict().Update(Col("Age")),
 			wantRes: &Statement{
-				SQL: "INSERT INTO `insert_test_model` (`id`, `age`, `name`, `email`, `balance`) VALUES (?, ?, ?, ?, ?) ON DUPLICATE KEY UPDATE `age` = VALUES(`age`);",
+				SQL: "INSERT INTO `insert_test_model` (`id`, `age`, `name`, `email`, `balance`) VALUES (?, ?, ?, ?, ?) AS `new` ON DUPLICATE KEY UPDATE `age` = `new`.`age`;",
 				Args: []any{
 					uint64(1), int8(18), "foo", &sql.NullString{String: "<EMAIL>", Valid: true}, float64(100),
 				},
@@ -140,6 +140,86 @@ func TestInserter_Build(t *testing.T) {
 				Id: uint64(1),
 			}).OnConflict().Update(Assign("Invalid", 19)),
 			wantErr: errs.ErrInvalidField("Invalid"),
+		}, {
+			name: "from select",
+			inserter: func() *Inserter[insertTestModel] {
+				subQuery, err := NewSelector[selectTestModel](db).
+					Select(Col("Id"), Col("Name")).
+					Where(Col("Age").Gt(18)).
+					ToSubQuery()
+				require.NoError(t, err)
+
+				return NewInserter[insertTestModel](db).Fields("Id", "Name").FromSelect(subQuery)
+			}(),
+			wantRes: &Statement{
+				SQL:  "INSERT INTO `insert_test_model` (`id`, `name`) SELECT `id`, `name` FROM `select_test_model` WHERE `age` > ?;",
+				Args: []any{18},
+			},
+		}, {
+			name: "from select with on conflict",
+			inserter: func() *Inserter[insertTestModel] {
+				subQuery, err := NewSelector[selectTestModel](db).
+					Select(Col("Id"), Col("Name")).
+					Where(Col("Age").Gt(18)).
+					ToSubQuery()
+				require.NoError(t, err)
+
+				return NewInserter[insertTestModel](db).
+					Fields("Id", "Name").
+					FromSelect(subQuery).
+					OnConflict().Update(Assign("Age", 19))
+			}(),
+			wantRes: &Statement{
+				SQL:  "INSERT INTO `insert_test_model` (`id`, `name`) SELECT `id`, `name` FROM `select_test_model` WHERE `age` > ? ON DUPLICATE KEY UPDATE `age` = ?;",
+				Args: []any{18, 19},
+			},
+		}, {
+			name: "from select with rows",
+			inserter: func() *Inserter[insertTestModel] {
+				subQuery, err := NewSelector[selectTestModel](db).Select(Col("Id")).ToSubQuery()
+				require.NoError(t, err)
+
+				return NewInserter[insertTestModel](db).
+					Fields("Id").
+					Rows(&insertTestModel{Id: 1}).
+					FromSelect(subQuery)
+			}(),
+			wantErr: errs.ErrInsertRowsWithSelect,
+		}, {
+			name: "with on conflict do nothing",
+			inserter: NewInserter[insertTestModel](db).Fields("Id", "Name").Rows(&insertTestModel{
+				Id:   1,
+				Name: "foo",
+			}).OnConflict().DoNothing(),
+			wantRes: &Statement{
+				SQL:  "INSERT IGNORE INTO `insert_test_model` (`id`, `name`) VALUES (?, ?);",
+				Args: []any{uint64(1), "foo"},
+			},
+		}, {
+			name: "with on conflict and assign expression",
+			inserter: NewInserter[insertTestModel](db).Fields("Id", "Balance").Rows(&insertTestModel{
+				Id:      1,
+				Balance: 100,
+			}).OnConflict().Update(
+				Assign("Balance", Col("Balance").Add(Excluded("Balance"))),
+				Assign("Age", Col("Age").Add(1)),
+			),
+			wantRes: &Statement{
+				SQL:  "INSERT INTO `insert_test_model` (`id`, `balance`) VALUES (?, ?) AS `new` ON DUPLICATE KEY UPDATE `balance` = `balance` + `new`.`balance`, `age` = `age` + ?;",
+				Args: []any{uint64(1), float64(100), 1},
+			},
+		}, {
+			name: "with on conflict constraint",
+			inserter: NewInserter[insertTestModel](db).Rows(&insertTestModel{
+				Id: 1,
+			}).OnConflict().OnConstraint("uk_name").Update(Col("Age")),
+			wantErr: errs.ErrUnsupportedConflictTarget,
+		}, {
+			name: "with on conflict update where",
+			inserter: NewInserter[insertTestModel](db).Rows(&insertTestModel{
+				Id: 1,
+			}).OnConflict().UpdateWhere(Col("Age").Lt(18)).Update(Col("Age")),
+			wantErr: errs.ErrUnsupportedConflictTarget,
 		},
 	}
 
@@ -231,6 +311,263 @@ func TestInserter_OnConflict_Postgres(t *testing.T) {
 				Id: uint64(1),
 			}).OnConflict("Invalid").Update(Assign("Age", 19), Assign("Balance", 200)),
 			wantErr: errs.ErrInvalidField("Invalid"),
+		}, {
+			name: "from select",
+			inserter: func() *Inserter[insertTestModel] {
+				subQuery, err := NewSelector[selectTestModel](db).
+					Select(Col("Id"), Col("Name")).
+					Where(Col("Age").Gt(18)).
+					ToSubQuery()
+				require.NoError(t, err)
+
+				return NewInserter[insertTestModel](db).
+					Fields("Id", "Name").
+					FromSelect(subQuery).
+					OnConflict("Id").Update(Assign("Age", 19))
+			}(),
+			wantRes: &Statement{
+				SQL:  `INSERT INTO "insert_test_model" ("id", "name") SELECT "id", "name" FROM "select_test_model" WHERE "age" > $1 ON CONFLICT ("id") DO UPDATE SET "age" = $2;`,
+				Args: []any{18, 19},
+			},
+		}, {
+			name: "with constraint",
+			inserter: NewInserter[insertTestModel](db).Fields("Id", "Name").Rows(&insertTestModel{
+				Id:   1,
+				Name: "foo",
+			}).OnConflict().OnConstraint("uk_name").Update(Col("Name")),
+			wantRes: &Statement{
+				SQL:  `INSERT INTO "insert_test_model" ("id", "name") VALUES ($1, $2) ON CONFLICT ON CONSTRAINT "uk_name" DO UPDATE SET "name" = EXCLUDED."name";`,
+				Args: []any{uint64(1), "foo"},
+			},
+		}, {
+			name: "with on constraint and partial index",
+			inserter: NewInserter[insertTestModel](db).Rows(&insertTestModel{
+				Id: 1,
+			}).OnConflict().OnConstraint("uk_name").Where(Col("Age").Ge(18)).DoNothing(),
+			wantErr: errs.ErrConflictWhereWithoutCols,
+		}, {
+			name: "with partial index without conflict columns",
+			inserter: NewInserter[insertTestModel](db).Rows(&insertTestModel{
+				Id: 1,
+			}).OnConflict().Where(Col("Age").Ge(18)).DoNothing(),
+			wantErr: errs.ErrConflictWhereWithoutCols,
+		}, {
+			name: "with partial index",
+			inserter: NewInserter[insertTestModel](db).Fields("Id", "Name").Rows(&insertTestModel{
+				Id:   1,
+				Name: "foo",
+			}).OnConflict("Name").Where(Col("Age").Ge(18)).DoNothing(),
+			wantRes: &Statement{
+				SQL:  `INSERT INTO "insert_test_model" ("id", "name") VALUES ($1, $2) ON CONFLICT ("name") WHERE "age" >= $3 DO NOTHING;`,
+				Args: []any{uint64(1), "foo", 18},
+			},
+		}, {
+			name: "with update where and expression",
+			inserter: NewInserter[insertTestModel](db).Fields("Id", "Balance").Rows(&insertTestModel{
+				Id:      1,
+				Balance: 100,
+			}).OnConflict("Id").
+				UpdateWhere(Col("Balance").Lt(Excluded("Balance"))).
+				Update(Assign("Balance", Col("Balance").Add(Excluded("Balance")))),
+			wantRes: &Statement{
+				SQL:  `INSERT INTO "insert_test_model" ("id", "balance") VALUES ($1, $2) ON CONFLICT ("id") DO UPDATE SET "balance" = "insert_test_model"."balance" + EXCLUDED."balance" WHERE "insert_test_model"."balance" < EXCLUDED."balance";`,
+				Args: []any{uint64(1), float64(100)},
+			},
+		},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			statement, err := tc.inserter.Build()
+			assert.Equal(t, tc.wantErr, err)
+
+			if err == nil {
+				assert.Equal(t, tc.wantRes, statement)
+			}
+		})
+	}
+}
+
+func TestInserter_OnConflict_MySQLLegacy(t *testing.T) {
+	db, err := OpenDB(&sql.DB{}, MySQLLegacyDialect)
+	require.NoError(t, err)
+
+	statement, err := NewInserter[insertTestModel](db).Fields("Id", "Balance").Rows(&insertTestModel{
+		Id:      1,
+		Balance: 100,
+	}).OnConflict().Update(
+		Col("Balance"),
+		Assign("Age", Col("Age").Add(1)),
+	).Build()
+	require.NoError(t, err)
+	assert.Equal(t, &Statement{
+		SQL:  "INSERT INTO `insert_test_model` (`id`, `balance`) VALUES (?, ?) ON DUPLICATE KEY UPDATE `balance` = VALUES(`balance`), `age` = `age` + ?;",
+		Args: []any{uint64(1), float64(100), 1},
+	}, statement)
+}
+
+func TestInserter_SQLite(t *testing.T) {
+	db, err := OpenDB(&sql.DB{}, SQLiteDialect)
+	require.NoError(t, err)
+
+	tcs := []struct {
+		name     string
+		inserter *Inserter[insertTestModel]
+		wantRes  *Statement
+		wantErr  error
+	}{
+		{
+			name: "basic",
+			inserter: NewInserter[insertTestModel](db).Fields("Id", "Name").Rows(&insertTestModel{
+				Id:   1,
+				Name: "foo",
+			}),
+			wantRes: &Statement{
+				SQL:  `INSERT INTO "insert_test_model" ("id", "name") VALUES (?, ?);`,
+				Args: []any{uint64(1), "foo"},
+			},
+		}, {
+			name: "with on conflict do nothing",
+			inserter: NewInserter[insertTestModel](db).Fields("Id", "Name").Rows(&insertTestModel{
+				Id:   1,
+				Name: "foo",
+			}).OnConflict("Id").DoNothing(),
+			wantRes: &Statement{
+				SQL:  `INSERT INTO "insert_test_model" ("id", "name") VALUES (?, ?) ON CONFLICT ("id") DO NOTHING;`,
+				Args: []any{uint64(1), "foo"},
+			},
+		}, {
+			name: "with on conflict update",
+			inserter: NewInserter[insertTestModel](db).Fields("Id", "Balance").Rows(&insertTestModel{
+				Id:      1,
+				Balance: 100,
+			}).OnConflict("Id").Update(Assign("Balance", Col("Balance").Add(Excluded("Balance"))), Assign("Age", 18)),
+			wantRes: &Statement{
+				SQL:  `INSERT INTO "insert_test_model" ("id", "balance") VALUES (?, ?) ON CONFLICT ("id") DO UPDATE SET "balance" = "insert_test_model"."balance" + excluded."balance", "age" = ?;`,
+				Args: []any{uint64(1), float64(100), 18},
+			},
+		}, {
+			name: "with returning",
+			inserter: NewInserter[insertTestModel](db).Fields("Name").Rows(&insertTestModel{
+				Name: "foo",
+			}).OnConflict("Name").Update(Col("Name")).Returning("Id"),
+			wantRes: &Statement{
+				SQL:  `INSERT INTO "insert_test_model" ("name") VALUES (?) ON CONFLICT ("name") DO UPDATE SET "name" = excluded."name" RETURNING "id";`,
+				Args: []any{"foo"},
+			},
+		}, {
+			name: "with on constraint",
+			inserter: NewInserter[insertTestModel](db).Rows(&insertTestModel{
+				Id: 1,
+			}).OnConflict().OnConstraint("uk_name").DoNothing(),
+			wantErr: errs.ErrUnsupportedConflictTarget,
+		},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			statement, err := tc.inserter.Build()
+			assert.Equal(t, tc.wantErr, err)
+
+			if err == nil {
+				assert.Equal(t, tc.wantRes, statement)
+			}
+		})
+	}
+}
+
+func TestInserter_Merge(t *testing.T) {
+	sqlServerDB, err := OpenDB(&sql.DB{}, SQLServerDialect)
+	require.NoError(t, err)
+	oracleDB, err := OpenDB(&sql.DB{}, OracleDialect)
+	require.NoError(t, err)
+
+	tcs := []struct {
+		name     string
+		inserter *Inserter[insertTestModel]
+		wantRes  *Statement
+		wantErr  error
+	}{
+		{
+			name: "sql server insert",
+			inserter: NewInserter[insertTestModel](sqlServerDB).Fields("Id", "Name").Rows(
+				&insertTestModel{Id: 1, Name: "foo"},
+				&insertTestModel{Id: 2, Name: "bar"},
+			),
+			wantRes: &Statement{
+				SQL:  "INSERT INTO [insert_test_model] ([id], [name]) VALUES (@p1, @p2), (@p3, @p4);",
+				Args: []any{uint64(1), "foo", uint64(2), "bar"},
+			},
+		}, {
+			name: "sql server merge",
+			inserter: NewInserter[insertTestModel](sqlServerDB).Fields("Id", "Name").Rows(
+				&insertTestModel{Id: 1, Name: "foo"},
+				&insertTestModel{Id: 2, Name: "bar"},
+			).OnConflict("Id").Update(Col("Name"), Assign("Age", Col("Age").Add(1))),
+			wantRes: &Statement{
+				SQL: "MERGE INTO [insert_test_model] USING (VALUES (@p1, @p2), (@p3, @p4)) AS [source] ([id], [name]) " +
+					"ON ([insert_test_model].[id] = [source].[id]) " +
+					"WHEN MATCHED THEN UPDATE SET [name] = [source].[name], [age] = [insert_test_model].[age] + @p5 " +
+					"WHEN NOT MATCHED THEN INSERT ([id], [name]) VALUES ([source].[id], [source].[name]);",
+				Args: []any{uint64(1), "foo", uint64(2), "bar", 1},
+			},
+		}, {
+			name: "sql server merge with condition",
+			inserter: NewInserter[insertTestModel](sqlServerDB).Fields("Id", "Balance").Rows(
+				&insertTestModel{Id: 1, Balance: 100},
+			).OnConflict("Id").UpdateWhere(Col("Balance").Lt(Excluded("Balance"))).Update(Col("Balance")),
+			wantRes: &Statement{
+				SQL: "MERGE INTO [insert_test_model] USING (VALUES (@p1, @p2)) AS [source] ([id], [balance]) " +
+					"ON ([insert_test_model].[id] = [source].[id]) " +
+					"WHEN MATCHED AND [insert_test_model].[balance] < [source].[balance] THEN UPDATE SET [balance] = [source].[balance] " +
+					"WHEN NOT MATCHED THEN INSERT ([id], [balance]) VALUES ([source].[id], [source].[balance]);",
+				Args: []any{uint64(1), float64(100)},
+			},
+		}, {
+			name: "sql server merge do nothing",
+			inserter: NewInserter[insertTestModel](sqlServerDB).Fields("Id", "Name").Rows(
+				&insertTestModel{Id: 1, Name: "foo"},
+			).OnConflict("Id").DoNothing(),
+			wantRes: &Statement{
+				SQL: "MERGE INTO [insert_test_model] USING (VALUES (@p1, @p2)) AS [source] ([id], [name]) " +
+					"ON ([insert_test_model].[id] = [source].[id]) " +
+					"WHEN NOT MATCHED THEN INSERT ([id], [name]) VALUES ([source].[id], [source].[name]);",
+				Args: []any{uint64(1), "foo"},
+			},
+		}, {
+			name: "sql server merge without conflicts",
+			inserter: NewInserter[insertTestModel](sqlServerDB).Rows(
+				&insertTestModel{Id: 1},
+			).OnConflict().DoNothing(),
+			wantErr: errs.ErrUnsupportedConflictTarget,
+		}, {
+			name: "oracle insert",
+			inserter: NewInserter[insertTestModel](oracleDB).Fields("Id", "Name").Rows(
+				&insertTestModel{Id: 1, Name: "foo"},
+			),
+			wantRes: &Statement{
+				SQL:  `INSERT INTO "insert_test_model" ("id", "name") VALUES (:1, :2)`,
+				Args: []any{uint64(1), "foo"},
+			},
+		}, {
+			name: "oracle merge",
+			inserter: NewInserter[insertTestModel](oracleDB).Fields("Id", "Name").Rows(
+				&insertTestModel{Id: 1, Name: "foo"},
+				&insertTestModel{Id: 2, Name: "bar"},
+			).OnConflict("Id").UpdateWhere(Col("Age").Lt(18)).Update(Col("Name")),
+			wantRes: &Statement{
+				SQL: `MERGE INTO "insert_test_model" USING (SELECT :1 "id", :2 "name" FROM DUAL UNION ALL SELECT :3, :4 FROM DUAL) "source" ` +
+					`ON ("insert_test_model"."id" = "source"."id") ` +
+					`WHEN MATCHED THEN UPDATE SET "name" = "source"."name" WHERE "insert_test_model"."age" < :5 ` +
+					`WHEN NOT MATCHED THEN INSERT ("id", "name") VALUES ("source"."id", "source"."name")`,
+				Args: []any{uint64(1), "foo", uint64(2), "bar", 18},
+			},
+		}, {
+			name: "oracle merge with returning",
+			inserter: NewInserter[insertTestModel](oracleDB).Rows(
+				&insertTestModel{Id: 1},
+			).OnConflict("Id").DoNothing().Returning("Id"),
+			wantErr: errs.ErrUnsupportedReturning,
 		},
 	}
 
@@ -246,6 +583,70 @@ func TestInserter_OnConflict_Postgres(t *testing.T) {
 	}
 }
 
+func TestInserter_Merge_Exec(t *testing.T) {
+	mockDB, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+	require.NoError(t, err)
+	defer func() {
+		_ = mockDB.Close()
+	}()
+
+	db, err := OpenDB(mockDB, SQLServerDialect)
+	require.NoError(t, err)
+
+	mock.ExpectExec("MERGE INTO [insert_test_model] USING (VALUES (@p1, @p2)) AS [source] ([id], [name]) "+
+		"ON ([insert_test_model].[id] = [source].[id]) "+
+		"WHEN MATCHED THEN UPDATE SET [name] = [source].[name] "+
+		"WHEN NOT MATCHED THEN INSERT ([id], [name]) VALUES ([source].[id], [source].[name]);").
+		WithArgs(uint64(1), "foo").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	res := NewInserter[insertTestModel](db).Fields("Id", "Name").Rows(
+		&insertTestModel{Id: 1, Name: "foo"},
+	).OnConflict("Id").Update(Col("Name")).Exec(context.Background())
+	require.NoError(t, res.Err())
+	assert.Equal(t, int64(1), res.RowsAffected())
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestInserter_Returning(t *testing.T) {
+	mockDB, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer func() {
+		_ = mockDB.Close()
+	}()
+
+	db, err := OpenDB(mockDB, PostgresDialect)
+	require.NoError(t, err)
+
+	rows := []*insertTestModel{
+		{Name: "foo"},
+		{Name: "bar"},
+	}
+
+	mockRows := sqlmock.NewRows([]string{"id"})
+	mockRows.AddRow(1)
+	mockRows.AddRow(2)
+	mock.ExpectQuery(`INSERT INTO "insert_test_model" \("name"\) VALUES \(\$1\), \(\$2\) RETURNING "id";`).
+		WithArgs("foo", "bar").
+		WillReturnRows(mockRows)
+
+	res := NewInserter[insertTestModel](db).Fields("Name").Rows(rows...).Returning("Id").Exec(context.Background())
+	require.NoError(t, res.Err())
+	assert.Equal(t, int64(2), res.RowsAffected())
+	assert.Equal(t, []*insertTestModel{
+		{Id: 1, Name: "foo"},
+		{Id: 2, Name: "bar"},
+	}, rows)
+
+	_, err = NewInserter[insertTestModel](db).Rows(rows...).Returning("Id").Build()
+	require.NoError(t, err)
+
+	mysqlDB, err := OpenDB(mockDB, MySQLDialect)
+	require.NoError(t, err)
+	_, err = NewInserter[insertTestModel](mysqlDB).Rows(rows...).Returning("Id").Build()
+	assert.Equal(t, errs.ErrUnsupportedReturning, err)
+}
+
 func TestInserter_Exec(t *testing.T) {
 	mockDB, mock, err := sqlmock.New()
 	require.NoError(t, err)
@@ -306,3 +707,131 @@ func TestInserter_Exec(t *testing.T) {
 		})
 	}
 }
+
+func TestInserter_BatchSize(t *testing.T) {
+	mockDB, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer func() {
+		_ = mockDB.Close()
+	}()
+
+	db, err := OpenDB(mockDB, MySQLDialect)
+	require.NoError(t, err)
+
+	rows := []*insertTestModel{
+		{Id: 1, Name: "foo"},
+		{Id: 2, Name: "bar"},
+		{Id: 3, Name: "baz"},
+	}
+
+	tcs := []struct {
+		name     string
+		mockFunc func()
+		inserter *Inserter[insertTestModel]
+		wantRes  int64
+		wantErr  error
+	}{
+		{
+			name: "single batch",
+			mockFunc: func() {
+				mock.ExpectExec("INSERT INTO `insert_test_model` \\(`id`, `name`\\) VALUES \\(\\?, \\?\\), \\(\\?, \\?\\), \\(\\?, \\?\\);").
+					WithArgs(uint64(1), "foo", uint64(2), "bar", uint64(3), "baz").
+					WillReturnResult(sqlmock.NewResult(3, 3))
+			},
+			inserter: NewInserter[insertTestModel](db).Fields("Id", "Name").BatchSize(3).Rows(rows...),
+			wantRes:  3,
+		}, {
+			name: "multiple batches",
+			mockFunc: func() {
+				mock.ExpectBegin()
+				mock.ExpectExec("INSERT INTO `insert_test_model` \\(`id`, `name`\\) VALUES \\(\\?, \\?\\), \\(\\?, \\?\\);").
+					WithArgs(uint64(1), "foo", uint64(2), "bar").
+					WillReturnResult(sqlmock.NewResult(2, 2))
+				mock.ExpectExec("INSERT INTO `insert_test_model` \\(`id`, `name`\\) VALUES \\(\\?, \\?\\);").
+					WithArgs(uint64(3), "baz").
+					WillReturnResult(sqlmock.NewResult(3, 1))
+				mock.ExpectCommit()
+			},
+			inserter: NewInserter[insertTestModel](db).Fields("Id", "Name").BatchSize(2).Rows(rows...),
+			wantRes:  3,
+		}, {
+			name: "rollback on batch error",
+			mockFunc: func() {
+				mock.ExpectBegin()
+				mock.ExpectExec("INSERT INTO `insert_test_model`.*").
+					WillReturnResult(sqlmock.NewResult(2, 2))
+				mock.ExpectExec("INSERT INTO `insert_test_model`.*").
+					WillReturnError(errors.New("db error"))
+				mock.ExpectRollback()
+			},
+			inserter: NewInserter[insertTestModel](db).Fields("Id", "Name").BatchSize(2).Rows(rows...),
+			wantErr:  errs.ErrRollback(errors.New("db error"), nil, false),
+		},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			tc.mockFunc()
+
+			res := tc.inserter.Exec(context.Background())
+			assert.Equal(t, tc.wantErr, res.Err())
+
+			if res.Err() == nil {
+				assert.Equal(t, tc.wantRes, res.RowsAffected())
+			}
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestInserter_batches(t *testing.T) {
+	db, err := OpenDB(&sql.DB{}, PostgresDialect)
+	require.NoError(t, err)
+
+	rows := make([]*insertTestModel, 20000)
+	for i := range rows {
+		rows[i] = &insertTestModel{Id: uint64(i)}
+	}
+
+	inserter := NewInserter[insertTestModel](db).Rows(rows...)
+	require.NoError(t, inserter.initModel())
+
+	// 5 fields per row, at most 65535 / 5 rows per statement
+	batches, err := inserter.batches()
+	require.NoError(t, err)
+	assert.Equal(t, 2, len(batches))
+	assert.Equal(t, 13107, len(batches[0]))
+	assert.Equal(t, 6893, len(batches[1]))
+}
+
+func TestInserter_batches_conflictArgs(t *testing.T) {
+	db, err := OpenDB(&sql.DB{}, SQLiteDialect)
+	require.NoError(t, err)
+
+	rows := make([]*insertTestModel, 7000)
+	for i := range rows {
+		rows[i] = &insertTestModel{Id: uint64(i)}
+	}
+
+	inserter := NewInserter[insertTestModel](db).Rows(rows...).
+		OnConflict("Id").
+		UpdateWhere(Col("Age").Lt(18)).
+		Update(Assign("Name", "minor"), Col("Balance"))
+	require.NoError(t, inserter.initModel())
+
+	// 2 arguments of on conflict clause, at most (32766 - 2) / 5 rows per statement
+	batches, err := inserter.batches()
+	require.NoError(t, err)
+	assert.Equal(t, 2, len(batches))
+	assert.Equal(t, 6552, len(batches[0]))
+	assert.Equal(t, 448, len(batches[1]))
+
+	for _, batch := range batches {
+		stmt, err := NewInserter[insertTestModel](db).Rows(batch...).
+			OnConflict("Id").
+			UpdateWhere(Col("Age").Lt(18)).
+			Update(Assign("Name", "minor"), Col("Balance")).Build()
+		require.NoError(t, err)
+		assert.LessOrEqual(t, len(stmt.Args), SQLiteDialect.maxArgs())
+	}
+}
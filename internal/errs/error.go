@@ -6,23 +6,46 @@ import (
 )
 
 var (
-	ErrInvalidModelType      = errors.New("[easy-orm] invalid model entity type, only support struct or pointer to struct")
-	ErrEligibleRow           = errors.New("[easy-orm] eligible row not found")
-	ErrUnsafeDelete          = errors.New("[easy-orm] unsafe delete")
-	ErrInsertWithoutRows     = errors.New("[easy-orm] insert without rows")
-	ErrUnsupportedOnConflict = errors.New("[easy-orm] unsupported on conflict in standard sql")
-	ErrInvalidAssignable     = errors.New("[easy-orm] invalid assignable")
-	ErrHavingWithoutGroupBy  = errors.New("[easy-orm] having without group by")
+	ErrInvalidModelType          = errors.New("[easy-orm] invalid model entity type, only support struct or pointer to struct")
+	ErrEligibleRow               = errors.New("[easy-orm] eligible row not found")
+	ErrUnsafeDelete              = errors.New("[easy-orm] unsafe delete")
+	ErrInsertWithoutRows         = errors.New("[easy-orm] insert without rows")
+	ErrInsertRowsWithSelect      = errors.New("[easy-orm] insert with both rows and select")
+	ErrUnsupportedOnConflict     = errors.New("[easy-orm] unsupported on conflict in standard sql")
+	ErrUnsupportedConflictTarget = errors.New("[easy-orm] unsupported conflict constraint or condition")
+	ErrConflictWhereWithoutCols  = errors.New("[easy-orm] conflict where requires conflict columns rather than constraint")
+	ErrUnsupportedLastInsertId   = errors.New("[easy-orm] unsupported last insert id, use returning instead")
+	ErrUnsupportedReturning      = errors.New("[easy-orm] unsupported returning")
+	ErrInvalidAssignable         = errors.New("[easy-orm] invalid assignable")
+	ErrHavingWithoutGroupBy      = errors.New("[easy-orm] having without group by")
+	ErrMissingTenant             = errors.New("[easy-orm] missing tenant in context")
+	ErrScopeValueWithSelect      = errors.New("[easy-orm] scope value of rows inserted by select")
+	ErrShardingNotRouted         = errors.New("[easy-orm] statement on sharding db is not routed to shard")
+	ErrUpdateWithoutAssigns      = errors.New("[easy-orm] update without assignments")
+	ErrUpdateWithoutEntity       = errors.New("[easy-orm] update column without entity")
+	ErrUpdateWithoutWhere        = errors.New("[easy-orm] update entity without where or primary key")
+	ErrWithoutPrimaryKey         = errors.New("[easy-orm] model without primary key")
+	ErrUntrackedEntity           = errors.New("[easy-orm] entity is not tracked, find it by selector with Track")
+	ErrOptimisticLock            = errors.New("[easy-orm] optimistic lock failed, the row is modified or deleted concurrently")
+	ErrMigrationLocked           = errors.New("[easy-orm] migration is locked by another runner")
 )
 
 func ErrUnsupportedExpr(expr any) error {
 	return fmt.Errorf("[easy-orm] unsupported expression: %v", expr)
 }
 
+func ErrUnsupportedJoinScope(joinTyp string) error {
+	return fmt.Errorf("[easy-orm] unsupported scoping the joined tables of %s by USING, join them by ON instead", joinTyp)
+}
+
 func ErrInvalidField(fieldName string) error {
 	return fmt.Errorf("[easy-orm] invalid field: %s", fieldName)
 }
 
+func ErrInvalidValue(fieldName string, val any) error {
+	return fmt.Errorf("[easy-orm] invalid value of field %s: %v", fieldName, val)
+}
+
 func ErrInvalidTable(name string) error {
 	return fmt.Errorf("[easy-orm] invalid table: %s", name)
 }
@@ -35,6 +58,22 @@ func ErrInvalidTag(tagPair string) error {
 	return fmt.Errorf("[easy-orm] invalid tag: %s", tagPair)
 }
 
+func ErrDuplicateTag(tag string) error {
+	return fmt.Errorf("[easy-orm] duplicate tag: %s", tag)
+}
+
+func ErrInvalidSoftDeleteField(fieldName string) error {
+	return fmt.Errorf("[easy-orm] invalid soft delete field %s, only support time, bool or integer", fieldName)
+}
+
+func ErrInvalidVersionField(fieldName string) error {
+	return fmt.Errorf("[easy-orm] invalid version field %s, only support integer", fieldName)
+}
+
+func ErrInvalidAutoTimeField(fieldName string) error {
+	return fmt.Errorf("[easy-orm] invalid auto time field %s, only support time or integer", fieldName)
+}
+
 func ErrRollback(bizErr, rbErr error, bizPanicked bool) error {
 	return fmt.Errorf(
 		"[easy-orm] failed to rollback for biz error: %v, rollback error: %v, business panicked: %v",
@@ -45,3 +84,63 @@ func ErrRollback(bizErr, rbErr error, bizPanicked bool) error {
 func ErrInvalidTbRefTypeRef(tableRef any) error {
 	return fmt.Errorf("[easy-orm] invalid table reference type: %v", tableRef)
 }
+
+func ErrTxHookPanic(val any) error {
+	return fmt.Errorf("[easy-orm] transaction hook panicked: %v", val)
+}
+
+func ErrNoShardingAlgorithm(tableName string) error {
+	return fmt.Errorf("[easy-orm] no sharding algorithm for model: %s", tableName)
+}
+
+func ErrInvalidShardingKey(val any) error {
+	return fmt.Errorf("[easy-orm] invalid sharding key value: %v", val)
+}
+
+func ErrNoShard(val any) error {
+	return fmt.Errorf("[easy-orm] no shard for sharding key value: %v", val)
+}
+
+func ErrInvalidShardingAlgorithm(reason string) error {
+	return fmt.Errorf("[easy-orm] invalid sharding algorithm: %s", reason)
+}
+
+func ErrShardDBNotFound(name string) error {
+	return fmt.Errorf("[easy-orm] sharding db not found: %s", name)
+}
+
+func ErrUnsupportedSharding(feature string) error {
+	return fmt.Errorf("[easy-orm] unsupported %s on sharding db", feature)
+}
+
+func ErrUnsupportedColumnType(fieldName string, typ any) error {
+	return fmt.Errorf("[easy-orm] unsupported column type of field %s: %v, declare it by type tag", fieldName, typ)
+}
+
+func ErrUnsupportedDialect(dialect any) error {
+	return fmt.Errorf("[easy-orm] unsupported dialect: %T", dialect)
+}
+
+func ErrInvalidMigration(name string) error {
+	return fmt.Errorf("[easy-orm] invalid migration: %s", name)
+}
+
+func ErrDuplicateMigration(version int64) error {
+	return fmt.Errorf("[easy-orm] duplicate migration version: %d", version)
+}
+
+func ErrUnknownMigration(version int64) error {
+	return fmt.Errorf("[easy-orm] applied migration %d is unknown", version)
+}
+
+func ErrIrreversibleMigration(version int64) error {
+	return fmt.Errorf("[easy-orm] migration %d is irreversible without down", version)
+}
+
+func ErrMigrationFailed(version int64, direction string, err error) error {
+	return fmt.Errorf("[easy-orm] failed to migrate %s %d: %w", direction, version, err)
+}
+
+func ErrUnsupportedModifyColumn(table string, column string) error {
+	return fmt.Errorf("[easy-orm] unsupported to modify column %s of table %s, rebuild the table instead", column, table)
+}
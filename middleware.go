@@ -14,4 +14,8 @@ const (
 	ScTypINSERT = "INSERT"
 	ScTypUPDATE = "UPDATE"
 	ScTypDELETE = "DELETE"
+
+	ScTypBEGIN    = "BEGIN"
+	ScTypCOMMIT   = "COMMIT"
+	ScTypROLLBACK = "ROLLBACK"
 )
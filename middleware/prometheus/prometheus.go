@@ -42,7 +42,11 @@ func (m *MiddlewareBuilder) Build() easyorm.Middleware {
 		return func(ctx context.Context, ormCtx *easyorm.OrmContext) *easyorm.OrmResult {
 			start := time.Now()
 			defer func() {
-				m.vec.WithLabelValues(ormCtx.Typ, ormCtx.Model.TableName).Observe(float64(time.Since(start).Milliseconds()))
+				var tableName string
+				if ormCtx.Model != nil {
+					tableName = ormCtx.Model.TableName
+				}
+				m.vec.WithLabelValues(ormCtx.Typ, tableName).Observe(float64(time.Since(start).Milliseconds()))
 			}()
 			return next(ctx, ormCtx)
 		}
@@ -0,0 +1,97 @@
+package tenant
+
+import (
+	"context"
+
+	easyorm "github.com/JrMarcco/easy-orm"
+	"github.com/JrMarcco/easy-orm/internal/errs"
+	"github.com/JrMarcco/easy-orm/model"
+)
+
+type tenantKey struct{}
+
+// WithTenant returns a context carrying the tenant, read by the default tenant function of middleware.
+func WithTenant(ctx context.Context, tenant any) context.Context {
+	return context.WithValue(ctx, tenantKey{}, tenant)
+}
+
+// FromContext returns the tenant stored by WithTenant.
+func FromContext(ctx context.Context) (any, bool) {
+	tenant := ctx.Value(tenantKey{})
+	return tenant, tenant != nil
+}
+
+// MiddlewareBuilder scope statements of models with field tagged "tenant" to the tenant in context.
+// The predicate on tenant field qualified by table is added for every tenant aware table of SELECT,
+// UPDATE and DELETE, including the joined tables and the tables of sub queries,
+// and the tenant field of inserted rows is set to the tenant.
+// Statements on tenant aware tables without tenant in context are rejected,
+// so are inserts of tenant aware models by select as the tenant of selected rows can not be set.
+//
+// It should be placed before middlewares reading the statement, e.g. slog and trace,
+// raw statements are not scoped.
+type MiddlewareBuilder struct {
+	tenantFunc func(ctx context.Context) (any, bool)
+}
+
+type Opt func(*MiddlewareBuilder)
+
+// WithTenantFunc set the function getting tenant from context, default is FromContext.
+func WithTenantFunc(tenantFunc func(ctx context.Context) (any, bool)) Opt {
+	return func(builder *MiddlewareBuilder) {
+		builder.tenantFunc = tenantFunc
+	}
+}
+
+func NewMiddlewareBuilder(opts ...Opt) *MiddlewareBuilder {
+	builder := &MiddlewareBuilder{
+		tenantFunc: FromContext,
+	}
+	for _, opt := range opts {
+		opt(builder)
+	}
+	return builder
+}
+
+func (m *MiddlewareBuilder) Build() easyorm.Middleware {
+	return func(next easyorm.HandleFunc) easyorm.HandleFunc {
+		return func(ctx context.Context, ormCtx *easyorm.OrmContext) *easyorm.OrmResult {
+			tenant, ok := m.tenantFunc(ctx)
+
+			// tables of statement are scoped even if the model is not tenant aware, e.g. joined or in sub queries.
+			if scoper, isTableScoper := ormCtx.Builder.(easyorm.TableScoper); isTableScoper {
+				scoper.ScopeTables(func(md *model.Model) (*model.Field, any, error) {
+					if md.Tenant == nil {
+						return nil, nil, nil
+					}
+					if !ok {
+						return nil, nil, errs.ErrMissingTenant
+					}
+					return md.Tenant, tenant, nil
+				})
+			}
+
+			if ormCtx.Model == nil || ormCtx.Model.Tenant == nil {
+				return next(ctx, ormCtx)
+			}
+
+			if !ok {
+				return &easyorm.OrmResult{Err: errs.ErrMissingTenant}
+			}
+
+			fieldName := ormCtx.Model.Tenant.FiledName
+			switch builder := ormCtx.Builder.(type) {
+			case easyorm.ValueScoper:
+				if err := builder.ScopeValue(fieldName, tenant); err != nil {
+					return &easyorm.OrmResult{Err: err}
+				}
+			case easyorm.TableScoper:
+				// scoped with the tables of statement above
+			case easyorm.WhereScoper:
+				builder.ScopeWhere(easyorm.Col(fieldName).Eq(tenant))
+			}
+
+			return next(ctx, ormCtx)
+		}
+	}
+}
@@ -0,0 +1,307 @@
+package tenant
+
+import (
+	"context"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	easyorm "github.com/JrMarcco/easy-orm"
+	"github.com/JrMarcco/easy-orm/internal/errs"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type tenantTestModel struct {
+	Id       uint64
+	TenantId uint64 `orm:"tenant"`
+	Name     string
+}
+
+type tenantOrderModel struct {
+	Id       uint64
+	TenantId uint64 `orm:"tenant"`
+	UserId   uint64
+}
+
+type plainTestModel struct {
+	Id   uint64
+	Name string
+}
+
+func TestMiddlewareBuilder_Build(t *testing.T) {
+	tenantCtx := WithTenant(context.Background(), 7)
+
+	tcs := []struct {
+		name     string
+		ctx      context.Context
+		mockFunc func(mock sqlmock.Sqlmock)
+		execFunc func(ctx context.Context, db *easyorm.DB) error
+		wantErr  error
+	}{
+		{
+			name: "select",
+			ctx:  tenantCtx,
+			mockFunc: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery("SELECT * FROM `tenant_test_model` WHERE ((`id` = ?) OR (`name` = ?)) AND (`tenant_id` = ?);").
+					WithArgs(1, "foo", 7).
+					WillReturnRows(sqlmock.NewRows([]string{"id", "tenant_id", "name"}))
+			},
+			execFunc: func(ctx context.Context, db *easyorm.DB) error {
+				_, err := easyorm.NewSelector[tenantTestModel](db).
+					Where(easyorm.Col("Id").Eq(1).Or(easyorm.Col("Name").Eq("foo"))).
+					FindMulti(ctx)
+				return err
+			},
+		}, {
+			name: "select twice",
+			ctx:  tenantCtx,
+			mockFunc: func(mock sqlmock.Sqlmock) {
+				for i := 0; i < 2; i++ {
+					mock.ExpectQuery("SELECT * FROM `tenant_test_model` WHERE `tenant_id` = ?;").
+						WithArgs(7).
+						WillReturnRows(sqlmock.NewRows([]string{"id", "tenant_id", "name"}))
+				}
+			},
+			execFunc: func(ctx context.Context, db *easyorm.DB) error {
+				selector := easyorm.NewSelector[tenantTestModel](db)
+				if _, err := selector.FindMulti(ctx); err != nil {
+					return err
+				}
+				_, err := selector.FindMulti(ctx)
+				return err
+			},
+		}, {
+			name: "delete",
+			ctx:  tenantCtx,
+			mockFunc: func(mock sqlmock.Sqlmock) {
+				mock.ExpectExec("DELETE FROM `tenant_test_model` WHERE (`id` = ?) AND (`tenant_id` = ?);").
+					WithArgs(1, 7).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+			},
+			execFunc: func(ctx context.Context, db *easyorm.DB) error {
+				return easyorm.NewDeleter[tenantTestModel](db).Where(easyorm.Col("Id").Eq(1)).Exec(ctx).Err()
+			},
+		}, {
+			name: "update",
+			ctx:  tenantCtx,
+			mockFunc: func(mock sqlmock.Sqlmock) {
+				mock.ExpectExec("UPDATE `tenant_test_model` SET `name` = ? WHERE (`id` = ?) AND (`tenant_id` = ?);").
+					WithArgs("bar", 1, 7).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+			},
+			execFunc: func(ctx context.Context, db *easyorm.DB) error {
+				return easyorm.NewUpdater[tenantTestModel](db).
+					Set(easyorm.Assign("Name", "bar")).
+					Where(easyorm.Col("Id").Eq(1)).
+					Exec(ctx).Err()
+			},
+		}, {
+			name: "update entity",
+			ctx:  tenantCtx,
+			mockFunc: func(mock sqlmock.Sqlmock) {
+				mock.ExpectExec("UPDATE `tenant_test_model` SET `name` = ? WHERE (`id` = ?) AND (`tenant_id` = ?);").
+					WithArgs("bar", uint64(1), 7).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+			},
+			execFunc: func(ctx context.Context, db *easyorm.DB) error {
+				// the tenant of entity is not assigned, which moves the row to another tenant
+				return easyorm.NewUpdater[tenantTestModel](db).
+					Update(&tenantTestModel{Id: 1, TenantId: 8, Name: "bar"}).
+					Exec(ctx).Err()
+			},
+		}, {
+			name: "insert",
+			ctx:  tenantCtx,
+			mockFunc: func(mock sqlmock.Sqlmock) {
+				mock.ExpectExec("INSERT INTO `tenant_test_model` (`id`, `name`, `tenant_id`) VALUES (?, ?, ?);").
+					WithArgs(uint64(1), "foo", uint64(7)).
+					WillReturnResult(sqlmock.NewResult(1, 1))
+			},
+			execFunc: func(ctx context.Context, db *easyorm.DB) error {
+				row := &tenantTestModel{Id: 1, Name: "foo"}
+				if err := easyorm.NewInserter[tenantTestModel](db).Fields("Id", "Name").Rows(row).Exec(ctx).Err(); err != nil {
+					return err
+				}
+
+				if row.TenantId != 7 {
+					return errs.ErrInvalidValue("TenantId", row.TenantId)
+				}
+				return nil
+			},
+		}, {
+			name: "join",
+			ctx:  tenantCtx,
+			mockFunc: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery("SELECT * FROM `tenant_test_model` INNER JOIN `tenant_order_model` ON `id` = `user_id` AND `tenant_order_model`.`tenant_id` = ? WHERE `tenant_test_model`.`tenant_id` = ?;").
+					WithArgs(7, 7).
+					WillReturnRows(sqlmock.NewRows([]string{"id", "tenant_id", "name"}))
+			},
+			execFunc: func(ctx context.Context, db *easyorm.DB) error {
+				user, order := easyorm.TableOf(&tenantTestModel{}), easyorm.TableOf(&tenantOrderModel{})
+				_, err := easyorm.NewSelector[tenantTestModel](db).
+					From(user.InnerJoin(order).On(user.Col("Id").Eq(order.Col("UserId")))).
+					FindMulti(ctx)
+				return err
+			},
+		}, {
+			name: "left join with alias",
+			ctx:  tenantCtx,
+			mockFunc: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery("SELECT * FROM `tenant_test_model` AS `u` LEFT JOIN `tenant_order_model` AS `o` ON `u`.`id` = `o`.`user_id` AND `o`.`tenant_id` = ? WHERE `u`.`tenant_id` = ?;").
+					WithArgs(7, 7).
+					WillReturnRows(sqlmock.NewRows([]string{"id", "tenant_id", "name"}))
+			},
+			execFunc: func(ctx context.Context, db *easyorm.DB) error {
+				user, order := easyorm.TableAs(&tenantTestModel{}, "u"), easyorm.TableAs(&tenantOrderModel{}, "o")
+				_, err := easyorm.NewSelector[tenantTestModel](db).
+					From(user.LeftJoin(order).On(user.Col("Id").Eq(order.Col("UserId")))).
+					FindMulti(ctx)
+				return err
+			},
+		}, {
+			name: "right join",
+			ctx:  tenantCtx,
+			mockFunc: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery("SELECT * FROM `tenant_test_model` AS `u` RIGHT JOIN `tenant_order_model` AS `o` ON `u`.`id` = `o`.`user_id` AND `u`.`tenant_id` = ? WHERE `o`.`tenant_id` = ?;").
+					WithArgs(7, 7).
+					WillReturnRows(sqlmock.NewRows([]string{"id", "tenant_id", "name"}))
+			},
+			execFunc: func(ctx context.Context, db *easyorm.DB) error {
+				user, order := easyorm.TableAs(&tenantTestModel{}, "u"), easyorm.TableAs(&tenantOrderModel{}, "o")
+				_, err := easyorm.NewSelector[tenantTestModel](db).
+					From(user.RightJoin(order).On(user.Col("Id").Eq(order.Col("UserId")))).
+					FindMulti(ctx)
+				return err
+			},
+		}, {
+			name: "right join using",
+			ctx:  tenantCtx,
+			mockFunc: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery("SELECT * FROM (SELECT * FROM `tenant_test_model` WHERE `tenant_test_model`.`tenant_id` = ?) AS `u` RIGHT JOIN `tenant_order_model` AS `o` USING (`id`) WHERE `o`.`tenant_id` = ?;").
+					WithArgs(7, 7).
+					WillReturnRows(sqlmock.NewRows([]string{"id", "tenant_id", "name"}))
+			},
+			execFunc: func(ctx context.Context, db *easyorm.DB) error {
+				user, order := easyorm.TableAs(&tenantTestModel{}, "u"), easyorm.TableAs(&tenantOrderModel{}, "o")
+				_, err := easyorm.NewSelector[tenantTestModel](db).
+					From(user.RightJoin(order).Using(user.Col("Id"))).
+					FindMulti(ctx)
+				return err
+			},
+		}, {
+			name:     "join tenant aware table without tenant",
+			ctx:      context.Background(),
+			mockFunc: func(mock sqlmock.Sqlmock) {},
+			execFunc: func(ctx context.Context, db *easyorm.DB) error {
+				plain, order := easyorm.TableOf(&plainTestModel{}), easyorm.TableOf(&tenantOrderModel{})
+				_, err := easyorm.NewSelector[plainTestModel](db).
+					From(plain.InnerJoin(order).On(plain.Col("Id").Eq(order.Col("UserId")))).
+					FindMulti(ctx)
+				return err
+			},
+			wantErr: errs.ErrMissingTenant,
+		}, {
+			name: "sub query",
+			ctx:  tenantCtx,
+			mockFunc: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery("SELECT * FROM `tenant_test_model` WHERE (`id` IN (SELECT `user_id` FROM `tenant_order_model` WHERE `tenant_id` = ?)) AND (`tenant_id` = ?);").
+					WithArgs(7, 7).
+					WillReturnRows(sqlmock.NewRows([]string{"id", "tenant_id", "name"}))
+			},
+			execFunc: func(ctx context.Context, db *easyorm.DB) error {
+				subQuery, err := easyorm.NewSelector[tenantOrderModel](db).Select(easyorm.Col("UserId")).ToSubQuery()
+				if err != nil {
+					return err
+				}
+				_, err = easyorm.NewSelector[tenantTestModel](db).Where(easyorm.Col("Id").InSubQuery(subQuery)).FindMulti(ctx)
+				return err
+			},
+		}, {
+			name: "sub query of tenant aware table only",
+			ctx:  tenantCtx,
+			mockFunc: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery("SELECT * FROM (SELECT * FROM `tenant_order_model` WHERE `tenant_id` = ?) AS `o`;").
+					WithArgs(7).
+					WillReturnRows(sqlmock.NewRows([]string{"id", "name"}))
+			},
+			execFunc: func(ctx context.Context, db *easyorm.DB) error {
+				subQuery, err := easyorm.NewSelector[tenantOrderModel](db).AsSubQuery("o")
+				if err != nil {
+					return err
+				}
+				_, err = easyorm.NewSelector[plainTestModel](db).From(subQuery).FindMulti(ctx)
+				return err
+			},
+		}, {
+			name: "insert from select",
+			ctx:  tenantCtx,
+			mockFunc: func(mock sqlmock.Sqlmock) {
+				mock.ExpectExec("INSERT INTO `plain_test_model` (`id`, `name`) SELECT `id`, `name` FROM `tenant_test_model` WHERE `tenant_id` = ?;").
+					WithArgs(7).
+					WillReturnResult(sqlmock.NewResult(1, 1))
+			},
+			execFunc: func(ctx context.Context, db *easyorm.DB) error {
+				subQuery, err := easyorm.NewSelector[tenantTestModel](db).
+					Select(easyorm.Col("Id"), easyorm.Col("Name")).
+					ToSubQuery()
+				if err != nil {
+					return err
+				}
+				return easyorm.NewInserter[plainTestModel](db).FromSelect(subQuery).Exec(ctx).Err()
+			},
+		}, {
+			name:     "insert tenant aware model from select",
+			ctx:      tenantCtx,
+			mockFunc: func(mock sqlmock.Sqlmock) {},
+			execFunc: func(ctx context.Context, db *easyorm.DB) error {
+				subQuery, err := easyorm.NewSelector[plainTestModel](db).ToSubQuery()
+				if err != nil {
+					return err
+				}
+				return easyorm.NewInserter[tenantTestModel](db).Fields("Id", "Name").FromSelect(subQuery).Exec(ctx).Err()
+			},
+			wantErr: errs.ErrScopeValueWithSelect,
+		}, {
+			name:     "missing tenant",
+			ctx:      context.Background(),
+			mockFunc: func(mock sqlmock.Sqlmock) {},
+			execFunc: func(ctx context.Context, db *easyorm.DB) error {
+				_, err := easyorm.NewSelector[tenantTestModel](db).FindMulti(ctx)
+				return err
+			},
+			wantErr: errs.ErrMissingTenant,
+		}, {
+			name: "not tenant aware",
+			ctx:  context.Background(),
+			mockFunc: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery("SELECT * FROM `plain_test_model`;").
+					WillReturnRows(sqlmock.NewRows([]string{"id", "name"}))
+			},
+			execFunc: func(ctx context.Context, db *easyorm.DB) error {
+				_, err := easyorm.NewSelector[plainTestModel](db).FindMulti(ctx)
+				return err
+			},
+		},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			mockDB, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+			require.NoError(t, err)
+			defer func() {
+				_ = mockDB.Close()
+			}()
+
+			db, err := easyorm.OpenDB(mockDB, easyorm.MySQLDialect, easyorm.DBWithMiddlewareChain(easyorm.MiddlewareChain{
+				NewMiddlewareBuilder().Build(),
+			}))
+			require.NoError(t, err)
+
+			tc.mockFunc(mock)
+
+			err = tc.execFunc(tc.ctx, db)
+			assert.Equal(t, tc.wantErr, err)
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
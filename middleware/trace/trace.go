@@ -27,8 +27,24 @@ func (m *MiddlewareBuilder) Build() easyorm.Middleware {
 
 	return func(next easyorm.HandleFunc) easyorm.HandleFunc {
 		return func(ctx context.Context, ormCtx *easyorm.OrmContext) *easyorm.OrmResult {
-			tableName := ormCtx.Model.TableName
-			spanCtx, span := m.tracer.Start(ctx, tableName, trace.WithSpanKind(trace.SpanKindClient))
+			switch ormCtx.Typ {
+			case easyorm.ScTypBEGIN:
+				return m.begin(ctx, ormCtx, next)
+			case easyorm.ScTypCOMMIT, easyorm.ScTypROLLBACK:
+				return m.finish(ctx, ormCtx, next)
+			}
+
+			var tableName string
+			if ormCtx.Model != nil {
+				tableName = ormCtx.Model.TableName
+			}
+
+			spanName := tableName
+			if spanName == "" {
+				spanName = ormCtx.Typ
+			}
+
+			spanCtx, span := m.tracer.Start(ctx, spanName, trace.WithSpanKind(trace.SpanKindClient))
 			defer span.End()
 
 			span.SetAttributes(attribute.String("orm.type", ormCtx.Typ))
@@ -53,6 +69,43 @@ func (m *MiddlewareBuilder) Build() easyorm.Middleware {
 	}
 }
 
+// txSpanKey key of the span covering the whole transaction in context.
+type txSpanKey struct{}
+
+// begin start the span of transaction, the span is the parent of spans of statements executed in transaction,
+// and it ends when the transaction is committed or rolled back.
+func (m *MiddlewareBuilder) begin(
+	ctx context.Context, ormCtx *easyorm.OrmContext, next easyorm.HandleFunc,
+) *easyorm.OrmResult {
+	spanCtx, span := m.tracer.Start(ctx, "transaction", trace.WithSpanKind(trace.SpanKindClient))
+	span.SetAttributes(attribute.String("orm.type", ormCtx.Typ))
+
+	res := next(context.WithValue(spanCtx, txSpanKey{}, span), ormCtx)
+	if res.Err != nil {
+		span.RecordError(res.Err)
+		span.End()
+	}
+	return res
+}
+
+func (m *MiddlewareBuilder) finish(
+	ctx context.Context, ormCtx *easyorm.OrmContext, next easyorm.HandleFunc,
+) *easyorm.OrmResult {
+	res := next(ctx, ormCtx)
+
+	span, ok := ctx.Value(txSpanKey{}).(trace.Span)
+	if !ok {
+		return res
+	}
+
+	span.SetAttributes(attribute.String("orm.tx.result", ormCtx.Typ))
+	if res.Err != nil {
+		span.RecordError(res.Err)
+	}
+	span.End()
+	return res
+}
+
 func NewMiddlewareBuilder() *MiddlewareBuilder {
 	return &MiddlewareBuilder{}
 }
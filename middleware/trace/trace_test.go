@@ -1 +1,122 @@
 package trace
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	easyorm "github.com/JrMarcco/easy-orm"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/trace"
+	"go.opentelemetry.io/otel/trace/noop"
+)
+
+type traceTestModel struct {
+	Id   uint64
+	Name string
+}
+
+type recordSpan struct {
+	noop.Span
+
+	name   string
+	parent string
+	ended  bool
+	errs   []error
+}
+
+func (s *recordSpan) End(...trace.SpanEndOption) {
+	s.ended = true
+}
+
+func (s *recordSpan) RecordError(err error, _ ...trace.EventOption) {
+	s.errs = append(s.errs, err)
+}
+
+type recordTracer struct {
+	noop.Tracer
+
+	spans []*recordSpan
+}
+
+func (t *recordTracer) Start(
+	ctx context.Context, spanName string, _ ...trace.SpanStartOption,
+) (context.Context, trace.Span) {
+	span := &recordSpan{name: spanName}
+	if parent, ok := trace.SpanFromContext(ctx).(*recordSpan); ok {
+		span.parent = parent.name
+	}
+
+	t.spans = append(t.spans, span)
+	return trace.ContextWithSpan(ctx, span), span
+}
+
+func TestMiddlewareBuilder_Build_Tx(t *testing.T) {
+	tcs := []struct {
+		name     string
+		mockFunc func(mock sqlmock.Sqlmock)
+		bizErr   error
+	}{
+		{
+			name: "commit",
+			mockFunc: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectExec("INSERT INTO .*").WillReturnResult(sqlmock.NewResult(1, 1))
+				mock.ExpectCommit()
+			},
+		}, {
+			name: "rollback",
+			mockFunc: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectExec("INSERT INTO .*").WillReturnResult(sqlmock.NewResult(1, 1))
+				mock.ExpectRollback()
+			},
+			bizErr: errors.New("biz error"),
+		},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			mockDB, mock, err := sqlmock.New()
+			require.NoError(t, err)
+			defer func() {
+				_ = mockDB.Close()
+			}()
+
+			tracer := &recordTracer{}
+			db, err := easyorm.OpenDB(mockDB, easyorm.PostgresDialect, easyorm.DBWithMiddlewareChain(easyorm.MiddlewareChain{
+				NewMiddlewareBuilder().WithTracer(tracer).Build(),
+			}))
+			require.NoError(t, err)
+
+			tc.mockFunc(mock)
+
+			err = db.DoTx(context.Background(), func(ctx context.Context, tx *easyorm.Tx) error {
+				res := easyorm.NewInserter[traceTestModel](db).Rows(&traceTestModel{Id: 1, Name: "foo"}).Exec(ctx)
+				if res.Err() != nil {
+					return res.Err()
+				}
+				return tc.bizErr
+			}, nil)
+			if tc.bizErr != nil {
+				require.Error(t, err)
+			} else {
+				require.NoError(t, err)
+			}
+
+			require.Len(t, tracer.spans, 2)
+
+			txSpan, stmtSpan := tracer.spans[0], tracer.spans[1]
+			assert.Equal(t, "transaction", txSpan.name)
+			assert.True(t, txSpan.ended)
+
+			assert.Equal(t, "trace_test_model", stmtSpan.name)
+			assert.Equal(t, "transaction", stmtSpan.parent)
+			assert.True(t, stmtSpan.ended)
+
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
@@ -0,0 +1,106 @@
+package migrate
+
+import (
+	"context"
+	"io/fs"
+	"path"
+	"regexp"
+	"strconv"
+
+	easyorm "github.com/JrMarcco/easy-orm"
+	"github.com/JrMarcco/easy-orm/internal/errs"
+	"github.com/JrMarcco/easy-orm/schema"
+)
+
+// Func the function migrating schema, statements executed on db with ctx run in the transaction of migration if any.
+type Func func(ctx context.Context, db *easyorm.DB) error
+
+// Migration the versioned change of schema, applied in ascending order of version.
+type Migration struct {
+	// Version the positive and unique version, like 1 or 20240101120000.
+	Version int64
+	Name    string
+	Up      Func
+	// Down reverts the change made by Up, nil if the migration is irreversible.
+	Down Func
+	// NoTx runs the migration without transaction, like "CREATE INDEX CONCURRENTLY" of postgres.
+	NoTx bool
+}
+
+// SQL returns the Func executing the statements in order.
+func SQL(stmts ...string) Func {
+	return func(ctx context.Context, db *easyorm.DB) error {
+		for _, stmt := range stmts {
+			if err := easyorm.NewRaw[any](db, stmt).Exec(ctx).Err(); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+}
+
+// sqlFileName the name of sql file like "1_create_user.up.sql" or "1_create_user.down.sql".
+var sqlFileName = regexp.MustCompile(`^(\d+)_(.+)\.(up|down)\.sql$`)
+
+// FromFS load the migrations from sql files in dir of fsys, which is usually embed.FS, like:
+//
+//	//go:embed migrations/*.sql
+//	var migrations embed.FS
+//
+//	FromFS(migrations, "migrations")
+//
+// the files are named "<version>_<name>.up.sql" and "<version>_<name>.down.sql", the down file is optional.
+// Files not ending with ".sql" are ignored.
+func FromFS(fsys fs.FS, dir string) ([]Migration, error) {
+	entries, err := fs.ReadDir(fsys, dir)
+	if err != nil {
+		return nil, err
+	}
+
+	migrations := make(map[int64]*Migration, len(entries))
+	for _, entry := range entries {
+		if entry.IsDir() || path.Ext(entry.Name()) != ".sql" {
+			continue
+		}
+
+		matches := sqlFileName.FindStringSubmatch(entry.Name())
+		if matches == nil {
+			return nil, errs.ErrInvalidMigration(entry.Name())
+		}
+
+		version, err := strconv.ParseInt(matches[1], 10, 64)
+		if err != nil {
+			return nil, errs.ErrInvalidMigration(entry.Name())
+		}
+
+		content, err := fs.ReadFile(fsys, path.Join(dir, entry.Name()))
+		if err != nil {
+			return nil, err
+		}
+
+		mig, ok := migrations[version]
+		if !ok {
+			mig = &Migration{Version: version, Name: matches[2]}
+			migrations[version] = mig
+		}
+		if mig.Name != matches[2] {
+			return nil, errs.ErrDuplicateMigration(version)
+		}
+
+		fn := SQL(schema.SplitStatements(string(content))...)
+		if matches[3] == "up" {
+			mig.Up = fn
+		} else {
+			mig.Down = fn
+		}
+	}
+
+	res := make([]Migration, 0, len(migrations))
+	for _, mig := range migrations {
+		if mig.Up == nil {
+			return nil, errs.ErrInvalidMigration(strconv.FormatInt(mig.Version, 10) + "_" + mig.Name + ".up.sql")
+		}
+		res = append(res, *mig)
+	}
+	return res, nil
+}
@@ -0,0 +1,90 @@
+package migrate
+
+import (
+	"testing"
+	"testing/fstest"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	easyorm "github.com/JrMarcco/easy-orm"
+	"github.com/JrMarcco/easy-orm/internal/errs"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFromFS(t *testing.T) {
+	tcs := []struct {
+		name         string
+		fsys         fstest.MapFS
+		wantVersions map[int64]string
+		wantDown     map[int64]bool
+		wantErr      error
+	}{
+		{
+			name: "up and down",
+			fsys: fstest.MapFS{
+				"migrations/1_create_user.up.sql":   {Data: []byte("CREATE TABLE `user` (`id` BIGINT);")},
+				"migrations/1_create_user.down.sql": {Data: []byte("DROP TABLE `user`;")},
+				"migrations/2_seed.up.sql":          {Data: []byte("INSERT INTO `user` VALUES (1);")},
+				"migrations/README.md":              {Data: []byte("ignored")},
+			},
+			wantVersions: map[int64]string{1: "create_user", 2: "seed"},
+			wantDown:     map[int64]bool{1: true, 2: false},
+		}, {
+			name: "invalid name",
+			fsys: fstest.MapFS{
+				"migrations/create_user.up.sql": {Data: []byte("")},
+			},
+			wantErr: errs.ErrInvalidMigration("create_user.up.sql"),
+		}, {
+			name: "without up",
+			fsys: fstest.MapFS{
+				"migrations/1_create_user.down.sql": {Data: []byte("")},
+			},
+			wantErr: errs.ErrInvalidMigration("1_create_user.up.sql"),
+		}, {
+			name: "duplicate",
+			fsys: fstest.MapFS{
+				"migrations/1_create_user.up.sql":  {Data: []byte("")},
+				"migrations/1_create_order.up.sql": {Data: []byte("")},
+			},
+			wantErr: errs.ErrDuplicateMigration(1),
+		},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			migrations, err := FromFS(tc.fsys, "migrations")
+			assert.Equal(t, tc.wantErr, err)
+			if err != nil {
+				return
+			}
+
+			require.Len(t, migrations, len(tc.wantVersions))
+			for _, mig := range migrations {
+				assert.Equal(t, tc.wantVersions[mig.Version], mig.Name)
+				assert.NotNil(t, mig.Up)
+				assert.Equal(t, tc.wantDown[mig.Version], mig.Down != nil)
+			}
+		})
+	}
+}
+
+func TestSQL(t *testing.T) {
+	mockDB, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+	require.NoError(t, err)
+	defer func() { _ = mockDB.Close() }()
+
+	db, err := easyorm.OpenDB(mockDB, easyorm.MySQLDialect)
+	require.NoError(t, err)
+
+	migrations, err := FromFS(fstest.MapFS{
+		"1_init.up.sql": {Data: []byte("CREATE TABLE `a` (`id` BIGINT);\nCREATE TABLE `b` (`id` BIGINT);\n")},
+	}, ".")
+	require.NoError(t, err)
+
+	mock.ExpectExec("CREATE TABLE `a` (`id` BIGINT)").WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectExec("CREATE TABLE `b` (`id` BIGINT)").WillReturnResult(sqlmock.NewResult(0, 0))
+
+	require.NoError(t, migrations[0].Up(t.Context(), db))
+	require.NoError(t, mock.ExpectationsWereMet())
+}
@@ -0,0 +1,389 @@
+package migrate
+
+import (
+	"cmp"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"slices"
+	"strconv"
+	"time"
+
+	easyorm "github.com/JrMarcco/easy-orm"
+	"github.com/JrMarcco/easy-orm/internal/errs"
+	"github.com/JrMarcco/easy-orm/model"
+	"github.com/JrMarcco/easy-orm/schema"
+)
+
+const (
+	defaultTable = "schema_migrations"
+
+	// lockPollInterval the interval of retrying to acquire the lock held by another runner.
+	lockPollInterval = 500 * time.Millisecond
+)
+
+// ErrMigrationLocked the lock is held by another runner, see Migrator.Unlock for releasing the stale lock.
+var ErrMigrationLocked = errs.ErrMigrationLocked
+
+// schemaMigration the row of applied migration.
+type schemaMigration struct {
+	Version   int64     `orm:"pk"`
+	Name      string    `orm:"size=255"`
+	AppliedAt time.Time `orm:"auto_create_time"`
+}
+
+// migrationLock the single row held by the running migrator.
+type migrationLock struct {
+	Id       int64     `orm:"pk"`
+	Owner    string    `orm:"size=64"`
+	LockedAt time.Time `orm:"auto_create_time"`
+}
+
+// Status the status of migration.
+type Status struct {
+	Version int64
+	Name    string
+	Applied bool
+	// AppliedAt the time of migration applied, zero if not applied.
+	AppliedAt time.Time
+	// Unknown the migration is applied but not in the migrations of migrator.
+	Unknown bool
+}
+
+// Migrator runs the migrations on DB, the applied migrations are tracked in table "schema_migrations",
+// and runners are excluded from each other by the lock row in table "schema_migrations_lock".
+//
+// Each migration runs in its own transaction if the dialect supports transactional DDL, so that a failed migration
+// leaves nothing behind, otherwise the migration is applied statement by statement.
+type Migrator struct {
+	db         *easyorm.DB
+	dialect    schema.Dialect
+	migrations []Migration
+
+	table    string
+	lockWait time.Duration
+	owner    string
+}
+
+type MigratorOpt func(m *Migrator)
+
+// MigratorWithTable set the table tracking applied migrations, the lock table is suffixed by "_lock".
+func MigratorWithTable(table string) MigratorOpt {
+	return func(m *Migrator) {
+		m.table = table
+	}
+}
+
+// MigratorWithLockWait set how long to wait for the lock held by another runner, fail immediately by default.
+func MigratorWithLockWait(wait time.Duration) MigratorOpt {
+	return func(m *Migrator) {
+		m.lockWait = wait
+	}
+}
+
+// NewMigrator create the migrator of migrations,
+// the models of tracking tables are registered on the registry of DB.
+func NewMigrator(db *easyorm.DB, migrations []Migration, opts ...MigratorOpt) (*Migrator, error) {
+	dialect, err := schema.DialectOf(db.Dialect())
+	if err != nil {
+		return nil, err
+	}
+
+	sorted := slices.Clone(migrations)
+	slices.SortFunc(sorted, func(a, b Migration) int {
+		return cmp.Compare(a.Version, b.Version)
+	})
+	for i, mig := range sorted {
+		if mig.Version <= 0 || mig.Up == nil {
+			return nil, errs.ErrInvalidMigration(strconv.FormatInt(mig.Version, 10) + "_" + mig.Name)
+		}
+		if i > 0 && sorted[i-1].Version == mig.Version {
+			return nil, errs.ErrDuplicateMigration(mig.Version)
+		}
+	}
+
+	owner := make([]byte, 8)
+	_, _ = rand.Read(owner)
+
+	m := &Migrator{
+		db:         db,
+		dialect:    dialect,
+		migrations: sorted,
+		table:      defaultTable,
+		owner:      hex.EncodeToString(owner),
+	}
+	for _, opt := range opts {
+		opt(m)
+	}
+
+	if _, err = db.Registry().RegisterModel(&schemaMigration{}, model.WithTableOpt(m.table)); err != nil {
+		return nil, err
+	}
+	if _, err = db.Registry().RegisterModel(&migrationLock{}, model.WithTableOpt(m.table+"_lock")); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// Up applies all pending migrations in ascending order of version.
+func (m *Migrator) Up(ctx context.Context) error {
+	return m.locked(ctx, func(applied map[int64]*schemaMigration) error {
+		for _, mig := range m.migrations {
+			if _, ok := applied[mig.Version]; ok {
+				continue
+			}
+			if err := m.apply(ctx, mig, true); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// Down reverts the last n applied migrations in descending order of version.
+func (m *Migrator) Down(ctx context.Context, n int) error {
+	return m.locked(ctx, func(applied map[int64]*schemaMigration) error {
+		versions := appliedVersions(applied)
+		for i := len(versions) - 1; i >= 0 && n > 0; i, n = i-1, n-1 {
+			if err := m.revert(ctx, versions[i]); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// Goto migrates to the version, reverts the applied migrations after the version,
+// then applies the pending migrations up to the version. Goto(ctx, 0) reverts all.
+func (m *Migrator) Goto(ctx context.Context, version int64) error {
+	return m.locked(ctx, func(applied map[int64]*schemaMigration) error {
+		versions := appliedVersions(applied)
+		for i := len(versions) - 1; i >= 0 && versions[i] > version; i-- {
+			if err := m.revert(ctx, versions[i]); err != nil {
+				return err
+			}
+		}
+
+		for _, mig := range m.migrations {
+			if mig.Version > version {
+				break
+			}
+			if _, ok := applied[mig.Version]; ok {
+				continue
+			}
+			if err := m.apply(ctx, mig, true); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// Status returns the status of migrations in ascending order of version,
+// including the applied migrations unknown to migrator.
+// Status is read only, none of migrations is applied if the tracking table does not exist.
+func (m *Migrator) Status(ctx context.Context) ([]Status, error) {
+	mdl, err := m.db.Registry().GetModel(&schemaMigration{})
+	if err != nil {
+		return nil, err
+	}
+
+	exists, err := schema.Exists(ctx, m.db, m.dialect, mdl)
+	if err != nil {
+		return nil, err
+	}
+
+	applied := map[int64]*schemaMigration{}
+	if exists {
+		if applied, err = m.applied(ctx); err != nil {
+			return nil, err
+		}
+	}
+
+	res := make([]Status, 0, len(m.migrations))
+	for _, mig := range m.migrations {
+		status := Status{Version: mig.Version, Name: mig.Name}
+		if row, ok := applied[mig.Version]; ok {
+			status.Applied, status.AppliedAt = true, row.AppliedAt
+			delete(applied, mig.Version)
+		}
+		res = append(res, status)
+	}
+
+	for _, row := range applied {
+		res = append(res, Status{
+			Version:   row.Version,
+			Name:      row.Name,
+			Applied:   true,
+			AppliedAt: row.AppliedAt,
+			Unknown:   true,
+		})
+	}
+
+	slices.SortFunc(res, func(a, b Status) int {
+		return cmp.Compare(a.Version, b.Version)
+	})
+	return res, nil
+}
+
+// Unlock releases the lock whoever holds it, used to recover from the runner crashed without releasing.
+func (m *Migrator) Unlock(ctx context.Context) error {
+	return easyorm.NewDeleter[migrationLock](m.db).Where(easyorm.Col("Id").Eq(1)).Exec(ctx).Err()
+}
+
+// locked runs the function with the applied migrations while holding the lock.
+func (m *Migrator) locked(ctx context.Context, fn func(applied map[int64]*schemaMigration) error) (err error) {
+	if err = m.createTables(ctx); err != nil {
+		return err
+	}
+
+	if err = m.lock(ctx); err != nil {
+		return err
+	}
+	defer func() {
+		if unlockErr := m.unlock(ctx); unlockErr != nil {
+			err = errors.Join(err, unlockErr)
+		}
+	}()
+
+	applied, err := m.applied(ctx)
+	if err != nil {
+		return err
+	}
+	return fn(applied)
+}
+
+// createTables create the tracking tables if not exist.
+func (m *Migrator) createTables(ctx context.Context) error {
+	for _, entity := range []any{&schemaMigration{}, &migrationLock{}} {
+		mdl, err := m.db.Registry().GetModel(entity)
+		if err != nil {
+			return err
+		}
+
+		t, err := schema.TableOf(m.dialect, mdl)
+		if err != nil {
+			return err
+		}
+
+		if err = easyorm.NewRaw[any](m.db, schema.CreateTableIfNotExists(m.dialect, t)).Exec(ctx).Err(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// lock inserts the lock row, which conflicts on primary key if another runner holds it,
+// the lock row is read from primary as replicas may lag behind.
+func (m *Migrator) lock(ctx context.Context) error {
+	deadline := time.Now().Add(m.lockWait)
+	for {
+		err := easyorm.NewInserter[migrationLock](m.db).Rows(&migrationLock{Id: 1, Owner: m.owner}).Exec(ctx).Err()
+		if err == nil {
+			return nil
+		}
+
+		// the insertion failed for other reasons if there is no lock row
+		if _, findErr := easyorm.NewSelector[migrationLock](m.db).
+			Where(easyorm.Col("Id").Eq(1)).FindOne(easyorm.WithPrimary(ctx)); findErr != nil {
+			if errors.Is(findErr, errs.ErrEligibleRow) {
+				return err
+			}
+			return findErr
+		}
+
+		if time.Now().Add(lockPollInterval).After(deadline) {
+			return ErrMigrationLocked
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(lockPollInterval):
+		}
+	}
+}
+
+// unlock deletes the lock row held by this runner.
+func (m *Migrator) unlock(ctx context.Context) error {
+	return easyorm.NewDeleter[migrationLock](m.db).
+		Where(easyorm.Col("Id").Eq(1), easyorm.Col("Owner").Eq(m.owner)).
+		Exec(context.WithoutCancel(ctx)).Err()
+}
+
+// applied returns the applied migrations keyed by version, which are read from primary as replicas may lag behind.
+func (m *Migrator) applied(ctx context.Context) (map[int64]*schemaMigration, error) {
+	rows, err := easyorm.NewSelector[schemaMigration](m.db).
+		OrderBy(easyorm.Asc("Version")).
+		FindMulti(easyorm.WithPrimary(ctx))
+	if err != nil {
+		return nil, err
+	}
+
+	applied := make(map[int64]*schemaMigration, len(rows))
+	for _, row := range rows {
+		applied[row.Version] = row
+	}
+	return applied, nil
+}
+
+// revert reverts the applied migration of version.
+func (m *Migrator) revert(ctx context.Context, version int64) error {
+	i, ok := slices.BinarySearchFunc(m.migrations, version, func(mig Migration, v int64) int {
+		return cmp.Compare(mig.Version, v)
+	})
+	if !ok {
+		return errs.ErrUnknownMigration(version)
+	}
+
+	if m.migrations[i].Down == nil {
+		return errs.ErrIrreversibleMigration(version)
+	}
+	return m.apply(ctx, m.migrations[i], false)
+}
+
+// apply runs the up or down of migration and records it, in transaction if the dialect supports.
+func (m *Migrator) apply(ctx context.Context, mig Migration, up bool) error {
+	direction, fn := "up", mig.Up
+	if !up {
+		direction, fn = "down", mig.Down
+	}
+
+	migrate := func(ctx context.Context) error {
+		if err := fn(ctx, m.db); err != nil {
+			return err
+		}
+
+		if up {
+			return easyorm.NewInserter[schemaMigration](m.db).
+				Rows(&schemaMigration{Version: mig.Version, Name: mig.Name}).Exec(ctx).Err()
+		}
+		return easyorm.NewDeleter[schemaMigration](m.db).
+			Where(easyorm.Col("Version").Eq(mig.Version)).Exec(ctx).Err()
+	}
+
+	var err error
+	if mig.NoTx || !schema.TransactionalDDL(m.dialect) {
+		err = migrate(ctx)
+	} else {
+		err = m.db.DoTx(ctx, func(ctx context.Context, _ *easyorm.Tx) error {
+			return migrate(ctx)
+		}, nil)
+	}
+
+	if err != nil {
+		return errs.ErrMigrationFailed(mig.Version, direction, err)
+	}
+	return nil
+}
+
+// appliedVersions returns the versions of applied migrations in ascending order.
+func appliedVersions(applied map[int64]*schemaMigration) []int64 {
+	versions := make([]int64, 0, len(applied))
+	for version := range applied {
+		versions = append(versions, version)
+	}
+	slices.Sort(versions)
+	return versions
+}
@@ -0,0 +1,365 @@
+package migrate
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	easyorm "github.com/JrMarcco/easy-orm"
+	"github.com/JrMarcco/easy-orm/internal/errs"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var migratorTestNow = time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+var migratorTestMigrations = []Migration{
+	{
+		Version: 2,
+		Name:    "add_user_age",
+		Up:      SQL("ALTER TABLE `user` ADD COLUMN `age` INT;"),
+		Down:    SQL("ALTER TABLE `user` DROP COLUMN `age`;"),
+	}, {
+		Version: 1,
+		Name:    "create_user",
+		Up:      SQL("CREATE TABLE `user` (`id` BIGINT);"),
+		Down:    SQL("DROP TABLE `user`;"),
+	}, {
+		Version: 3,
+		Name:    "seed_user",
+		Up: func(ctx context.Context, db *easyorm.DB) error {
+			return easyorm.NewRaw[any](db, "INSERT INTO `user` (`id`) VALUES (?);", 1).Exec(ctx).Err()
+		},
+	},
+}
+
+func newMigratorTest(t *testing.T, dialect easyorm.Dialect, opts ...MigratorOpt) (*Migrator, sqlmock.Sqlmock) {
+	mockDB, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = mockDB.Close() })
+
+	db, err := easyorm.OpenDB(mockDB, dialect, easyorm.DBWithClock(func() time.Time { return migratorTestNow }))
+	require.NoError(t, err)
+
+	m, err := NewMigrator(db, migratorTestMigrations, opts...)
+	require.NoError(t, err)
+	return m, mock
+}
+
+// expectLocked expect creating tables, acquiring the lock and querying the applied versions.
+func expectLocked(mock sqlmock.Sqlmock, m *Migrator, applied ...int64) {
+	mock.ExpectExec("CREATE TABLE IF NOT EXISTS `schema_migrations` (`version` BIGINT NOT NULL, " +
+		"`name` VARCHAR(255) NOT NULL, `applied_at` DATETIME NOT NULL, PRIMARY KEY (`version`));").
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectExec("CREATE TABLE IF NOT EXISTS `schema_migrations_lock` (`id` BIGINT NOT NULL, " +
+		"`owner` VARCHAR(64) NOT NULL, `locked_at` DATETIME NOT NULL, PRIMARY KEY (`id`));").
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectExec("INSERT INTO `schema_migrations_lock` (`id`, `owner`, `locked_at`) VALUES (?, ?, ?);").
+		WithArgs(int64(1), m.owner, migratorTestNow).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+
+	rows := sqlmock.NewRows([]string{"version", "name", "applied_at"})
+	for _, version := range applied {
+		rows.AddRow(version, "", migratorTestNow)
+	}
+	mock.ExpectQuery("SELECT * FROM `schema_migrations` ORDER BY `version` ASC;").WillReturnRows(rows)
+}
+
+func expectUnlock(mock sqlmock.Sqlmock, m *Migrator) {
+	mock.ExpectExec("DELETE FROM `schema_migrations_lock` WHERE (`id` = ?) AND (`owner` = ?);").
+		WithArgs(1, m.owner).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+}
+
+func expectApplied(mock sqlmock.Sqlmock, version int64, name string) {
+	mock.ExpectExec("INSERT INTO `schema_migrations` (`version`, `name`, `applied_at`) VALUES (?, ?, ?);").
+		WithArgs(version, name, migratorTestNow).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+}
+
+func expectReverted(mock sqlmock.Sqlmock, version int64) {
+	mock.ExpectExec("DELETE FROM `schema_migrations` WHERE `version` = ?;").
+		WithArgs(version).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+}
+
+func TestMigrator_Up(t *testing.T) {
+	m, mock := newMigratorTest(t, easyorm.MySQLDialect)
+
+	expectLocked(mock, m, 1)
+	mock.ExpectExec("ALTER TABLE `user` ADD COLUMN `age` INT;").WillReturnResult(sqlmock.NewResult(0, 0))
+	expectApplied(mock, 2, "add_user_age")
+	mock.ExpectExec("INSERT INTO `user` (`id`) VALUES (?);").WithArgs(1).WillReturnResult(sqlmock.NewResult(1, 1))
+	expectApplied(mock, 3, "seed_user")
+	expectUnlock(mock, m)
+
+	require.NoError(t, m.Up(t.Context()))
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestMigrator_Up_Transactional(t *testing.T) {
+	m, mock := newMigratorTest(t, easyorm.SQLiteDialect)
+
+	mock.ExpectExec(`CREATE TABLE IF NOT EXISTS "schema_migrations" ("version" INTEGER NOT NULL, ` +
+		`"name" VARCHAR(255) NOT NULL, "applied_at" DATETIME NOT NULL, PRIMARY KEY ("version"));`).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectExec(`CREATE TABLE IF NOT EXISTS "schema_migrations_lock" ("id" INTEGER NOT NULL, ` +
+		`"owner" VARCHAR(64) NOT NULL, "locked_at" DATETIME NOT NULL, PRIMARY KEY ("id"));`).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectExec(`INSERT INTO "schema_migrations_lock" ("id", "owner", "locked_at") VALUES (?, ?, ?);`).
+		WithArgs(int64(1), m.owner, migratorTestNow).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectQuery(`SELECT * FROM "schema_migrations" ORDER BY "version" ASC;`).
+		WillReturnRows(sqlmock.NewRows([]string{"version", "name", "applied_at"}))
+
+	mock.ExpectBegin()
+	mock.ExpectExec("CREATE TABLE `user` (`id` BIGINT);").WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectExec(`INSERT INTO "schema_migrations" ("version", "name", "applied_at") VALUES (?, ?, ?);`).
+		WithArgs(int64(1), "create_user", migratorTestNow).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	// the failed migration is rolled back with its record
+	mock.ExpectBegin()
+	mock.ExpectExec("ALTER TABLE `user` ADD COLUMN `age` INT;").WillReturnError(errors.New("mock error"))
+	mock.ExpectRollback()
+
+	mock.ExpectExec(`DELETE FROM "schema_migrations_lock" WHERE ("id" = ?) AND ("owner" = ?);`).
+		WithArgs(1, m.owner).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	err := m.Up(t.Context())
+	assert.ErrorContains(t, err, "failed to migrate up 2: ")
+	assert.ErrorContains(t, err, "mock error")
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestMigrator_Down(t *testing.T) {
+	tcs := []struct {
+		name    string
+		applied []int64
+		n       int
+		mock    func(mock sqlmock.Sqlmock)
+		wantErr error
+	}{
+		{
+			name:    "down 2",
+			applied: []int64{1, 2},
+			n:       2,
+			mock: func(mock sqlmock.Sqlmock) {
+				mock.ExpectExec("ALTER TABLE `user` DROP COLUMN `age`;").WillReturnResult(sqlmock.NewResult(0, 0))
+				expectReverted(mock, 2)
+				mock.ExpectExec("DROP TABLE `user`;").WillReturnResult(sqlmock.NewResult(0, 0))
+				expectReverted(mock, 1)
+			},
+		}, {
+			name:    "more than applied",
+			applied: []int64{1},
+			n:       3,
+			mock: func(mock sqlmock.Sqlmock) {
+				mock.ExpectExec("DROP TABLE `user`;").WillReturnResult(sqlmock.NewResult(0, 0))
+				expectReverted(mock, 1)
+			},
+		}, {
+			name:    "irreversible",
+			applied: []int64{1, 2, 3},
+			n:       1,
+			mock:    func(mock sqlmock.Sqlmock) {},
+			wantErr: errs.ErrIrreversibleMigration(3),
+		}, {
+			name:    "unknown",
+			applied: []int64{1, 4},
+			n:       1,
+			mock:    func(mock sqlmock.Sqlmock) {},
+			wantErr: errs.ErrUnknownMigration(4),
+		},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			m, mock := newMigratorTest(t, easyorm.MySQLDialect)
+
+			expectLocked(mock, m, tc.applied...)
+			tc.mock(mock)
+			expectUnlock(mock, m)
+
+			err := m.Down(t.Context(), tc.n)
+			assert.Equal(t, tc.wantErr, err)
+			require.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestMigrator_Goto(t *testing.T) {
+	m, mock := newMigratorTest(t, easyorm.MySQLDialect)
+
+	// applied out of order, 2 is pending while 3 is applied
+	expectLocked(mock, m, 1, 3)
+	mock.ExpectExec("ALTER TABLE `user` ADD COLUMN `age` INT;").WillReturnResult(sqlmock.NewResult(0, 0))
+	expectApplied(mock, 2, "add_user_age")
+	expectUnlock(mock, m)
+	require.NoError(t, m.Goto(t.Context(), 3))
+
+	expectLocked(mock, m, 1, 2)
+	mock.ExpectExec("ALTER TABLE `user` DROP COLUMN `age`;").WillReturnResult(sqlmock.NewResult(0, 0))
+	expectReverted(mock, 2)
+	expectUnlock(mock, m)
+	require.NoError(t, m.Goto(t.Context(), 1))
+
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
+// expectTableExists expect querying the columns of tracking table.
+func expectTableExists(mock sqlmock.Sqlmock, exists bool) {
+	rows := sqlmock.NewRows([]string{"name", "type", "nullable", "dflt"})
+	if exists {
+		rows.AddRow("version", "bigint", false, nil)
+	}
+	mock.ExpectQuery("SELECT column_name AS name, column_type AS type, is_nullable = 'YES' AS nullable, " +
+		"column_default AS dflt FROM information_schema.columns " +
+		"WHERE table_schema = DATABASE() AND table_name = ? ORDER BY ordinal_position;").
+		WithArgs("schema_migrations").
+		WillReturnRows(rows)
+}
+
+func TestMigrator_Status(t *testing.T) {
+	m, mock := newMigratorTest(t, easyorm.MySQLDialect)
+
+	t.Run("not migrated", func(t *testing.T) {
+		expectTableExists(mock, false)
+
+		status, err := m.Status(t.Context())
+		require.NoError(t, err)
+		assert.Equal(t, []Status{
+			{Version: 1, Name: "create_user"},
+			{Version: 2, Name: "add_user_age"},
+			{Version: 3, Name: "seed_user"},
+		}, status)
+	})
+
+	expectTableExists(mock, true)
+	mock.ExpectQuery("SELECT * FROM `schema_migrations` ORDER BY `version` ASC;").
+		WillReturnRows(sqlmock.NewRows([]string{"version", "name", "applied_at"}).
+			AddRow(1, "create_user", migratorTestNow).
+			AddRow(5, "dropped", migratorTestNow))
+
+	status, err := m.Status(t.Context())
+	require.NoError(t, err)
+	assert.Equal(t, []Status{
+		{Version: 1, Name: "create_user", Applied: true, AppliedAt: migratorTestNow},
+		{Version: 2, Name: "add_user_age"},
+		{Version: 3, Name: "seed_user"},
+		{Version: 5, Name: "dropped", Applied: true, AppliedAt: migratorTestNow, Unknown: true},
+	}, status)
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestMigrator_Lock(t *testing.T) {
+	m, mock := newMigratorTest(t, easyorm.MySQLDialect, MigratorWithLockWait(600*time.Millisecond))
+
+	mock.ExpectExec("CREATE TABLE IF NOT EXISTS `schema_migrations` (`version` BIGINT NOT NULL, " +
+		"`name` VARCHAR(255) NOT NULL, `applied_at` DATETIME NOT NULL, PRIMARY KEY (`version`));").
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectExec("CREATE TABLE IF NOT EXISTS `schema_migrations_lock` (`id` BIGINT NOT NULL, " +
+		"`owner` VARCHAR(64) NOT NULL, `locked_at` DATETIME NOT NULL, PRIMARY KEY (`id`));").
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	for range 2 {
+		mock.ExpectExec("INSERT INTO `schema_migrations_lock` (`id`, `owner`, `locked_at`) VALUES (?, ?, ?);").
+			WithArgs(int64(1), m.owner, migratorTestNow).
+			WillReturnError(errors.New("duplicate entry"))
+		mock.ExpectQuery("SELECT * FROM `schema_migrations_lock` WHERE `id` = ? LIMIT 1;").
+			WithArgs(1).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "owner", "locked_at"}).AddRow(1, "other", migratorTestNow))
+	}
+
+	assert.Equal(t, ErrMigrationLocked, m.Up(t.Context()))
+
+	mock.ExpectExec("DELETE FROM `schema_migrations_lock` WHERE `id` = ?;").
+		WithArgs(1).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	require.NoError(t, m.Unlock(t.Context()))
+
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestMigrator_Replica(t *testing.T) {
+	mockDB, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+	require.NoError(t, err)
+	defer func() { _ = mockDB.Close() }()
+
+	replicaDB, replicaMock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+	require.NoError(t, err)
+	defer func() { _ = replicaDB.Close() }()
+
+	db, err := easyorm.OpenDB(mockDB, easyorm.MySQLDialect,
+		easyorm.DBWithClock(func() time.Time { return migratorTestNow }),
+		easyorm.DBWithReplicas(replicaDB),
+	)
+	require.NoError(t, err)
+
+	m, err := NewMigrator(db, migratorTestMigrations, MigratorWithLockWait(0))
+	require.NoError(t, err)
+
+	expectLocked(mock, m, 1, 2, 3)
+	expectUnlock(mock, m)
+	require.NoError(t, m.Up(t.Context()))
+
+	mock.ExpectExec("INSERT INTO `schema_migrations_lock` (`id`, `owner`, `locked_at`) VALUES (?, ?, ?);").
+		WithArgs(int64(1), m.owner, migratorTestNow).
+		WillReturnError(errors.New("duplicate entry"))
+	mock.ExpectQuery("SELECT * FROM `schema_migrations_lock` WHERE `id` = ? LIMIT 1;").
+		WithArgs(1).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "owner", "locked_at"}).AddRow(1, "other", migratorTestNow))
+	assert.Equal(t, ErrMigrationLocked, m.lock(t.Context()))
+
+	require.NoError(t, mock.ExpectationsWereMet())
+	require.NoError(t, replicaMock.ExpectationsWereMet())
+}
+
+func TestNewMigrator(t *testing.T) {
+	noop := func(ctx context.Context, db *easyorm.DB) error { return nil }
+
+	tcs := []struct {
+		name       string
+		dialect    easyorm.Dialect
+		migrations []Migration
+		wantErr    error
+	}{
+		{
+			name:       "duplicate",
+			dialect:    easyorm.MySQLDialect,
+			migrations: []Migration{{Version: 1, Up: noop}, {Version: 1, Up: noop}},
+			wantErr:    errs.ErrDuplicateMigration(1),
+		}, {
+			name:       "without up",
+			dialect:    easyorm.MySQLDialect,
+			migrations: []Migration{{Version: 1, Name: "init"}},
+			wantErr:    errs.ErrInvalidMigration("1_init"),
+		}, {
+			name:       "invalid version",
+			dialect:    easyorm.MySQLDialect,
+			migrations: []Migration{{Version: 0, Name: "init", Up: noop}},
+			wantErr:    errs.ErrInvalidMigration("0_init"),
+		}, {
+			name:    "unsupported dialect",
+			dialect: easyorm.OracleDialect,
+			wantErr: errs.ErrUnsupportedDialect(easyorm.OracleDialect),
+		},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			mockDB, _, err := sqlmock.New()
+			require.NoError(t, err)
+			defer func() { _ = mockDB.Close() }()
+
+			db, err := easyorm.OpenDB(mockDB, tc.dialect)
+			require.NoError(t, err)
+
+			_, err = NewMigrator(db, tc.migrations)
+			assert.Equal(t, tc.wantErr, err)
+		})
+	}
+}
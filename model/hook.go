@@ -0,0 +1,82 @@
+package model
+
+import (
+	"context"
+	"reflect"
+)
+
+// BeforeInserter invoked on each row before insert, the error aborts the insert.
+type BeforeInserter interface {
+	BeforeInsert(ctx context.Context) error
+}
+
+// AfterInserter invoked on each row after insert.
+type AfterInserter interface {
+	AfterInsert(ctx context.Context) error
+}
+
+// BeforeUpdater invoked on the entity before update, the error aborts the update.
+type BeforeUpdater interface {
+	BeforeUpdate(ctx context.Context) error
+}
+
+// AfterUpdater invoked on the entity after update.
+type AfterUpdater interface {
+	AfterUpdate(ctx context.Context) error
+}
+
+// BeforeDeleter invoked before delete, the error aborts the delete.
+type BeforeDeleter interface {
+	BeforeDelete(ctx context.Context) error
+}
+
+// AfterDeleter invoked after delete.
+type AfterDeleter interface {
+	AfterDelete(ctx context.Context) error
+}
+
+// AfterFinder invoked on each entity found, the error aborts the query.
+type AfterFinder interface {
+	AfterFind(ctx context.Context) error
+}
+
+// Hook the set of hooks implemented by the pointer to entity.
+type Hook uint8
+
+const (
+	HookBeforeInsert Hook = 1 << iota
+	HookAfterInsert
+	HookBeforeUpdate
+	HookAfterUpdate
+	HookBeforeDelete
+	HookAfterDelete
+	HookAfterFind
+)
+
+var hookTypes = map[Hook]reflect.Type{
+	HookBeforeInsert: reflect.TypeFor[BeforeInserter](),
+	HookAfterInsert:  reflect.TypeFor[AfterInserter](),
+	HookBeforeUpdate: reflect.TypeFor[BeforeUpdater](),
+	HookAfterUpdate:  reflect.TypeFor[AfterUpdater](),
+	HookBeforeDelete: reflect.TypeFor[BeforeDeleter](),
+	HookAfterDelete:  reflect.TypeFor[AfterDeleter](),
+	HookAfterFind:    reflect.TypeFor[AfterFinder](),
+}
+
+// Has reports whether the hook is implemented.
+func (h Hook) Has(hook Hook) bool {
+	return h&hook != 0
+}
+
+// hooksOf returns the hooks implemented by the pointer to struct type.
+func hooksOf(typ reflect.Type) Hook {
+	ptrTyp := reflect.PointerTo(typ)
+
+	var hooks Hook
+	for hook, hookTyp := range hookTypes {
+		if ptrTyp.Implements(hookTyp) {
+			hooks |= hook
+		}
+	}
+	return hooks
+}
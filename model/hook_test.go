@@ -0,0 +1,28 @@
+package model
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type hookModel struct{}
+
+func (h hookModel) BeforeInsert(_ context.Context) error { return nil }
+func (h *hookModel) AfterFind(_ context.Context) error   { return nil }
+
+func TestModelRegistry_Hooks(t *testing.T) {
+	r := NewRegistry()
+
+	m, err := r.GetModel(&hookModel{})
+	require.NoError(t, err)
+	assert.Equal(t, HookBeforeInsert|HookAfterFind, m.Hooks)
+	assert.True(t, m.Hooks.Has(HookAfterFind))
+	assert.False(t, m.Hooks.Has(HookBeforeUpdate))
+
+	m, err = r.GetModel(&basicStruct{})
+	require.NoError(t, err)
+	assert.Equal(t, Hook(0), m.Hooks)
+}
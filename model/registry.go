@@ -1,10 +1,13 @@
 package model
 
 import (
+	"database/sql"
 	"reflect"
 	"regexp"
+	"strconv"
 	"strings"
 	"sync"
+	"time"
 
 	"github.com/JrMarcco/easy-orm/internal/errs"
 )
@@ -12,8 +15,45 @@ import (
 const (
 	tagName    = "orm"
 	tagNameCol = "column"
+
+	tagFlagTenant     = "tenant"
+	tagFlagSoftDelete = "soft_delete"
+	tagFlagVersion    = "version"
+
+	tagFlagAutoCreateTime = "auto_create_time"
+	tagFlagAutoUpdateTime = "auto_update_time"
+
+	tagNameType     = "type"
+	tagNameDefault  = "default"
+	tagFlagNullable = "nullable"
+	tagFlagPK       = "pk"
+	tagFlagUnique   = "unique"
+	tagFlagIndex    = "index"
+
+	// TagValUnnamed the name of index tagged by unique or index without value.
+	TagValUnnamed = "-"
+
+	// tagValMilli the value of auto time tags storing unix time in milliseconds, like `orm:"auto_create_time=milli"`.
+	tagValMilli = "milli"
 )
 
+// tagFlags tags without value.
+var tagFlags = map[string]struct{}{
+	tagFlagTenant:     {},
+	tagFlagSoftDelete: {},
+	tagFlagVersion:    {},
+
+	tagFlagAutoCreateTime: {},
+	tagFlagAutoUpdateTime: {},
+
+	tagFlagNotNull: {},
+
+	tagFlagNullable: {},
+	tagFlagPK:       {},
+	tagFlagUnique:   {},
+	tagFlagIndex:    {},
+}
+
 var _ Registry = (*modelRegistry)(nil)
 
 type modelRegistry struct {
@@ -97,6 +137,9 @@ func (r *modelRegistry) parseModel(typ reflect.Type) (*Model, error) {
 	fields := make(map[string]*Field, numField)
 	columns := make(map[string]*Field, numField)
 
+	var tenant, softDelete, version *Field
+	var autoCreateTime, autoUpdateTime *AutoTime
+
 	for i := 0; i < numField; i++ {
 		structField := elemTyp.Field(i)
 
@@ -111,23 +154,91 @@ func (r *modelRegistry) parseModel(typ reflect.Type) (*Model, error) {
 			colName = camelToUnderline(structField.Name)
 		}
 
+		rules, err := parseRules(tagMap, structField.Tag)
+		if err != nil {
+			return nil, err
+		}
+
+		def, err := parseColumnDef(tagMap, structField.Type)
+		if err != nil {
+			return nil, err
+		}
+
 		field := &Field{
 			Typ:        structField.Type,
 			FiledName:  structField.Name,
 			ColumnName: colName,
 			Offset:     structField.Offset,
+			Rules:      rules,
+			Def:        def,
 		}
 
 		seqFields = append(seqFields, field)
 		fields[structField.Name] = field
 		columns[colName] = field
+
+		if _, ok = tagMap[tagFlagTenant]; ok {
+			if tenant != nil {
+				return nil, errs.ErrDuplicateTag(tagFlagTenant)
+			}
+			tenant = field
+		}
+
+		if _, ok = tagMap[tagFlagSoftDelete]; ok {
+			if softDelete != nil {
+				return nil, errs.ErrDuplicateTag(tagFlagSoftDelete)
+			}
+			if !isSoftDeleteType(field.Typ) {
+				return nil, errs.ErrInvalidSoftDeleteField(field.FiledName)
+			}
+			softDelete = field
+		}
+
+		if _, ok = tagMap[tagFlagVersion]; ok {
+			if version != nil {
+				return nil, errs.ErrDuplicateTag(tagFlagVersion)
+			}
+			if !isVersionType(field.Typ) {
+				return nil, errs.ErrInvalidVersionField(field.FiledName)
+			}
+			version = field
+		}
+
+		for _, auto := range []struct {
+			flag     string
+			autoTime **AutoTime
+		}{
+			{flag: tagFlagAutoCreateTime, autoTime: &autoCreateTime},
+			{flag: tagFlagAutoUpdateTime, autoTime: &autoUpdateTime},
+		} {
+			flag, autoTime := auto.flag, auto.autoTime
+			val, ok := tagMap[flag]
+			if !ok {
+				continue
+			}
+			if *autoTime != nil {
+				return nil, errs.ErrDuplicateTag(flag)
+			}
+
+			if *autoTime, err = parseAutoTime(field, flag, val); err != nil {
+				return nil, err
+			}
+		}
 	}
 
 	return &Model{
-		TableName: camelToUnderline(elemTyp.Name()),
-		SeqFields: seqFields,
-		Fields:    fields,
-		Columns:   columns,
+		TableName:  camelToUnderline(elemTyp.Name()),
+		SeqFields:  seqFields,
+		Fields:     fields,
+		Columns:    columns,
+		Tenant:     tenant,
+		SoftDelete: softDelete,
+		Version:    version,
+
+		AutoCreateTime: autoCreateTime,
+		AutoUpdateTime: autoUpdateTime,
+
+		Hooks: hooksOf(elemTyp),
 	}, nil
 }
 
@@ -142,28 +253,37 @@ func (r *modelRegistry) parseModel(typ reflect.Type) (*Model, error) {
 // the tag content is "column=user_id,column=user_name"
 // the tag name is "column"
 // the tag value is "user_name"
+//
+// flags are tags without value, like `orm:"column=tenant_id,tenant"`.
 func (r *modelRegistry) parseTag(tag reflect.StructTag) (map[string]string, error) {
 	ormTag, ok := tag.Lookup(tagName)
 	if !ok {
 		return map[string]string{}, nil
 	}
 
-	pairs := strings.Split(ormTag, ",")
+	pairs := splitTag(ormTag)
 	tagMap := make(map[string]string, len(pairs))
 
 	for _, pair := range pairs {
-		content := strings.Split(pair, "=")
-		if len(content) != 2 {
-			return nil, errs.ErrInvalidTag(pair)
+		key, val, ok := strings.Cut(pair, "=")
+		if !ok {
+			flag := strings.Trim(key, " ")
+			if _, ok = tagFlags[flag]; !ok {
+				return nil, errs.ErrInvalidTag(pair)
+			}
+
+			tagMap[flag] = ""
+			continue
 		}
 
-		key := strings.Trim(content[0], " ")
+		key = strings.Trim(key, " ")
 		if key == "" {
 			return nil, errs.ErrInvalidTag(pair)
 		}
 
-		val := strings.Trim(content[1], " ")
-		if val == "" {
+		// value with "=" is allowed in quotes only, like `orm:"default='a=b'"`
+		val = strings.Trim(val, " ")
+		if val == "" || (strings.Contains(val, "=") && !strings.HasPrefix(val, "'")) {
 			return nil, errs.ErrInvalidTag(pair)
 		}
 
@@ -173,6 +293,86 @@ func (r *modelRegistry) parseTag(tag reflect.StructTag) (map[string]string, erro
 	return tagMap, nil
 }
 
+// splitTag split the tag content by commas, except those in parentheses or quotes like `orm:"type=decimal(10,2)"`.
+func splitTag(content string) []string {
+	var pairs []string
+
+	depth, quoted, start := 0, false, 0
+	for i := 0; i < len(content); i++ {
+		switch c := content[i]; {
+		case c == '\'':
+			quoted = !quoted
+		case quoted:
+		case c == '(':
+			depth++
+		case c == ')':
+			depth--
+		case c == ',' && depth == 0:
+			pairs = append(pairs, content[start:i])
+			start = i + 1
+		}
+	}
+	return append(pairs, content[start:])
+}
+
+// parseColumnDef parse the definition of column from the orm tag.
+func parseColumnDef(tagMap map[string]string, typ reflect.Type) (ColumnDef, error) {
+	def := ColumnDef{Type: tagMap[tagNameType]}
+
+	if size, ok := tagMap[tagNameSize]; ok {
+		// the size is checked while parsing rules
+		def.Size, _ = strconv.Atoi(size)
+	}
+
+	if val, ok := tagMap[tagNameDefault]; ok {
+		def.Default = &val
+	}
+
+	_, def.PK = tagMap[tagFlagPK]
+	_, notNull := tagMap[tagFlagNotNull]
+	_, nullable := tagMap[tagFlagNullable]
+	if nullable && (notNull || def.PK) {
+		return ColumnDef{}, errs.ErrInvalidTag(tagFlagNullable)
+	}
+	def.Nullable = nullable || (!notNull && !def.PK && IsNullType(typ))
+
+	for _, idx := range []struct {
+		flag string
+		name *string
+	}{
+		{flag: tagFlagUnique, name: &def.Unique},
+		{flag: tagFlagIndex, name: &def.Index},
+	} {
+		if name, ok := tagMap[idx.flag]; ok {
+			*idx.name = name
+			if name == "" {
+				*idx.name = TagValUnnamed
+			}
+		}
+	}
+	return def, nil
+}
+
+// IsNullType reports whether the value of type can be null, which is pointer or sql.Null* type.
+func IsNullType(typ reflect.Type) bool {
+	if typ.Kind() == reflect.Pointer {
+		return true
+	}
+	_, ok := NullValueType(typ)
+	return ok
+}
+
+// NullValueType returns the type of value wrapped by sql.Null* type, like string of sql.NullString.
+func NullValueType(typ reflect.Type) (reflect.Type, bool) {
+	if typ.Kind() != reflect.Struct || typ.PkgPath() != "database/sql" || typ.NumField() != 2 {
+		return nil, false
+	}
+	if _, ok := typ.FieldByName("Valid"); !ok {
+		return nil, false
+	}
+	return typ.Field(0).Type, true
+}
+
 func camelToUnderline(s string) string {
 	var result []rune
 	for i, r := range s {
@@ -199,3 +399,59 @@ func camelToUnderline(s string) string {
 	out = regexp.MustCompile(`_+`).ReplaceAllString(out, "_")
 	return out
 }
+
+// isSoftDeleteType reports whether the type can mark the row deleted,
+// deletion time of time.Time or sql.NullTime, deleted flag of bool, or deletion unix time of integer.
+func isSoftDeleteType(typ reflect.Type) bool {
+	if typ.Kind() == reflect.Pointer {
+		typ = typ.Elem()
+	}
+
+	switch typ {
+	case reflect.TypeOf(time.Time{}), reflect.TypeOf(sql.NullTime{}):
+		return true
+	}
+
+	switch typ.Kind() {
+	case reflect.Bool,
+		reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return true
+	}
+	return false
+}
+
+// parseAutoTime parse the field tagged auto time, the unix time of integer field is in seconds unless tagged milli.
+func parseAutoTime(field *Field, flag string, val string) (*AutoTime, error) {
+	if val != "" && val != tagValMilli {
+		return nil, errs.ErrInvalidTag(flag + "=" + val)
+	}
+
+	typ := field.Typ
+	if typ.Kind() == reflect.Pointer {
+		typ = typ.Elem()
+	}
+
+	if typ != reflect.TypeOf(time.Time{}) {
+		switch typ.Kind() {
+		case reflect.Int, reflect.Int32, reflect.Int64, reflect.Uint, reflect.Uint32, reflect.Uint64:
+		default:
+			return nil, errs.ErrInvalidAutoTimeField(field.FiledName)
+		}
+	}
+
+	return &AutoTime{
+		Field: field,
+		Milli: val == tagValMilli,
+	}, nil
+}
+
+// isVersionType reports whether the type can be the version of optimistic locking, only integer is supported.
+func isVersionType(typ reflect.Type) bool {
+	switch typ.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return true
+	}
+	return false
+}
@@ -4,6 +4,7 @@ import (
 	"database/sql"
 	"reflect"
 	"testing"
+	"time"
 
 	"github.com/JrMarcco/easy-orm/internal/errs"
 	"github.com/stretchr/testify/assert"
@@ -37,6 +38,16 @@ type withInvalidTagStruct3 struct {
 	Name string `orm:"column-user_name"`
 }
 
+type withTenantStruct struct {
+	Id       uint64
+	TenantId uint64 `orm:"column=tid,tenant"`
+}
+
+type withDuplicateTenantStruct struct {
+	TenantId uint64 `orm:"tenant"`
+	OrgId    uint64 `orm:"tenant"`
+}
+
 func TestModelRegistry_RegisterModel(t *testing.T) {
 	r := NewRegistry()
 	tcs := []struct {
@@ -72,6 +83,7 @@ func TestModelRegistry_RegisterModel(t *testing.T) {
 						FiledName:  "NickName",
 						ColumnName: "nick_name",
 						Offset:     32,
+						Def:        ColumnDef{Nullable: true},
 					}, {
 						Typ:        reflect.TypeOf(""),
 						FiledName:  "IDCardNo",
@@ -103,6 +115,7 @@ func TestModelRegistry_RegisterModel(t *testing.T) {
 						FiledName:  "NickName",
 						ColumnName: "nick_name",
 						Offset:     32,
+						Def:        ColumnDef{Nullable: true},
 					},
 					"IDCardNo": {
 						Typ:        reflect.TypeOf(""),
@@ -135,6 +148,7 @@ func TestModelRegistry_RegisterModel(t *testing.T) {
 						FiledName:  "NickName",
 						ColumnName: "nick_name",
 						Offset:     32,
+						Def:        ColumnDef{Nullable: true},
 					},
 					"id_card_no": {
 						Typ:        reflect.TypeOf(""),
@@ -171,6 +185,7 @@ func TestModelRegistry_RegisterModel(t *testing.T) {
 						FiledName:  "NickName",
 						ColumnName: "nick_name",
 						Offset:     32,
+						Def:        ColumnDef{Nullable: true},
 					}, {
 						Typ:        reflect.TypeOf(""),
 						FiledName:  "IDCardNo",
@@ -202,6 +217,7 @@ func TestModelRegistry_RegisterModel(t *testing.T) {
 						FiledName:  "NickName",
 						ColumnName: "nick_name",
 						Offset:     32,
+						Def:        ColumnDef{Nullable: true},
 					},
 					"IDCardNo": {
 						Typ:        reflect.TypeOf(""),
@@ -234,6 +250,7 @@ func TestModelRegistry_RegisterModel(t *testing.T) {
 						FiledName:  "NickName",
 						ColumnName: "nick_name",
 						Offset:     32,
+						Def:        ColumnDef{Nullable: true},
 					},
 					"id_card_no": {
 						Typ:        reflect.TypeOf(""),
@@ -280,6 +297,7 @@ func TestModelRegistry_RegisterModel(t *testing.T) {
 						FiledName:  "NickName",
 						ColumnName: "nick_name",
 						Offset:     32,
+						Def:        ColumnDef{Nullable: true},
 					}, {
 						Typ:        reflect.TypeOf(""),
 						FiledName:  "IDCardNo",
@@ -311,6 +329,7 @@ func TestModelRegistry_RegisterModel(t *testing.T) {
 						FiledName:  "NickName",
 						ColumnName: "nick_name",
 						Offset:     32,
+						Def:        ColumnDef{Nullable: true},
 					},
 					"IDCardNo": {
 						Typ:        reflect.TypeOf(""),
@@ -343,6 +362,7 @@ func TestModelRegistry_RegisterModel(t *testing.T) {
 						FiledName:  "NickName",
 						ColumnName: "nick_name",
 						Offset:     32,
+						Def:        ColumnDef{Nullable: true},
 					},
 					"id_card_no": {
 						Typ:        reflect.TypeOf(""),
@@ -382,6 +402,7 @@ func TestModelRegistry_RegisterModel(t *testing.T) {
 						FiledName:  "NickName",
 						ColumnName: "nick_name",
 						Offset:     32,
+						Def:        ColumnDef{Nullable: true},
 					}, {
 						Typ:        reflect.TypeOf(""),
 						FiledName:  "IDCardNo",
@@ -413,6 +434,7 @@ func TestModelRegistry_RegisterModel(t *testing.T) {
 						FiledName:  "NickName",
 						ColumnName: "nick_name",
 						Offset:     32,
+						Def:        ColumnDef{Nullable: true},
 					},
 					"IDCardNo": {
 						Typ:        reflect.TypeOf(""),
@@ -445,6 +467,7 @@ func TestModelRegistry_RegisterModel(t *testing.T) {
 						FiledName:  "NickName",
 						ColumnName: "nick_name",
 						Offset:     32,
+						Def:        ColumnDef{Nullable: true},
 					},
 					"card_no": {
 						Typ:        reflect.TypeOf(""),
@@ -458,9 +481,16 @@ func TestModelRegistry_RegisterModel(t *testing.T) {
 			name:   "pointer with invalid table opt",
 			entity: &basicStruct{},
 			opts: []Opt{
-				WithTableOpt("t.table.name"),
+				WithTableOpt("c.s.table.name"),
 			},
-			wantErr: errs.ErrInvalidTable("t.table.name"),
+			wantErr: errs.ErrInvalidTable("c.s.table.name"),
+		}, {
+			name:   "pointer with empty schema table opt",
+			entity: &basicStruct{},
+			opts: []Opt{
+				WithTableOpt(".table"),
+			},
+			wantErr: errs.ErrInvalidTable(".table"),
 		}, {
 			name:   "pointer with unknown field opt",
 			entity: &basicStruct{},
@@ -527,6 +557,34 @@ func TestModelRegistry_RegisterModel(t *testing.T) {
 			name:    "struct with invalid tag 3",
 			entity:  withInvalidTagStruct3{},
 			wantErr: errs.ErrInvalidTag("column-user_name"),
+		}, {
+			name:   "struct with tenant",
+			entity: withTenantStruct{},
+			wantModel: func() *Model {
+				id := &Field{
+					Typ:        reflect.TypeOf(uint64(0)),
+					FiledName:  "Id",
+					ColumnName: "id",
+					Offset:     0,
+				}
+				tenant := &Field{
+					Typ:        reflect.TypeOf(uint64(0)),
+					FiledName:  "TenantId",
+					ColumnName: "tid",
+					Offset:     8,
+				}
+				return &Model{
+					TableName: "with_tenant_struct",
+					SeqFields: []*Field{id, tenant},
+					Fields:    map[string]*Field{"Id": id, "TenantId": tenant},
+					Columns:   map[string]*Field{"id": id, "tid": tenant},
+					Tenant:    tenant,
+				}
+			}(),
+		}, {
+			name:    "struct with duplicate tenant",
+			entity:  withDuplicateTenantStruct{},
+			wantErr: errs.ErrDuplicateTag("tenant"),
 		},
 	}
 
@@ -593,6 +651,7 @@ func TestModelRegistry_GetModel(t *testing.T) {
 						FiledName:  "NickName",
 						ColumnName: "nick_name",
 						Offset:     32,
+						Def:        ColumnDef{Nullable: true},
 					},
 					"IDCardNo": {
 						Typ:        reflect.TypeOf(""),
@@ -625,6 +684,7 @@ func TestModelRegistry_GetModel(t *testing.T) {
 						FiledName:  "NickName",
 						ColumnName: "nick_name",
 						Offset:     32,
+						Def:        ColumnDef{Nullable: true},
 					},
 					"id_card_no": {
 						Typ:        reflect.TypeOf(""),
@@ -664,6 +724,7 @@ func TestModelRegistry_GetModel(t *testing.T) {
 						FiledName:  "NickName",
 						ColumnName: "nick_name",
 						Offset:     32,
+						Def:        ColumnDef{Nullable: true},
 					},
 					"IDCardNo": {
 						Typ:        reflect.TypeOf(""),
@@ -696,6 +757,7 @@ func TestModelRegistry_GetModel(t *testing.T) {
 						FiledName:  "NickName",
 						ColumnName: "nick_name",
 						Offset:     32,
+						Def:        ColumnDef{Nullable: true},
 					},
 					"id_card_no": {
 						Typ:        reflect.TypeOf(""),
@@ -791,3 +853,186 @@ func TestModelRegistry_GetModel(t *testing.T) {
 
 	assert.Equal(t, len(r.models), 4)
 }
+
+func TestWithTableOpt(t *testing.T) {
+	tcs := []struct {
+		name          string
+		tableName     string
+		wantCatalog   string
+		wantSchema    string
+		wantTableName string
+		wantQualified string
+	}{
+		{
+			name:          "table",
+			tableName:     "user",
+			wantTableName: "user",
+			wantQualified: "user",
+		}, {
+			name:          "schema",
+			tableName:     "public.user",
+			wantSchema:    "public",
+			wantTableName: "user",
+			wantQualified: "public.user",
+		}, {
+			name:          "catalog",
+			tableName:     "db.dbo.user",
+			wantCatalog:   "db",
+			wantSchema:    "dbo",
+			wantTableName: "user",
+			wantQualified: "db.dbo.user",
+		},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			m := &Model{Catalog: "c", Schema: "s", TableName: "t"}
+			require.NoError(t, WithTableOpt(tc.tableName)(m))
+
+			assert.Equal(t, tc.wantCatalog, m.Catalog)
+			assert.Equal(t, tc.wantSchema, m.Schema)
+			assert.Equal(t, tc.wantTableName, m.TableName)
+			assert.Equal(t, tc.wantQualified, m.QualifiedName())
+		})
+	}
+}
+
+func TestModelRegistry_SoftDelete(t *testing.T) {
+	type duplicate struct {
+		DeletedAt *time.Time `orm:"soft_delete"`
+		IsDeleted bool       `orm:"soft_delete"`
+	}
+	type invalid struct {
+		DeletedAt string `orm:"soft_delete"`
+	}
+
+	type softDeleteModel struct {
+		Id        uint64
+		DeletedAt *time.Time `orm:"soft_delete"`
+	}
+
+	r := NewRegistry()
+
+	m, err := r.GetModel(&softDeleteModel{})
+	require.NoError(t, err)
+	assert.Equal(t, m.Fields["DeletedAt"], m.SoftDelete)
+
+	_, err = r.GetModel(&duplicate{})
+	assert.Equal(t, errs.ErrDuplicateTag("soft_delete"), err)
+
+	_, err = r.GetModel(&invalid{})
+	assert.Equal(t, errs.ErrInvalidSoftDeleteField("DeletedAt"), err)
+}
+
+func TestModelRegistry_Version(t *testing.T) {
+	type versionModel struct {
+		Id      uint64
+		Version int64 `orm:"version"`
+	}
+	type duplicate struct {
+		Version  int64 `orm:"version"`
+		Revision int64 `orm:"version"`
+	}
+	type invalid struct {
+		Version string `orm:"version"`
+	}
+
+	r := NewRegistry()
+
+	m, err := r.GetModel(&versionModel{})
+	require.NoError(t, err)
+	assert.Equal(t, m.Fields["Version"], m.Version)
+
+	_, err = r.GetModel(&duplicate{})
+	assert.Equal(t, errs.ErrDuplicateTag("version"), err)
+
+	_, err = r.GetModel(&invalid{})
+	assert.Equal(t, errs.ErrInvalidVersionField("Version"), err)
+}
+
+func TestModelRegistry_AutoTime(t *testing.T) {
+	type autoTimeModel struct {
+		Id        uint64
+		CreatedAt time.Time `orm:"auto_create_time"`
+		UpdatedAt int64     `orm:"column=mtime,auto_update_time=milli"`
+	}
+	type duplicate struct {
+		CreatedAt time.Time `orm:"auto_create_time"`
+		Ctime     time.Time `orm:"auto_create_time"`
+	}
+	type invalidType struct {
+		UpdatedAt string `orm:"auto_update_time"`
+	}
+	type invalidUnit struct {
+		UpdatedAt int64 `orm:"auto_update_time=nano"`
+	}
+
+	r := NewRegistry()
+
+	m, err := r.GetModel(&autoTimeModel{})
+	require.NoError(t, err)
+	assert.Equal(t, &AutoTime{Field: m.Fields["CreatedAt"]}, m.AutoCreateTime)
+	assert.Equal(t, &AutoTime{Field: m.Fields["UpdatedAt"], Milli: true}, m.AutoUpdateTime)
+	assert.Equal(t, "mtime", m.AutoUpdateTime.ColumnName)
+
+	_, err = r.GetModel(&duplicate{})
+	assert.Equal(t, errs.ErrDuplicateTag("auto_create_time"), err)
+
+	_, err = r.GetModel(&invalidType{})
+	assert.Equal(t, errs.ErrInvalidAutoTimeField("UpdatedAt"), err)
+
+	_, err = r.GetModel(&invalidUnit{})
+	assert.Equal(t, errs.ErrInvalidTag("auto_update_time=nano"), err)
+}
+
+func TestModelRegistry_ColumnDef(t *testing.T) {
+	type columnDefModel struct {
+		Id       uint64          `orm:"pk"`
+		Email    string          `orm:"size=64,unique"`
+		Price    float64         `orm:"type=decimal(10,2),default=0"`
+		Status   string          `orm:"default='a=b',index=idx_status_remark"`
+		Remark   *string         `orm:"not_null,index=idx_status_remark"`
+		Nickname sql.NullString  `orm:"index"`
+		Age      int8            `orm:"nullable"`
+		Score    *sql.NullInt64  `orm:"column=score"`
+		Created  *time.Time      `orm:"pk"`
+		Tags     []byte          `orm:"type=json"`
+		Note     *sql.NullString `orm:"unique=uk_note"`
+	}
+	type invalid struct {
+		Id uint64 `orm:"pk,nullable"`
+	}
+	type unquoted struct {
+		Status string `orm:"default=a=b"`
+	}
+
+	r := NewRegistry()
+
+	m, err := r.GetModel(&columnDefModel{})
+	require.NoError(t, err)
+
+	zero := "0"
+	quoted := "'a=b'"
+	tcs := map[string]ColumnDef{
+		"Id":       {PK: true},
+		"Email":    {Size: 64, Unique: TagValUnnamed},
+		"Price":    {Type: "decimal(10,2)", Default: &zero},
+		"Status":   {Default: &quoted, Index: "idx_status_remark"},
+		"Remark":   {Index: "idx_status_remark"},
+		"Nickname": {Nullable: true, Index: TagValUnnamed},
+		"Age":      {Nullable: true},
+		"Score":    {Nullable: true},
+		"Created":  {PK: true},
+		"Tags":     {Type: "json"},
+		"Note":     {Nullable: true, Unique: "uk_note"},
+	}
+	for name, def := range tcs {
+		assert.Equal(t, def, m.Fields[name].Def, name)
+	}
+
+	_, err = r.GetModel(&invalid{})
+	assert.Equal(t, errs.ErrInvalidTag("nullable"), err)
+
+	_, err = r.GetModel(&unquoted{})
+	assert.Equal(t, errs.ErrInvalidTag("default=a=b"), err)
+}
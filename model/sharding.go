@@ -0,0 +1,40 @@
+package model
+
+import "github.com/JrMarcco/easy-orm/internal/errs"
+
+// Dst the destination of a shard.
+type Dst struct {
+	// DB the name of database in ShardingDB.
+	DB string
+	// Table the name of table in database.
+	Table string
+}
+
+// ShardingAlgorithm decide the shard of sharded model by the value of sharding key.
+type ShardingAlgorithm interface {
+	// Key returns the field name of sharding key.
+	Key() string
+	// Shard returns the destination of the value of sharding key.
+	Shard(val any) (Dst, error)
+	// Broadcast returns the destinations of all shards, statements without sharding key are fanned out to them.
+	Broadcast() []Dst
+}
+
+// WithShardingOpt set the sharding algorithm of model, whose sharding key must be a field of model.
+// Algorithms like ModSharding validate their config when the model is registered.
+func WithShardingOpt(algorithm ShardingAlgorithm) Opt {
+	return func(m *Model) error {
+		if _, ok := m.Fields[algorithm.Key()]; !ok {
+			return errs.ErrInvalidField(algorithm.Key())
+		}
+
+		if v, ok := algorithm.(interface{ Validate() error }); ok {
+			if err := v.Validate(); err != nil {
+				return err
+			}
+		}
+
+		m.Sharding = algorithm
+		return nil
+	}
+}
@@ -2,6 +2,7 @@ package model
 
 import (
 	"reflect"
+	"slices"
 	"strings"
 
 	"github.com/JrMarcco/easy-orm/internal/errs"
@@ -13,30 +14,87 @@ type Registry interface {
 }
 
 type Model struct {
+	// Catalog the database or catalog the table belongs to, empty for the current one.
+	Catalog string
+	// Schema the schema the table belongs to, empty for the default one.
+	Schema string
+	// TableName the name of table without schema and catalog.
 	TableName string
 
 	SeqFields []*Field
 	Fields    map[string]*Field // fieldName -> Field
 	Columns   map[string]*Field // ColumnName -> Field
+
+	// Tenant the field tagged "tenant", nil if the model is not tenant aware.
+	Tenant *Field
+	// SoftDelete the field tagged "soft_delete" marking the row deleted, nil if the model is hard deleted.
+	SoftDelete *Field
+	// Version the field tagged "version" for optimistic locking, nil if the model is not versioned.
+	Version *Field
+
+	// AutoCreateTime the field tagged "auto_create_time" filled with the time of insertion.
+	AutoCreateTime *AutoTime
+	// AutoUpdateTime the field tagged "auto_update_time" filled with the time of insertion and update.
+	AutoUpdateTime *AutoTime
+
+	// Hooks the lifecycle hooks implemented by the entity, like BeforeInserter.
+	Hooks Hook
+
+	// Sharding the algorithm deciding the shard of model on ShardingDB, nil if the model is not sharded.
+	Sharding ShardingAlgorithm
+}
+
+// PrimaryKeys returns the fields tagged "pk", or the field Id if none tagged, nil if the model has neither.
+func (m *Model) PrimaryKeys() []*Field {
+	var pks []*Field
+	for _, field := range m.SeqFields {
+		if field.Def.PK {
+			pks = append(pks, field)
+		}
+	}
+	if len(pks) == 0 {
+		if id, ok := m.Fields["Id"]; ok {
+			pks = append(pks, id)
+		}
+	}
+	return pks
 }
 
 type Opt func(*Model) error
 
+// WithTableOpt set the table name, which can be qualified like "schema.table" or "catalog.schema.table".
 func WithTableOpt(tableName string) Opt {
 	return func(m *Model) error {
-		if tableName == "" {
+		segments := strings.Split(tableName, ".")
+		if len(segments) > 3 || slices.Contains(segments, "") {
 			return errs.ErrInvalidTable(tableName)
 		}
 
-		if segments := strings.Split(tableName, "."); len(segments) > 2 {
-			return errs.ErrInvalidTable(tableName)
+		m.Catalog, m.Schema = "", ""
+		switch len(segments) {
+		case 3:
+			m.Catalog, m.Schema = segments[0], segments[1]
+		case 2:
+			m.Schema = segments[0]
 		}
 
-		m.TableName = tableName
+		m.TableName = segments[len(segments)-1]
 		return nil
 	}
 }
 
+// TableNameOf returns the default table name of struct type name, like "user_info" of "UserInfo".
+func TableNameOf(typeName string) string {
+	return camelToUnderline(typeName)
+}
+
+// QualifiedName returns the table name qualified by schema and catalog if any, like "catalog.schema.table".
+func (m *Model) QualifiedName() string {
+	return strings.Join(slices.DeleteFunc([]string{m.Catalog, m.Schema, m.TableName}, func(s string) bool {
+		return s == ""
+	}), ".")
+}
+
 func WithColumnOpt(fieldName, columnName string) Opt {
 	return func(m *Model) error {
 		if columnName == "" {
@@ -56,9 +114,43 @@ func WithColumnOpt(fieldName, columnName string) Opt {
 	}
 }
 
+// AutoTime the field filled with the current time automatically,
+// which is time.Time, *time.Time or integer of unix time.
+type AutoTime struct {
+	*Field
+	// Milli the unix time of integer field is in milliseconds rather than seconds.
+	Milli bool
+}
+
 type Field struct {
 	Typ        reflect.Type
 	FiledName  string
 	ColumnName string
 	Offset     uintptr
+	// Rules the validation rules checked before writes.
+	Rules []Rule
+	// Def the definition of column declared by tags, used to generate DDL.
+	Def ColumnDef
+}
+
+// ColumnDef the definition of column declared by tags,
+// like `orm:"type=decimal(10,2),nullable,default=0,pk,unique,index=idx_name"`.
+type ColumnDef struct {
+	// Type the column type overriding the one mapped from go type, empty if not declared.
+	Type string
+	// Size the length of string or bytes declared by "size", 0 if not declared.
+	Size int
+	// Nullable whether the column accepts null,
+	// true if tagged nullable or the field is pointer or sql.Null* type, unless tagged not_null or pk.
+	Nullable bool
+	// Default the default value in SQL like "0", "'active'" or "CURRENT_TIMESTAMP", nil if not declared.
+	Default *string
+	// PK the column is part of the primary key.
+	PK bool
+	// Unique the name of unique index the column belongs to,
+	// "-" if tagged unique without name, which is a unique index of the column alone.
+	Unique string
+	// Index the name of index the column belongs to,
+	// "-" if tagged index without name, which is an index of the column alone.
+	Index string
 }
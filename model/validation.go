@@ -0,0 +1,281 @@
+package model
+
+import (
+	"database/sql/driver"
+	"fmt"
+	"reflect"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/JrMarcco/easy-orm/internal/errs"
+)
+
+const (
+	tagNameValidate = "validate"
+
+	tagFlagNotNull = "not_null"
+	tagNameSize    = "size"
+
+	ruleMin   = "min"
+	ruleMax   = "max"
+	ruleRegex = "regex"
+	ruleEnum  = "enum"
+)
+
+// Rule the validation rule of field checked before writes,
+// declared by `orm:"not_null,size=64"` or `validate:"min=1,max=100,regex=^[a-z]+$,enum=a|b|c"`.
+// Rules unknown to easy-orm in validate tag are ignored, so the tag can be shared with other validators
+// like `validate:"required,email"`, and the comma in param is escaped by backslash like `validate:"regex=^\\d{1\\,3}$"`.
+type Rule struct {
+	Name  string
+	Param string
+
+	// check reports whether the value satisfies the rule, val is the dereferenced value if not null.
+	check func(val reflect.Value, null bool) bool
+}
+
+// FieldError the field failing the rule.
+type FieldError struct {
+	Field string
+	Rule  string
+	Param string
+	Value any
+}
+
+func (e FieldError) String() string {
+	if e.Param == "" {
+		return fmt.Sprintf("%s: %s", e.Field, e.Rule)
+	}
+	return fmt.Sprintf("%s: %s=%s", e.Field, e.Rule, e.Param)
+}
+
+// ValidationError lists every field of entity failing validation.
+type ValidationError struct {
+	// Row the index of entity in the rows written.
+	Row    int
+	Fields []FieldError
+}
+
+func (e *ValidationError) Error() string {
+	fields := make([]string, 0, len(e.Fields))
+	for _, f := range e.Fields {
+		fields = append(fields, f.String())
+	}
+	return fmt.Sprintf("[easy-orm] validation failed on row %d: %s", e.Row, strings.Join(fields, ", "))
+}
+
+// Validate check the fields of entity against their rules,
+// returns *ValidationError listing every failing field, all fields of model are checked if fields is empty.
+func (m *Model) Validate(entity any, fields ...*Field) error {
+	if len(fields) == 0 {
+		fields = m.SeqFields
+	}
+
+	rv := reflect.Indirect(reflect.ValueOf(entity))
+
+	var fieldErrs []FieldError
+	for _, field := range fields {
+		if len(field.Rules) == 0 {
+			continue
+		}
+
+		val, null := ruleValue(rv.FieldByName(field.FiledName))
+		for _, rule := range field.Rules {
+			if rule.check(val, null) {
+				continue
+			}
+
+			fieldErr := FieldError{Field: field.FiledName, Rule: rule.Name, Param: rule.Param}
+			if !null && val.CanInterface() {
+				fieldErr.Value = val.Interface()
+			}
+			fieldErrs = append(fieldErrs, fieldErr)
+		}
+	}
+
+	if len(fieldErrs) == 0 {
+		return nil
+	}
+	return &ValidationError{Fields: fieldErrs}
+}
+
+// ruleValue dereference the value of field, and unwrap the value of driver.Valuer like sql.NullString.
+func ruleValue(val reflect.Value) (reflect.Value, bool) {
+	for val.Kind() == reflect.Pointer || val.Kind() == reflect.Interface {
+		if val.IsNil() {
+			return val, true
+		}
+		val = val.Elem()
+	}
+
+	if val.CanInterface() {
+		if valuer, ok := val.Interface().(driver.Valuer); ok {
+			v, err := valuer.Value()
+			if err != nil || v == nil {
+				return val, true
+			}
+			return reflect.ValueOf(v), false
+		}
+	}
+
+	switch val.Kind() {
+	case reflect.Slice, reflect.Map:
+		return val, val.IsNil()
+	}
+	return val, false
+}
+
+// parseRules parse the validation rules from the orm tag and validate tag of field.
+func parseRules(tagMap map[string]string, tag reflect.StructTag) ([]Rule, error) {
+	var rules []Rule
+
+	if _, ok := tagMap[tagFlagNotNull]; ok {
+		rules = append(rules, Rule{
+			Name:  tagFlagNotNull,
+			check: func(_ reflect.Value, null bool) bool { return !null },
+		})
+	}
+
+	if size, ok := tagMap[tagNameSize]; ok {
+		rule, err := newRule(tagNameSize, size)
+		if err != nil {
+			return nil, err
+		}
+		rules = append(rules, rule)
+	}
+
+	validateTag, ok := tag.Lookup(tagNameValidate)
+	if !ok || validateTag == "" {
+		return rules, nil
+	}
+
+	for _, pair := range splitRules(validateTag) {
+		name, param, ok := strings.Cut(pair, "=")
+		name = strings.TrimSpace(name)
+		if !knownRule(name) {
+			continue
+		}
+		if !ok {
+			return nil, errs.ErrInvalidTag(pair)
+		}
+
+		rule, err := newRule(name, strings.TrimSpace(param))
+		if err != nil {
+			return nil, err
+		}
+		rules = append(rules, rule)
+	}
+	return rules, nil
+}
+
+// splitRules split the validate tag by comma, the comma escaped by backslash is kept in the rule.
+func splitRules(validateTag string) []string {
+	var pairs []string
+	var sb strings.Builder
+	for i := 0; i < len(validateTag); i++ {
+		switch c := validateTag[i]; {
+		case c == '\\' && i+1 < len(validateTag) && validateTag[i+1] == ',':
+			sb.WriteByte(',')
+			i++
+		case c == ',':
+			pairs = append(pairs, sb.String())
+			sb.Reset()
+		default:
+			sb.WriteByte(c)
+		}
+	}
+	return append(pairs, sb.String())
+}
+
+// knownRule reports whether the rule of validate tag is checked by easy-orm.
+func knownRule(name string) bool {
+	switch name {
+	case tagNameSize, ruleMin, ruleMax, ruleRegex, ruleEnum:
+		return true
+	}
+	return false
+}
+
+func newRule(name string, param string) (Rule, error) {
+	rule := Rule{Name: name, Param: param}
+	invalid := errs.ErrInvalidTag(name + "=" + param)
+
+	switch name {
+	case tagNameSize:
+		size, err := strconv.Atoi(param)
+		if err != nil || size < 0 {
+			return Rule{}, invalid
+		}
+		rule.check = notNullOr(func(val reflect.Value) bool {
+			length, ok := lengthOf(val)
+			return !ok || length <= size
+		})
+	case ruleMin, ruleMax:
+		bound, err := strconv.ParseFloat(param, 64)
+		if err != nil {
+			return Rule{}, invalid
+		}
+		rule.check = notNullOr(func(val reflect.Value) bool {
+			n, ok := numberOf(val)
+			if !ok {
+				return true
+			}
+			if name == ruleMin {
+				return n >= bound
+			}
+			return n <= bound
+		})
+	case ruleRegex:
+		re, err := regexp.Compile(param)
+		if err != nil {
+			return Rule{}, invalid
+		}
+		rule.check = notNullOr(func(val reflect.Value) bool {
+			return val.Kind() != reflect.String || re.MatchString(val.String())
+		})
+	case ruleEnum:
+		values := strings.Split(param, "|")
+		rule.check = notNullOr(func(val reflect.Value) bool {
+			return !val.CanInterface() || slices.Contains(values, fmt.Sprint(val.Interface()))
+		})
+	default:
+		return Rule{}, invalid
+	}
+	return rule, nil
+}
+
+// notNullOr the rule passes on null, which is checked by not_null only.
+func notNullOr(check func(val reflect.Value) bool) func(val reflect.Value, null bool) bool {
+	return func(val reflect.Value, null bool) bool {
+		return null || check(val)
+	}
+}
+
+// lengthOf returns the length of string in characters or the length of bytes.
+func lengthOf(val reflect.Value) (int, bool) {
+	switch {
+	case val.Kind() == reflect.String:
+		return utf8.RuneCountInString(val.String()), true
+	case val.Kind() == reflect.Slice && val.Type().Elem().Kind() == reflect.Uint8:
+		return val.Len(), true
+	}
+	return 0, false
+}
+
+// numberOf returns the value of number, or the length of string.
+func numberOf(val reflect.Value) (float64, bool) {
+	switch {
+	case val.CanInt():
+		return float64(val.Int()), true
+	case val.CanUint():
+		return float64(val.Uint()), true
+	case val.CanFloat():
+		return val.Float(), true
+	}
+
+	length, ok := lengthOf(val)
+	return float64(length), ok
+}
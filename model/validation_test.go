@@ -0,0 +1,188 @@
+package model
+
+import (
+	"database/sql"
+	"testing"
+
+	"github.com/JrMarcco/easy-orm/internal/errs"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type validationModel struct {
+	Id       uint64
+	Name     string          `orm:"not_null,size=4"`
+	Nickname *string         `orm:"not_null"`
+	Email    sql.NullString  `orm:"not_null" validate:"regex=^[a-z]+@[a-z]+\\.com$"`
+	Age      int8            `validate:"min=1,max=150"`
+	Status   string          `validate:"enum=active|inactive"`
+	Score    *float64        `validate:"max=100"`
+	Password []byte          `orm:"size=8" validate:"min=2"`
+	Remark   *sql.NullString `orm:"size=2"`
+}
+
+func TestModel_Validate(t *testing.T) {
+	nickname := "tom"
+	score := 99.5
+	overflow := 100.5
+
+	valid := func() *validationModel {
+		return &validationModel{
+			Name:     "Tom",
+			Nickname: &nickname,
+			Email:    sql.NullString{String: "tom@mail.com", Valid: true},
+			Age:      18,
+			Status:   "active",
+			Score:    &score,
+			Password: []byte("secret"),
+		}
+	}
+
+	tcs := []struct {
+		name    string
+		entity  *validationModel
+		fields  []string
+		wantErr error
+	}{
+		{
+			name:   "valid",
+			entity: valid(),
+		}, {
+			name: "all invalid",
+			entity: &validationModel{
+				Name:     "Jerry",
+				Email:    sql.NullString{String: "Jerry", Valid: true},
+				Status:   "deleted",
+				Score:    &overflow,
+				Password: []byte("x"),
+				Remark:   &sql.NullString{String: "abc", Valid: true},
+			},
+			wantErr: &ValidationError{
+				Fields: []FieldError{
+					{Field: "Name", Rule: "size", Param: "4", Value: "Jerry"},
+					{Field: "Nickname", Rule: "not_null"},
+					{Field: "Email", Rule: "regex", Param: "^[a-z]+@[a-z]+\\.com$", Value: "Jerry"},
+					{Field: "Age", Rule: "min", Param: "1", Value: int8(0)},
+					{Field: "Status", Rule: "enum", Param: "active|inactive", Value: "deleted"},
+					{Field: "Score", Rule: "max", Param: "100", Value: overflow},
+					{Field: "Password", Rule: "min", Param: "2", Value: []byte("x")},
+					{Field: "Remark", Rule: "size", Param: "2", Value: "abc"},
+				},
+			},
+		}, {
+			name: "null",
+			entity: func() *validationModel {
+				v := valid()
+				v.Email = sql.NullString{}
+				v.Score = nil
+				return v
+			}(),
+			wantErr: &ValidationError{
+				Fields: []FieldError{{Field: "Email", Rule: "not_null"}},
+			},
+		}, {
+			name: "multibyte characters",
+			entity: func() *validationModel {
+				v := valid()
+				v.Name = "汤姆"
+				return v
+			}(),
+		}, {
+			name: "specified fields",
+			entity: func() *validationModel {
+				v := valid()
+				v.Name = "Jerry"
+				v.Age = 0
+				return v
+			}(),
+			fields: []string{"Age"},
+			wantErr: &ValidationError{
+				Fields: []FieldError{{Field: "Age", Rule: "min", Param: "1", Value: int8(0)}},
+			},
+		},
+	}
+
+	r := NewRegistry()
+	m, err := r.GetModel(&validationModel{})
+	require.NoError(t, err)
+
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			fields := make([]*Field, 0, len(tc.fields))
+			for _, f := range tc.fields {
+				fields = append(fields, m.Fields[f])
+			}
+
+			err := m.Validate(tc.entity, fields...)
+			if tc.wantErr == nil {
+				assert.NoError(t, err)
+				return
+			}
+			assert.Equal(t, tc.wantErr, err)
+		})
+	}
+}
+
+func TestModelRegistry_InvalidRule(t *testing.T) {
+	type invalidSize struct {
+		Name string `orm:"size=abc"`
+	}
+	type invalidRegex struct {
+		Name string `validate:"regex=[a-z"`
+	}
+	type withoutParam struct {
+		Name string `validate:"min"`
+	}
+
+	tcs := []struct {
+		name    string
+		entity  any
+		wantErr error
+	}{
+		{name: "invalid size", entity: &invalidSize{}, wantErr: errs.ErrInvalidTag("size=abc")},
+		{name: "invalid regex", entity: &invalidRegex{}, wantErr: errs.ErrInvalidTag("regex=[a-z")},
+		{name: "without param", entity: &withoutParam{}, wantErr: errs.ErrInvalidTag("min")},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := NewRegistry().GetModel(tc.entity)
+			assert.Equal(t, tc.wantErr, err)
+		})
+	}
+}
+
+func TestModelRegistry_ValidateTag(t *testing.T) {
+	type sharedTag struct {
+		Code  string `validate:"required,len=3,regex=^\\d{1\\,3}$,email"`
+		Level int    `validate:"omitempty,min=1"`
+	}
+
+	m, err := NewRegistry().GetModel(&sharedTag{})
+	require.NoError(t, err)
+
+	code := m.Fields["Code"].Rules
+	require.Len(t, code, 1)
+	assert.Equal(t, ruleRegex, code[0].Name)
+	assert.Equal(t, `^\d{1,3}$`, code[0].Param)
+
+	level := m.Fields["Level"].Rules
+	require.Len(t, level, 1)
+	assert.Equal(t, ruleMin, level[0].Name)
+
+	assert.NoError(t, m.Validate(&sharedTag{Code: "123", Level: 1}))
+	assert.Equal(t, &ValidationError{
+		Fields: []FieldError{{Field: "Code", Rule: ruleRegex, Param: `^\d{1,3}$`, Value: "1234"}},
+	}, m.Validate(&sharedTag{Code: "1234", Level: 1}))
+}
+
+func TestValidationError_Error(t *testing.T) {
+	err := &ValidationError{
+		Row: 1,
+		Fields: []FieldError{
+			{Field: "Name", Rule: "not_null"},
+			{Field: "Age", Rule: "max", Param: "150", Value: 200},
+		},
+	}
+	assert.Equal(t, "[easy-orm] validation failed on row 1: Name: not_null, Age: max=150", err.Error())
+}
@@ -12,6 +12,9 @@ var (
 
 type orm interface {
 	getCore() *core
+	// queryContext query on primary, used by statements writing data such as "INSERT ... RETURNING".
 	queryContext(ctx context.Context, sql string, args ...any) (*sql.Rows, error)
+	// readContext query on replica if any, used by statements only reading data.
+	readContext(ctx context.Context, sql string, args ...any) (*sql.Rows, error)
 	execContext(ctx context.Context, sql string, args ...any) (sql.Result, error)
 }
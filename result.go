@@ -1,6 +1,10 @@
 package easyorm
 
-import "database/sql"
+import (
+	"database/sql"
+
+	"github.com/JrMarcco/easy-orm/internal/errs"
+)
 
 // Result sql execute result.
 type Result struct {
@@ -29,3 +33,42 @@ func (r Result) LastInsertId() int64 {
 func (r Result) Err() error {
 	return r.err
 }
+
+var _ sql.Result = (batchResult)(nil)
+
+// batchResult aggregates the results of a statement split into multiple batches.
+type batchResult []sql.Result
+
+// LastInsertId returns the last insert id of the last batch.
+func (b batchResult) LastInsertId() (int64, error) {
+	if len(b) == 0 {
+		return 0, nil
+	}
+	return b[len(b)-1].LastInsertId()
+}
+
+// RowsAffected returns the sum of rows affected by all batches.
+func (b batchResult) RowsAffected() (int64, error) {
+	var total int64
+	for _, res := range b {
+		rows, err := res.RowsAffected()
+		if err != nil {
+			return 0, err
+		}
+		total += rows
+	}
+	return total, nil
+}
+
+var _ sql.Result = (returningResult)(0)
+
+// returningResult the result of statement with "RETURNING" clause, which is executed as a query.
+type returningResult int64
+
+func (r returningResult) LastInsertId() (int64, error) {
+	return 0, errs.ErrUnsupportedLastInsertId
+}
+
+func (r returningResult) RowsAffected() (int64, error) {
+	return int64(r), nil
+}
@@ -0,0 +1,146 @@
+package easyorm
+
+import (
+	"context"
+	"errors"
+	"math/rand/v2"
+	"reflect"
+	"time"
+)
+
+// TxOpt option of DB.DoTx.
+type TxOpt func(opts *txOptions)
+
+type txOptions struct {
+	maxAttempts int
+	baseDelay   time.Duration
+	maxDelay    time.Duration
+	retryable   func(err error) bool
+}
+
+// TxWithRetry re-run the business function in a fresh transaction at most maxAttempts times in total,
+// when the transaction fails with a retryable error, like serialization failure or deadlock.
+func TxWithRetry(maxAttempts int) TxOpt {
+	return func(opts *txOptions) {
+		opts.maxAttempts = maxAttempts
+	}
+}
+
+// TxWithBackoff the delay before retrying grows exponentially from base up to max, with full jitter.
+func TxWithBackoff(base time.Duration, max time.Duration) TxOpt {
+	return func(opts *txOptions) {
+		opts.baseDelay = base
+		opts.maxDelay = max
+	}
+}
+
+// TxWithRetryClassifier classify whether the error is retryable, defaults to the classifier of dialect.
+func TxWithRetryClassifier(retryable func(err error) bool) TxOpt {
+	return func(opts *txOptions) {
+		opts.retryable = retryable
+	}
+}
+
+// backoff returns the delay before the next attempt, attempt starts from 1.
+func (o *txOptions) backoff(attempt int) time.Duration {
+	delay := o.baseDelay << (attempt - 1)
+	if delay <= 0 || delay > o.maxDelay {
+		delay = o.maxDelay
+	}
+	if delay <= 0 {
+		return 0
+	}
+	return rand.N(delay + 1)
+}
+
+func sleepContext(ctx context.Context, d time.Duration) error {
+	if d <= 0 {
+		return ctx.Err()
+	}
+
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+// sqlStateOf extract SQLSTATE from driver error without depending on drivers,
+// e.g. pgx and lib/pq implement "SQLState() string", go-sql-driver/mysql has "SQLState [5]byte" field.
+func sqlStateOf(err error) string {
+	var stater interface{ SQLState() string }
+	if errors.As(err, &stater) {
+		return stater.SQLState()
+	}
+
+	for ; err != nil; err = errors.Unwrap(err) {
+		field, ok := errField(err, "SQLState")
+		if !ok {
+			continue
+		}
+
+		switch field.Kind() {
+		case reflect.String:
+			return field.String()
+		case reflect.Array:
+			if field.Type().Elem().Kind() != reflect.Uint8 {
+				continue
+			}
+			state := make([]byte, field.Len())
+			for i := range state {
+				state[i] = byte(field.Index(i).Uint())
+			}
+			return string(state)
+		default:
+		}
+	}
+	return ""
+}
+
+// errNumberOf extract vendor error number from driver error without depending on drivers,
+// e.g. go-sql-driver/mysql and go-mssqldb have "Number" field, godror implements "Code() int",
+// mattn/go-sqlite3 has "Code" field.
+func errNumberOf(err error) (int64, bool) {
+	var coder interface{ Code() int }
+	if errors.As(err, &coder) {
+		return int64(coder.Code()), true
+	}
+
+	for ; err != nil; err = errors.Unwrap(err) {
+		for _, name := range []string{"Number", "Code"} {
+			field, ok := errField(err, name)
+			if !ok {
+				continue
+			}
+
+			if field.CanInt() {
+				return field.Int(), true
+			}
+			if field.CanUint() {
+				return int64(field.Uint()), true
+			}
+		}
+	}
+	return 0, false
+}
+
+func errField(err error, name string) (reflect.Value, bool) {
+	val := reflect.ValueOf(err)
+	if val.Kind() == reflect.Pointer {
+		if val.IsNil() {
+			return reflect.Value{}, false
+		}
+		val = val.Elem()
+	}
+
+	if val.Kind() != reflect.Struct {
+		return reflect.Value{}, false
+	}
+
+	field := val.FieldByName(name)
+	return field, field.IsValid()
+}
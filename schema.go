@@ -0,0 +1,25 @@
+package easyorm
+
+import "context"
+
+type schemaKey struct{}
+
+// WithSchema returns a context whose statements access tables in the schema instead of the schema of model,
+// e.g. "tenant_42" for schema-per-tenant isolation. It applies to joined tables and sub queries as well.
+func WithSchema(ctx context.Context, schema string) context.Context {
+	return context.WithValue(ctx, schemaKey{}, schema)
+}
+
+// SchemaFromContext returns the schema stored by WithSchema.
+func SchemaFromContext(ctx context.Context) string {
+	schema, _ := ctx.Value(schemaKey{}).(string)
+	return schema
+}
+
+// schemaOf returns the schema statements executed with the context access tables in, empty for the schema of model.
+func (c *core) schemaOf(ctx context.Context) string {
+	if c.schemaFunc != nil {
+		return c.schemaFunc(ctx)
+	}
+	return SchemaFromContext(ctx)
+}
@@ -0,0 +1,369 @@
+package schema
+
+import (
+	"strings"
+	"unicode"
+
+	"github.com/JrMarcco/easy-orm/internal/errs"
+)
+
+// ParseDDL parse the tables from "CREATE TABLE" and "CREATE INDEX" statements, other statements are ignored.
+//
+// Columns are nullable unless declared "NOT NULL" or in primary key,
+// and the unique column constraint is parsed as the unique index named like "uk_user_email".
+// Foreign keys, checks and other options are ignored.
+func ParseDDL(content string) ([]*Table, error) {
+	var tables []*Table
+	for _, stmt := range SplitStatements(content) {
+		p := &ddlParser{tokens: tokenize(stmt)}
+
+		switch {
+		case p.accept("CREATE", "TABLE"):
+			t, err := p.createTable()
+			if err != nil {
+				return nil, err
+			}
+			tables = append(tables, t)
+		case p.accept("CREATE", "INDEX"), p.accept("CREATE", "UNIQUE", "INDEX"):
+			unique := strings.EqualFold(p.tokens[1].val, "UNIQUE")
+			if err := p.createIndex(tables, unique); err != nil {
+				return nil, err
+			}
+		}
+	}
+	return tables, nil
+}
+
+type tokenKind uint8
+
+const (
+	tokenWord tokenKind = iota
+	// tokenIdent the quoted identifier, whose value is unquoted.
+	tokenIdent
+	// tokenString the string literal with quotes.
+	tokenString
+	tokenPunct
+)
+
+type token struct {
+	kind tokenKind
+	val  string
+}
+
+// tokenize split the statement into words, quoted identifiers, string literals and punctuations.
+func tokenize(stmt string) []token {
+	var tokens []token
+	for i := 0; i < len(stmt); {
+		c := stmt[i]
+		switch {
+		case unicode.IsSpace(rune(c)):
+			i++
+		case c == '`' || c == '"':
+			end := strings.IndexByte(stmt[i+1:], c)
+			if end < 0 {
+				end = len(stmt) - i - 1
+			}
+			tokens = append(tokens, token{kind: tokenIdent, val: stmt[i+1 : i+1+end]})
+			i += end + 2
+		case c == '\'':
+			end := i + 1
+			for end < len(stmt) && (stmt[end] != '\'' || strings.HasPrefix(stmt[end:], "''")) {
+				if stmt[end] == '\'' {
+					end++
+				}
+				end++
+			}
+			end = min(end+1, len(stmt))
+			tokens = append(tokens, token{kind: tokenString, val: stmt[i:end]})
+			i = end
+		case strings.IndexByte("(),.;", c) >= 0:
+			tokens = append(tokens, token{kind: tokenPunct, val: string(c)})
+			i++
+		default:
+			end := i + 1
+			for end < len(stmt) && !unicode.IsSpace(rune(stmt[end])) && strings.IndexByte("(),.;`\"'", stmt[end]) < 0 {
+				end++
+			}
+			tokens = append(tokens, token{kind: tokenWord, val: stmt[i:end]})
+			i = end
+		}
+	}
+	return tokens
+}
+
+type ddlParser struct {
+	tokens []token
+	pos    int
+}
+
+// accept consumes the keywords if the following tokens are them.
+func (p *ddlParser) accept(keywords ...string) bool {
+	if p.pos+len(keywords) > len(p.tokens) {
+		return false
+	}
+	for i, kw := range keywords {
+		if !p.peekIs(i, kw) {
+			return false
+		}
+	}
+	p.pos += len(keywords)
+	return true
+}
+
+// peekIs reports whether the token at offset is the keyword or punctuation.
+func (p *ddlParser) peekIs(offset int, keyword string) bool {
+	i := p.pos + offset
+	return i < len(p.tokens) && p.tokens[i].kind != tokenIdent && p.tokens[i].kind != tokenString &&
+		strings.EqualFold(p.tokens[i].val, keyword)
+}
+
+func (p *ddlParser) done() bool {
+	return p.pos >= len(p.tokens)
+}
+
+func (p *ddlParser) next() token {
+	if p.done() {
+		return token{}
+	}
+	p.pos++
+	return p.tokens[p.pos-1]
+}
+
+// qualifiedName parse the name qualified like "catalog.schema.table".
+func (p *ddlParser) qualifiedName() []string {
+	segments := []string{p.next().val}
+	for p.accept(".") {
+		segments = append(segments, p.next().val)
+	}
+	return segments
+}
+
+// group returns the tokens in parentheses and consumes them, nil if the next token is not "(".
+func (p *ddlParser) group() []token {
+	if !p.peekIs(0, "(") {
+		return nil
+	}
+
+	start, depth := p.pos, 0
+	for !p.done() {
+		tok := p.next()
+		switch {
+		case tok.kind == tokenPunct && tok.val == "(":
+			depth++
+		case tok.kind == tokenPunct && tok.val == ")":
+			depth--
+		}
+		if depth == 0 {
+			return p.tokens[start+1 : p.pos-1]
+		}
+	}
+	return p.tokens[start+1:]
+}
+
+// names returns the names of columns in parentheses, the length or order of index column is skipped.
+func (p *ddlParser) names() []string {
+	var names []string
+	for _, item := range splitTokens(p.group()) {
+		if len(item) > 0 {
+			names = append(names, item[0].val)
+		}
+	}
+	return names
+}
+
+func (p *ddlParser) createTable() (*Table, error) {
+	p.accept("IF", "NOT", "EXISTS")
+
+	t := &Table{}
+	switch segments := p.qualifiedName(); len(segments) {
+	case 3:
+		t.Catalog, t.Schema, t.Name = segments[0], segments[1], segments[2]
+	case 2:
+		t.Schema, t.Name = segments[0], segments[1]
+	default:
+		t.Name = segments[0]
+	}
+
+	if !p.peekIs(0, "(") {
+		return nil, errs.ErrInvalidTable(t.Name)
+	}
+
+	for _, item := range splitTokens(p.group()) {
+		if len(item) == 0 {
+			continue
+		}
+
+		ip := &ddlParser{tokens: item}
+		if ip.accept("CONSTRAINT") {
+			ip.next()
+		}
+
+		switch {
+		case ip.accept("PRIMARY", "KEY"):
+			t.PrimaryKey = ip.names()
+		case ip.accept("UNIQUE"):
+			_ = ip.accept("KEY") || ip.accept("INDEX")
+			t.Indexes = append(t.Indexes, ip.index(t.Name, true))
+		case ip.accept("KEY"), ip.accept("INDEX"):
+			t.Indexes = append(t.Indexes, ip.index(t.Name, false))
+		case ip.peekIs(0, "FOREIGN"), ip.peekIs(0, "CHECK"), ip.peekIs(0, "FULLTEXT"), ip.peekIs(0, "SPATIAL"),
+			ip.peekIs(0, "EXCLUDE"):
+		default:
+			ip.column(t)
+		}
+	}
+
+	for i, col := range t.Columns {
+		for _, pk := range t.PrimaryKey {
+			if col.Name == pk {
+				t.Columns[i].Nullable = false
+			}
+		}
+	}
+	return t, nil
+}
+
+// index parse the index of table constraint like "uk_name (`name`)", the name is optional.
+func (p *ddlParser) index(table string, unique bool) Index {
+	idx := Index{Unique: unique}
+	if !p.peekIs(0, "(") {
+		idx.Name = p.next().val
+	}
+	idx.Columns = p.names()
+
+	if idx.Name == "" && len(idx.Columns) > 0 {
+		prefix := "idx_"
+		if unique {
+			prefix = "uk_"
+		}
+		idx.Name = prefix + table + "_" + strings.Join(idx.Columns, "_")
+	}
+	return idx
+}
+
+// columnConstraints the keywords ending the type of column.
+var columnConstraints = map[string]struct{}{
+	"NOT": {}, "NULL": {}, "DEFAULT": {}, "PRIMARY": {}, "UNIQUE": {}, "KEY": {}, "AUTO_INCREMENT": {},
+	"AUTOINCREMENT": {}, "REFERENCES": {}, "CHECK": {}, "COMMENT": {}, "COLLATE": {}, "CONSTRAINT": {},
+	"GENERATED": {}, "ON": {}, "CHARSET": {}, "IDENTITY": {},
+}
+
+// column parse the definition of column, like "`name` VARCHAR(64) NOT NULL DEFAULT ”".
+func (p *ddlParser) column(t *Table) {
+	col := Column{Name: p.next().val, Nullable: true}
+
+	var typ strings.Builder
+	for !p.done() {
+		tok := p.tokens[p.pos]
+		if _, ok := columnConstraints[strings.ToUpper(tok.val)]; ok && tok.kind == tokenWord {
+			break
+		}
+		if p.peekIs(0, "CHARACTER") && p.peekIs(1, "SET") {
+			break
+		}
+
+		if tok.kind == tokenPunct && tok.val == "(" {
+			typ.WriteByte('(')
+			for i, item := range splitTokens(p.group()) {
+				if i > 0 {
+					typ.WriteByte(',')
+				}
+				for _, param := range item {
+					typ.WriteString(param.val)
+				}
+			}
+			typ.WriteByte(')')
+			continue
+		}
+
+		if typ.Len() > 0 {
+			typ.WriteByte(' ')
+		}
+		typ.WriteString(p.next().val)
+	}
+	col.Type = typ.String()
+
+	for !p.done() {
+		switch {
+		case p.accept("NOT", "NULL"):
+			col.Nullable = false
+		case p.accept("NULL"):
+			col.Nullable = true
+		case p.accept("DEFAULT"):
+			dflt := p.next().val
+			if p.peekIs(0, "(") {
+				// function call like "now()"
+				dflt += "(" + joinTokens(p.group()) + ")"
+			}
+			col.Default = &dflt
+		case p.accept("PRIMARY", "KEY"):
+			t.PrimaryKey = append(t.PrimaryKey, col.Name)
+		case p.accept("UNIQUE"):
+			p.accept("KEY")
+			t.Indexes = append(t.Indexes, Index{Name: "uk_" + t.Name + "_" + col.Name, Columns: []string{col.Name}, Unique: true})
+		default:
+			p.next()
+		}
+	}
+	t.Columns = append(t.Columns, col)
+}
+
+// createIndex parse "CREATE INDEX name ON table (columns)" and add the index to the table parsed.
+func (p *ddlParser) createIndex(tables []*Table, unique bool) error {
+	p.accept("IF", "NOT", "EXISTS")
+
+	idx := Index{Unique: unique}
+	name := p.qualifiedName()
+	idx.Name = name[len(name)-1]
+
+	if !p.accept("ON") {
+		return errs.ErrInvalidTable(idx.Name)
+	}
+	table := p.qualifiedName()
+	p.accept("USING", "BTREE")
+	idx.Columns = p.names()
+
+	tableName := table[len(table)-1]
+	for _, t := range tables {
+		if t.Name == tableName {
+			t.Indexes = append(t.Indexes, idx)
+			return nil
+		}
+	}
+	return errs.ErrInvalidTable(tableName)
+}
+
+// splitTokens split the tokens by commas out of parentheses.
+func splitTokens(tokens []token) [][]token {
+	var items [][]token
+
+	depth, start := 0, 0
+	for i, tok := range tokens {
+		if tok.kind != tokenPunct {
+			continue
+		}
+		switch tok.val {
+		case "(":
+			depth++
+		case ")":
+			depth--
+		case ",":
+			if depth == 0 {
+				items = append(items, tokens[start:i])
+				start = i + 1
+			}
+		}
+	}
+	if start < len(tokens) {
+		items = append(items, tokens[start:])
+	}
+	return items
+}
+
+func joinTokens(tokens []token) string {
+	var sb strings.Builder
+	for _, tok := range tokens {
+		sb.WriteString(tok.val)
+	}
+	return sb.String()
+}
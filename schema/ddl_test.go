@@ -0,0 +1,108 @@
+package schema
+
+import (
+	"testing"
+
+	"github.com/JrMarcco/easy-orm/internal/errs"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseDDL(t *testing.T) {
+	active := "'active'"
+	zero := "0"
+	now := "now()"
+	escaped := "'it''s'"
+
+	tcs := []struct {
+		name       string
+		content    string
+		wantTables []*Table
+		wantErr    error
+	}{
+		{
+			name: "mysql",
+			content: "-- users\n" +
+				"CREATE TABLE IF NOT EXISTS `user` (\n" +
+				"  `id` BIGINT UNSIGNED NOT NULL AUTO_INCREMENT,\n" +
+				"  `email` VARCHAR(64) NOT NULL COMMENT 'email, unique',\n" +
+				"  `status` VARCHAR(16) CHARACTER SET utf8mb4 NOT NULL DEFAULT 'active',\n" +
+				"  `price` DECIMAL(10, 2) DEFAULT 0,\n" +
+				"  `remark` TEXT,\n" +
+				"  PRIMARY KEY (`id`),\n" +
+				"  UNIQUE KEY `uk_email` (`email`),\n" +
+				"  KEY `idx_status_price` (`status`, `price`(8)),\n" +
+				"  CONSTRAINT `fk_x` FOREIGN KEY (`id`) REFERENCES `x` (`id`)\n" +
+				") ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;\n" +
+				"INSERT INTO `user` VALUES (1);",
+			wantTables: []*Table{
+				{
+					Name: "user",
+					Columns: []Column{
+						{Name: "id", Type: "BIGINT UNSIGNED"},
+						{Name: "email", Type: "VARCHAR(64)"},
+						{Name: "status", Type: "VARCHAR(16)", Default: &active},
+						{Name: "price", Type: "DECIMAL(10,2)", Nullable: true, Default: &zero},
+						{Name: "remark", Type: "TEXT", Nullable: true},
+					},
+					PrimaryKey: []string{"id"},
+					Indexes: []Index{
+						{Name: "uk_email", Columns: []string{"email"}, Unique: true},
+						{Name: "idx_status_price", Columns: []string{"status", "price"}},
+					},
+				},
+			},
+		}, {
+			name: "postgres",
+			content: `CREATE TABLE "biz"."order" (` +
+				`"id" BIGSERIAL PRIMARY KEY, ` +
+				`"code" CHARACTER VARYING(32) NOT NULL UNIQUE, ` +
+				`"note" TEXT DEFAULT 'it''s', ` +
+				`"created_at" TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT now());` +
+				`CREATE INDEX IF NOT EXISTS "idx_order_created_at" ON "biz"."order" USING btree ("created_at" DESC);`,
+			wantTables: []*Table{
+				{
+					Schema: "biz",
+					Name:   "order",
+					Columns: []Column{
+						{Name: "id", Type: "BIGSERIAL"},
+						{Name: "code", Type: "CHARACTER VARYING(32)"},
+						{Name: "note", Type: "TEXT", Nullable: true, Default: &escaped},
+						{Name: "created_at", Type: "TIMESTAMP WITH TIME ZONE", Default: &now},
+					},
+					PrimaryKey: []string{"id"},
+					Indexes: []Index{
+						{Name: "uk_order_code", Columns: []string{"code"}, Unique: true},
+						{Name: "idx_order_created_at", Columns: []string{"created_at"}},
+					},
+				},
+			},
+		}, {
+			name: "sqlite",
+			content: "CREATE TABLE t (a INTEGER, b TEXT NOT NULL, PRIMARY KEY (a, b), UNIQUE (b));" +
+				"CREATE UNIQUE INDEX uk_t_a ON t (a);",
+			wantTables: []*Table{
+				{
+					Name:       "t",
+					Columns:    []Column{{Name: "a", Type: "INTEGER"}, {Name: "b", Type: "TEXT"}},
+					PrimaryKey: []string{"a", "b"},
+					Indexes: []Index{
+						{Name: "uk_t_b", Columns: []string{"b"}, Unique: true},
+						{Name: "uk_t_a", Columns: []string{"a"}, Unique: true},
+					},
+				},
+			},
+		}, {
+			name:    "index on unknown table",
+			content: "CREATE INDEX idx_x ON x (a);",
+			wantErr: errs.ErrInvalidTable("x"),
+		},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			tables, err := ParseDDL(tc.content)
+			assert.Equal(t, tc.wantErr, err)
+			assert.Equal(t, tc.wantTables, tables)
+		})
+	}
+}
@@ -0,0 +1,442 @@
+package schema
+
+import (
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	easyorm "github.com/JrMarcco/easy-orm"
+	"github.com/JrMarcco/easy-orm/internal/errs"
+)
+
+var (
+	MySQL    Dialect = mysql{}
+	Postgres Dialect = postgres{}
+	SQLite   Dialect = sqlite{}
+)
+
+var (
+	timeType  = reflect.TypeOf(time.Time{})
+	bytesType = reflect.TypeOf([]byte(nil))
+)
+
+// Dialect the data definition language of database.
+type Dialect interface {
+	// quote the opening and closing quote of identifier.
+	quote() (byte, byte)
+	// columnType the column type of go type, typ is neither pointer nor sql.Null* type,
+	// size is the length of string or bytes, 0 if not declared.
+	columnType(typ reflect.Type, size int) (string, bool)
+	// transactionalDDL whether DDL statements can be rolled back in transaction.
+	transactionalDDL() bool
+
+	// listTables the query of names of tables in the current database or schema, read as tableRow.
+	listTables() string
+	// inspectColumns the query of columns of table in order, the columns are read as columnRow.
+	inspectColumns(t *Table) (string, []any)
+	// inspectIndexes the query of indexes of table ordered by name and position, the columns are read as indexRow.
+	inspectIndexes(t *Table) (string, []any)
+	// inspectedType the column type of column read.
+	inspectedType(col columnRow) string
+	// normalizeType the type in lower case without aliases, so that the same types are equal.
+	normalizeType(typ string) string
+	// modifyColumn write the statement modifying column have to want, nothing if not changed.
+	modifyColumn(w *writer, t *Table, have Column, want Column) error
+	dropIndex(w *writer, t *Table, name string)
+}
+
+// DialectOf returns the DDL dialect of easy-orm dialect.
+func DialectOf(dialect easyorm.Dialect) (Dialect, error) {
+	switch dialect {
+	case easyorm.MySQLDialect, easyorm.MySQLLegacyDialect:
+		return MySQL, nil
+	case easyorm.PostgresDialect:
+		return Postgres, nil
+	case easyorm.SQLiteDialect:
+		return SQLite, nil
+	}
+	return nil, errs.ErrUnsupportedDialect(dialect)
+}
+
+var _ Dialect = (*mysql)(nil)
+
+type mysql struct{}
+
+func (m mysql) quote() (byte, byte) {
+	return '`', '`'
+}
+
+func (m mysql) transactionalDDL() bool {
+	return false
+}
+
+func (m mysql) columnType(typ reflect.Type, size int) (string, bool) {
+	switch typ {
+	case timeType:
+		return "DATETIME", true
+	case bytesType:
+		if size > 0 {
+			return "VARBINARY(" + strconv.Itoa(size) + ")", true
+		}
+		return "BLOB", true
+	}
+
+	switch typ.Kind() {
+	case reflect.Bool:
+		return "TINYINT(1)", true
+	case reflect.Int8:
+		return "TINYINT", true
+	case reflect.Int16:
+		return "SMALLINT", true
+	case reflect.Int32:
+		return "INT", true
+	case reflect.Int, reflect.Int64:
+		return "BIGINT", true
+	case reflect.Uint8:
+		return "TINYINT UNSIGNED", true
+	case reflect.Uint16:
+		return "SMALLINT UNSIGNED", true
+	case reflect.Uint32:
+		return "INT UNSIGNED", true
+	case reflect.Uint, reflect.Uint64:
+		return "BIGINT UNSIGNED", true
+	case reflect.Float32:
+		return "FLOAT", true
+	case reflect.Float64:
+		return "DOUBLE", true
+	case reflect.String:
+		// text can not be indexed without prefix length, so varchar is preferred
+		if size == 0 {
+			size = 255
+		}
+		return "VARCHAR(" + strconv.Itoa(size) + ")", true
+	}
+	return "", false
+}
+
+var _ Dialect = (*postgres)(nil)
+
+type postgres struct{}
+
+func (p postgres) quote() (byte, byte) {
+	return '"', '"'
+}
+
+func (p postgres) transactionalDDL() bool {
+	return true
+}
+
+func (p postgres) columnType(typ reflect.Type, size int) (string, bool) {
+	switch typ {
+	case timeType:
+		return "TIMESTAMP", true
+	case bytesType:
+		return "BYTEA", true
+	}
+
+	switch typ.Kind() {
+	case reflect.Bool:
+		return "BOOLEAN", true
+	case reflect.Int8, reflect.Int16, reflect.Uint8:
+		return "SMALLINT", true
+	case reflect.Int32, reflect.Uint16:
+		return "INTEGER", true
+	case reflect.Int, reflect.Int64, reflect.Uint32:
+		return "BIGINT", true
+	case reflect.Uint, reflect.Uint64:
+		return "NUMERIC(20)", true
+	case reflect.Float32:
+		return "REAL", true
+	case reflect.Float64:
+		return "DOUBLE PRECISION", true
+	case reflect.String:
+		if size > 0 {
+			return "VARCHAR(" + strconv.Itoa(size) + ")", true
+		}
+		return "TEXT", true
+	}
+	return "", false
+}
+
+var _ Dialect = (*sqlite)(nil)
+
+type sqlite struct{}
+
+func (s sqlite) quote() (byte, byte) {
+	return '"', '"'
+}
+
+func (s sqlite) transactionalDDL() bool {
+	return true
+}
+
+func (s sqlite) columnType(typ reflect.Type, size int) (string, bool) {
+	switch typ {
+	case timeType:
+		return "DATETIME", true
+	case bytesType:
+		return "BLOB", true
+	}
+
+	switch typ.Kind() {
+	case reflect.Bool,
+		reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return "INTEGER", true
+	case reflect.Float32, reflect.Float64:
+		return "REAL", true
+	case reflect.String:
+		if size > 0 {
+			return "VARCHAR(" + strconv.Itoa(size) + ")", true
+		}
+		return "TEXT", true
+	}
+	return "", false
+}
+
+func (m mysql) listTables() string {
+	return "SELECT table_name AS name FROM information_schema.tables " +
+		"WHERE table_schema = DATABASE() AND table_type = 'BASE TABLE' ORDER BY table_name;"
+}
+
+func (m mysql) inspectColumns(t *Table) (string, []any) {
+	where, args := m.tableSchema(t)
+	return "SELECT column_name AS name, column_type AS type, is_nullable = 'YES' AS nullable, column_default AS dflt " +
+		"FROM information_schema.columns WHERE " + where + " ORDER BY ordinal_position;", args
+}
+
+func (m mysql) inspectIndexes(t *Table) (string, []any) {
+	where, args := m.tableSchema(t)
+	return "SELECT index_name AS name, column_name AS column_name, non_unique = 0 AS is_unique, " +
+		"index_name = 'PRIMARY' AS is_primary FROM information_schema.statistics WHERE " + where +
+		" ORDER BY index_name, seq_in_index;", args
+}
+
+// tableSchema the condition of table in the database of table, or the current database.
+func (m mysql) tableSchema(t *Table) (string, []any) {
+	if t.Schema != "" {
+		return "table_schema = ? AND table_name = ?", []any{t.Schema, t.Name}
+	}
+	return "table_schema = DATABASE() AND table_name = ?", []any{t.Name}
+}
+
+func (m mysql) inspectedType(col columnRow) string {
+	return col.Type
+}
+
+// mysqlDisplayWidth the display width of integer type deprecated since mysql 8.0.17, like "int(11)".
+var mysqlDisplayWidth = regexp.MustCompile(`^(tinyint|smallint|mediumint|int|bigint)\(\d+\)`)
+
+func (m mysql) normalizeType(typ string) string {
+	typ = normalizeType(typ, map[string]string{
+		"integer":          "int",
+		"bool":             "tinyint(1)",
+		"boolean":          "tinyint(1)",
+		"numeric":          "decimal",
+		"double precision": "double",
+	})
+	if !strings.HasPrefix(typ, "tinyint(1)") {
+		typ = mysqlDisplayWidth.ReplaceAllString(typ, "$1")
+	}
+	return typ
+}
+
+func (m mysql) modifyColumn(w *writer, t *Table, have Column, want Column) error {
+	if typ, nullable, dflt := changes(m, have, want); !typ && !nullable && !dflt {
+		return nil
+	}
+
+	w.alterTable(t)
+	w.WriteString("MODIFY COLUMN ")
+	w.column(want)
+	w.WriteByte(';')
+	return nil
+}
+
+func (m mysql) dropIndex(w *writer, t *Table, name string) {
+	w.WriteString("DROP INDEX ")
+	w.quote(name)
+	w.WriteString(" ON ")
+	w.table(t)
+	w.WriteByte(';')
+}
+
+func (p postgres) listTables() string {
+	return "SELECT table_name AS name FROM information_schema.tables " +
+		"WHERE table_schema = current_schema() AND table_type = 'BASE TABLE' ORDER BY table_name;"
+}
+
+func (p postgres) inspectColumns(t *Table) (string, []any) {
+	where, args := p.tableSchema(t, "table_schema", "table_name")
+	return "SELECT column_name AS name, data_type AS type, is_nullable = 'YES' AS nullable, column_default AS dflt, " +
+		"character_maximum_length AS char_len, numeric_precision AS precision, numeric_scale AS scale " +
+		"FROM information_schema.columns WHERE " + where + " ORDER BY ordinal_position;", args
+}
+
+func (p postgres) inspectIndexes(t *Table) (string, []any) {
+	where, args := p.tableSchema(t, "n.nspname", "t.relname")
+	// indexes backing constraints except primary key are skipped, which can not be dropped by "DROP INDEX"
+	return "SELECT i.relname AS name, a.attname AS column_name, ix.indisunique AS is_unique, " +
+		"ix.indisprimary AS is_primary FROM pg_index ix " +
+		"JOIN pg_class t ON t.oid = ix.indrelid JOIN pg_class i ON i.oid = ix.indexrelid " +
+		"JOIN pg_namespace n ON n.oid = t.relnamespace " +
+		"JOIN LATERAL unnest(ix.indkey) WITH ORDINALITY AS k(attnum, ord) ON true " +
+		"JOIN pg_attribute a ON a.attrelid = t.oid AND a.attnum = k.attnum WHERE " + where +
+		" AND (ix.indisprimary OR NOT EXISTS (SELECT 1 FROM pg_constraint c WHERE c.conindid = ix.indexrelid))" +
+		" ORDER BY i.relname, k.ord;", args
+}
+
+// tableSchema the condition of table in the schema of table, or the current schema.
+func (p postgres) tableSchema(t *Table, schemaCol string, tableCol string) (string, []any) {
+	if t.Schema != "" {
+		return schemaCol + " = $1 AND " + tableCol + " = $2", []any{t.Schema, t.Name}
+	}
+	return schemaCol + " = current_schema() AND " + tableCol + " = $1", []any{t.Name}
+}
+
+func (p postgres) inspectedType(col columnRow) string {
+	switch {
+	case col.CharLen.Valid:
+		return col.Type + "(" + strconv.FormatInt(col.CharLen.Int64, 10) + ")"
+	case col.Type == "numeric" && col.Precision.Valid:
+		return "numeric(" + strconv.FormatInt(col.Precision.Int64, 10) + "," + strconv.FormatInt(col.Scale.Int64, 10) + ")"
+	}
+	return col.Type
+}
+
+func (p postgres) normalizeType(typ string) string {
+	typ = normalizeType(typ, map[string]string{
+		"character varying":           "varchar",
+		"character":                   "char",
+		"int":                         "integer",
+		"int2":                        "smallint",
+		"int4":                        "integer",
+		"int8":                        "bigint",
+		"float4":                      "real",
+		"float8":                      "double precision",
+		"bool":                        "boolean",
+		"decimal":                     "numeric",
+		"timestamp without time zone": "timestamp",
+		"timestamptz":                 "timestamp with time zone",
+	})
+	// the scale is 0 by default, like "numeric(20)" of "numeric(20,0)"
+	return strings.Replace(typ, ",0)", ")", 1)
+}
+
+func (p postgres) modifyColumn(w *writer, t *Table, have Column, want Column) error {
+	typ, nullable, dflt := changes(p, have, want)
+	// the default of serial column is owned by its sequence, which is not declared by model
+	if dflt && want.Default == nil && strings.HasPrefix(normalizeDefault(have.Default), "NEXTVAL(") {
+		dflt = false
+	}
+	if !typ && !nullable && !dflt {
+		return nil
+	}
+
+	w.alterTable(t)
+
+	var actions []func()
+	if typ {
+		actions = append(actions, func() {
+			w.WriteString(" TYPE ")
+			w.WriteString(want.Type)
+		})
+	}
+	if nullable {
+		actions = append(actions, func() {
+			if want.Nullable {
+				w.WriteString(" DROP NOT NULL")
+				return
+			}
+			w.WriteString(" SET NOT NULL")
+		})
+	}
+	if dflt {
+		actions = append(actions, func() {
+			if want.Default == nil {
+				w.WriteString(" DROP DEFAULT")
+				return
+			}
+			w.WriteString(" SET DEFAULT ")
+			w.WriteString(*want.Default)
+		})
+	}
+
+	for i, action := range actions {
+		if i > 0 {
+			w.WriteString(", ")
+		}
+		w.WriteString("ALTER COLUMN ")
+		w.quote(want.Name)
+		action()
+	}
+	w.WriteByte(';')
+	return nil
+}
+
+func (p postgres) dropIndex(w *writer, t *Table, name string) {
+	w.WriteString("DROP INDEX ")
+	if t.Schema != "" {
+		w.quote(t.Schema)
+		w.WriteByte('.')
+	}
+	w.quote(name)
+	w.WriteByte(';')
+}
+
+func (s sqlite) listTables() string {
+	return "SELECT name FROM sqlite_master WHERE type = 'table' AND name NOT LIKE 'sqlite_%' ORDER BY name;"
+}
+
+func (s sqlite) inspectColumns(t *Table) (string, []any) {
+	return `SELECT name, type, "notnull" = 0 AS nullable, dflt_value AS dflt, pk FROM pragma_table_info(?) ORDER BY cid;`,
+		[]any{t.Name}
+}
+
+func (s sqlite) inspectIndexes(t *Table) (string, []any) {
+	// only indexes created by "CREATE INDEX" are read, the primary key is read from the columns
+	return `SELECT il.name AS name, ii.name AS column_name, il."unique" AS is_unique, 0 AS is_primary ` +
+		`FROM sqlite_master m JOIN pragma_index_list(m.name) il JOIN pragma_index_info(il.name) ii ` +
+		`WHERE m.type = 'table' AND m.name = ? AND il.origin = 'c' ORDER BY il.name, ii.seqno;`, []any{t.Name}
+}
+
+func (s sqlite) inspectedType(col columnRow) string {
+	return col.Type
+}
+
+func (s sqlite) normalizeType(typ string) string {
+	return normalizeType(typ, nil)
+}
+
+func (s sqlite) modifyColumn(w *writer, t *Table, have Column, want Column) error {
+	if typ, nullable, dflt := changes(s, have, want); typ || nullable || dflt {
+		return errs.ErrUnsupportedModifyColumn(t.Name, want.Name)
+	}
+	return nil
+}
+
+func (s sqlite) dropIndex(w *writer, t *Table, name string) {
+	w.WriteString("DROP INDEX ")
+	w.quote(name)
+	w.WriteByte(';')
+}
+
+// normalizeType the type in lower case with the aliases replaced, the spaces in parameters are removed.
+func normalizeType(typ string, aliases map[string]string) string {
+	typ = strings.Join(strings.Fields(strings.ToLower(typ)), " ")
+	typ = typeParamSpaces.ReplaceAllString(typ, "$1$2")
+
+	base, params := typ, ""
+	if i := strings.IndexByte(typ, '('); i >= 0 {
+		base, params = typ[:i], typ[i:]
+	}
+	if alias, ok := aliases[base]; ok {
+		base = alias
+	}
+	return base + params
+}
+
+// typeParamSpaces the spaces around parentheses and commas of type parameters, like "decimal (10, 2)".
+var typeParamSpaces = regexp.MustCompile(`\s*([(,])\s*|\s*(\))`)
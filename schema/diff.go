@@ -0,0 +1,315 @@
+package schema
+
+import (
+	"context"
+	"database/sql"
+	"slices"
+	"strings"
+
+	easyorm "github.com/JrMarcco/easy-orm"
+	"github.com/JrMarcco/easy-orm/model"
+)
+
+// columnRow the column read from database.
+type columnRow struct {
+	Name     string
+	Type     string
+	Nullable bool
+	Dflt     sql.NullString
+	// CharLen, Precision and Scale complete the type of postgres, like "character varying" of length 64.
+	CharLen   sql.NullInt64
+	Precision sql.NullInt64
+	Scale     sql.NullInt64
+	// Pk the position of column in primary key of sqlite, 0 if not in primary key.
+	Pk int
+}
+
+// indexRow the column of index read from database, ordered by the position in index.
+type indexRow struct {
+	Name       string
+	ColumnName string
+	IsUnique   bool
+	IsPrimary  bool
+}
+
+// tableRow the name of table read from database.
+type tableRow struct {
+	Name string
+}
+
+// Inspect read the definition of the table of model from primary database, nil if the table does not exist.
+// The primary key is returned in PrimaryKey rather than Indexes.
+func Inspect(ctx context.Context, db *easyorm.DB, d Dialect, m *model.Model) (*Table, error) {
+	return inspect(ctx, db, d, &Table{Catalog: m.Catalog, Schema: m.Schema, Name: m.TableName})
+}
+
+// Exists reports whether the table of model exists in primary database.
+func Exists(ctx context.Context, db *easyorm.DB, d Dialect, m *model.Model) (bool, error) {
+	query, args := d.inspectColumns(&Table{Catalog: m.Catalog, Schema: m.Schema, Name: m.TableName})
+	cols, err := easyorm.NewRaw[columnRow](db, query, args...).FindMulti(easyorm.WithPrimary(ctx))
+	if err != nil {
+		return false, err
+	}
+	return len(cols) > 0, nil
+}
+
+// Tables read the definition of all tables in the current database or schema from primary, ordered by name.
+func Tables(ctx context.Context, db *easyorm.DB, d Dialect) ([]*Table, error) {
+	ctx = easyorm.WithPrimary(ctx)
+	rows, err := easyorm.NewRaw[tableRow](db, d.listTables()).FindMulti(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	tables := make([]*Table, 0, len(rows))
+	for _, row := range rows {
+		t, err := inspect(ctx, db, d, &Table{Name: row.Name})
+		if err != nil {
+			return nil, err
+		}
+		if t != nil {
+			tables = append(tables, t)
+		}
+	}
+	return tables, nil
+}
+
+// inspect read the table from primary, as the replicas may lag behind the migrations applied.
+func inspect(ctx context.Context, db *easyorm.DB, d Dialect, t *Table) (*Table, error) {
+	ctx = easyorm.WithPrimary(ctx)
+	query, args := d.inspectColumns(t)
+	cols, err := easyorm.NewRaw[columnRow](db, query, args...).FindMulti(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if len(cols) == 0 {
+		return nil, nil
+	}
+
+	pk := make(map[int]string, 1)
+	for _, col := range cols {
+		var dflt *string
+		if col.Dflt.Valid {
+			dflt = &col.Dflt.String
+		}
+
+		t.Columns = append(t.Columns, Column{
+			Name:     col.Name,
+			Type:     d.inspectedType(*col),
+			Nullable: col.Nullable,
+			Default:  dflt,
+		})
+		if col.Pk > 0 {
+			pk[col.Pk] = col.Name
+		}
+	}
+	for i := 1; i <= len(pk); i++ {
+		t.PrimaryKey = append(t.PrimaryKey, pk[i])
+	}
+
+	query, args = d.inspectIndexes(t)
+	rows, err := easyorm.NewRaw[indexRow](db, query, args...).FindMulti(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, row := range rows {
+		if row.IsPrimary {
+			t.PrimaryKey = append(t.PrimaryKey, row.ColumnName)
+			continue
+		}
+
+		if n := len(t.Indexes); n > 0 && t.Indexes[n-1].Name == row.Name {
+			t.Indexes[n-1].Columns = append(t.Indexes[n-1].Columns, row.ColumnName)
+			continue
+		}
+		t.Indexes = append(t.Indexes, Index{Name: row.Name, Columns: []string{row.ColumnName}, Unique: row.IsUnique})
+	}
+	return t, nil
+}
+
+// AlterOpt the option of altering tables by Alter and Diff.
+type AlterOpt func(a *alterOpts)
+
+type alterOpts struct {
+	dropColumns bool
+	dropIndexes bool
+}
+
+// AlterWithDropColumns drops the columns not in the model, which are kept by default as dropping loses their data.
+func AlterWithDropColumns() AlterOpt {
+	return func(a *alterOpts) {
+		a.dropColumns = true
+	}
+}
+
+// AlterWithDropIndexes drops the indexes not in the model, which are kept by default as they may be created out of the model.
+func AlterWithDropIndexes() AlterOpt {
+	return func(a *alterOpts) {
+		a.dropIndexes = true
+	}
+}
+
+// Diff returns the statements migrating the tables in database to the models of entities registered on db,
+// tables not existing are created.
+func Diff(ctx context.Context, db *easyorm.DB, entities []any, opts ...AlterOpt) ([]string, error) {
+	d, err := DialectOf(db.Dialect())
+	if err != nil {
+		return nil, err
+	}
+
+	var stmts []string
+	for _, entity := range entities {
+		m, err := db.Registry().GetModel(entity)
+		if err != nil {
+			return nil, err
+		}
+
+		want, err := TableOf(d, m)
+		if err != nil {
+			return nil, err
+		}
+
+		have, err := Inspect(ctx, db, d, m)
+		if err != nil {
+			return nil, err
+		}
+
+		alters, err := Alter(d, have, want, opts...)
+		if err != nil {
+			return nil, err
+		}
+		stmts = append(stmts, alters...)
+	}
+	return stmts, nil
+}
+
+// Alter returns the statements altering the table from have to want, have is nil if the table does not exist.
+//
+// Columns are added or modified, and indexes are created or dropped,
+// the index changed is dropped and created again. The primary key is not altered.
+// The columns and indexes not in want are dropped only with AlterWithDropColumns and AlterWithDropIndexes.
+func Alter(d Dialect, have *Table, want *Table, opts ...AlterOpt) ([]string, error) {
+	var o alterOpts
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	if have == nil {
+		stmts := []string{CreateTable(d, want)}
+		for _, idx := range want.Indexes {
+			stmts = append(stmts, CreateIndex(d, want, idx))
+		}
+		return stmts, nil
+	}
+
+	var stmts []string
+	appendStmt := func(write func(w *writer) error) error {
+		w := newWriter(d)
+		if err := write(w); err != nil {
+			return err
+		}
+		if w.Len() > 0 {
+			stmts = append(stmts, w.String())
+		}
+		return nil
+	}
+
+	haveIndexes := make(map[string]Index, len(have.Indexes))
+	for _, idx := range have.Indexes {
+		haveIndexes[idx.Name] = idx
+	}
+	wantIndexes := make(map[string]Index, len(want.Indexes))
+	for _, idx := range want.Indexes {
+		wantIndexes[idx.Name] = idx
+	}
+
+	// indexes are dropped first, which may reference the columns dropped
+	for _, idx := range have.Indexes {
+		if wantIdx, ok := wantIndexes[idx.Name]; ok && !sameIndex(idx, wantIdx) || !ok && o.dropIndexes {
+			_ = appendStmt(func(w *writer) error {
+				d.dropIndex(w, have, idx.Name)
+				return nil
+			})
+		}
+	}
+
+	haveCols := make(map[string]Column, len(have.Columns))
+	for _, col := range have.Columns {
+		haveCols[col.Name] = col
+	}
+
+	for _, col := range want.Columns {
+		haveCol, ok := haveCols[col.Name]
+		if !ok {
+			_ = appendStmt(func(w *writer) error {
+				w.alterTable(have)
+				w.WriteString("ADD COLUMN ")
+				w.column(col)
+				w.WriteByte(';')
+				return nil
+			})
+			continue
+		}
+
+		if err := appendStmt(func(w *writer) error {
+			return d.modifyColumn(w, have, haveCol, col)
+		}); err != nil {
+			return nil, err
+		}
+	}
+
+	if o.dropColumns {
+		for _, col := range have.Columns {
+			if !slices.ContainsFunc(want.Columns, func(c Column) bool { return c.Name == col.Name }) {
+				_ = appendStmt(func(w *writer) error {
+					w.alterTable(have)
+					w.WriteString("DROP COLUMN ")
+					w.quote(col.Name)
+					w.WriteByte(';')
+					return nil
+				})
+			}
+		}
+	}
+
+	for _, idx := range want.Indexes {
+		if haveIdx, ok := haveIndexes[idx.Name]; !ok || !sameIndex(idx, haveIdx) {
+			stmts = append(stmts, CreateIndex(d, have, idx))
+		}
+	}
+	return stmts, nil
+}
+
+// changes returns what is changed from column have to want, the types are compared after normalized by dialect.
+func changes(d Dialect, have Column, want Column) (typ bool, nullable bool, dflt bool) {
+	typ = d.normalizeType(have.Type) != d.normalizeType(want.Type)
+	nullable = have.Nullable != want.Nullable
+	dflt = normalizeDefault(have.Default) != normalizeDefault(want.Default)
+	return
+}
+
+// normalizeDefault the default value without quotes and type cast, like "active" of "'active'::character varying".
+func normalizeDefault(dflt *string) string {
+	if dflt == nil {
+		return "NULL"
+	}
+
+	val := strings.TrimSpace(*dflt)
+	if i := strings.LastIndex(val, "::"); i > 0 && !strings.Contains(val[i:], "'") {
+		val = val[:i]
+	}
+	val = strings.Trim(val, "'")
+	return strings.ToUpper(val)
+}
+
+func sameIndex(a, b Index) bool {
+	return a.Unique == b.Unique && slices.Equal(a.Columns, b.Columns)
+}
+
+// alterTable write the beginning of "ALTER TABLE" statement.
+func (w *writer) alterTable(t *Table) {
+	w.WriteString("ALTER TABLE ")
+	w.table(t)
+	w.WriteByte(' ')
+}
@@ -0,0 +1,308 @@
+package schema
+
+import (
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	easyorm "github.com/JrMarcco/easy-orm"
+	"github.com/JrMarcco/easy-orm/internal/errs"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type diffTestModel struct {
+	Id     uint64 `orm:"pk"`
+	Email  string `orm:"size=64,unique"`
+	Name   string `orm:"size=32,index=idx_name_age"`
+	Age    int8   `orm:"index=idx_name_age"`
+	Status string `orm:"size=16,default='active'"`
+	Remark *string
+}
+
+func TestAlter(t *testing.T) {
+	active := "'active'"
+	castActive := "'active'::character varying"
+	zero := "0"
+	nextval := "nextval('diff_test_model_id_seq'::regclass)"
+
+	tcs := []struct {
+		name      string
+		dialect   Dialect
+		have      *Table
+		opts      []AlterOpt
+		wantStmts []string
+		wantErr   error
+	}{
+		{
+			name:    "mysql",
+			dialect: MySQL,
+			have: &Table{
+				Name: "diff_test_model",
+				Columns: []Column{
+					{Name: "id", Type: "bigint(20) unsigned"},
+					{Name: "email", Type: "varchar(64)"},
+					{Name: "name", Type: "varchar(16)"},
+					{Name: "status", Type: "varchar(16)", Default: &active},
+					{Name: "remark", Type: "varchar(255)"},
+					{Name: "legacy", Type: "int", Default: &zero},
+				},
+				PrimaryKey: []string{"id"},
+				Indexes: []Index{
+					{Name: "uk_diff_test_model_email", Columns: []string{"email"}, Unique: true},
+					{Name: "idx_name_age", Columns: []string{"name"}},
+					{Name: "idx_legacy", Columns: []string{"legacy"}},
+				},
+			},
+			wantStmts: []string{
+				"DROP INDEX `idx_name_age` ON `diff_test_model`;",
+				"ALTER TABLE `diff_test_model` MODIFY COLUMN `name` VARCHAR(32) NOT NULL;",
+				"ALTER TABLE `diff_test_model` ADD COLUMN `age` TINYINT NOT NULL;",
+				"ALTER TABLE `diff_test_model` MODIFY COLUMN `remark` VARCHAR(255);",
+				"CREATE INDEX `idx_name_age` ON `diff_test_model` (`name`, `age`);",
+			},
+		}, {
+			name:    "mysql drop columns and indexes",
+			dialect: MySQL,
+			have: &Table{
+				Name: "diff_test_model",
+				Columns: []Column{
+					{Name: "id", Type: "bigint(20) unsigned"},
+					{Name: "email", Type: "varchar(64)"},
+					{Name: "name", Type: "varchar(16)"},
+					{Name: "status", Type: "varchar(16)", Default: &active},
+					{Name: "remark", Type: "varchar(255)"},
+					{Name: "legacy", Type: "int", Default: &zero},
+				},
+				PrimaryKey: []string{"id"},
+				Indexes: []Index{
+					{Name: "uk_diff_test_model_email", Columns: []string{"email"}, Unique: true},
+					{Name: "idx_name_age", Columns: []string{"name"}},
+					{Name: "idx_legacy", Columns: []string{"legacy"}},
+				},
+			},
+			opts: []AlterOpt{AlterWithDropColumns(), AlterWithDropIndexes()},
+			wantStmts: []string{
+				"DROP INDEX `idx_name_age` ON `diff_test_model`;",
+				"DROP INDEX `idx_legacy` ON `diff_test_model`;",
+				"ALTER TABLE `diff_test_model` MODIFY COLUMN `name` VARCHAR(32) NOT NULL;",
+				"ALTER TABLE `diff_test_model` ADD COLUMN `age` TINYINT NOT NULL;",
+				"ALTER TABLE `diff_test_model` MODIFY COLUMN `remark` VARCHAR(255);",
+				"ALTER TABLE `diff_test_model` DROP COLUMN `legacy`;",
+				"CREATE INDEX `idx_name_age` ON `diff_test_model` (`name`, `age`);",
+			},
+		}, {
+			name:    "postgres",
+			dialect: Postgres,
+			have: &Table{
+				Schema: "biz",
+				Name:   "diff_test_model",
+				Columns: []Column{
+					{Name: "id", Type: "numeric(20,0)", Default: &nextval},
+					{Name: "email", Type: "character varying(64)"},
+					{Name: "name", Type: "text", Nullable: true},
+					{Name: "age", Type: "smallint"},
+					{Name: "status", Type: "character varying(16)", Default: &castActive},
+					{Name: "remark", Type: "text", Nullable: true, Default: &zero},
+				},
+				PrimaryKey: []string{"id"},
+				Indexes: []Index{
+					{Name: "uk_diff_test_model_email", Columns: []string{"email"}},
+					{Name: "idx_name_age", Columns: []string{"name", "age"}},
+				},
+			},
+			wantStmts: []string{
+				`DROP INDEX "biz"."uk_diff_test_model_email";`,
+				`ALTER TABLE "biz"."diff_test_model" ALTER COLUMN "name" TYPE VARCHAR(32), ` +
+					`ALTER COLUMN "name" SET NOT NULL;`,
+				`ALTER TABLE "biz"."diff_test_model" ALTER COLUMN "remark" DROP DEFAULT;`,
+				`CREATE UNIQUE INDEX "uk_diff_test_model_email" ON "biz"."diff_test_model" ("email");`,
+			},
+		}, {
+			name:    "sqlite",
+			dialect: SQLite,
+			have: &Table{
+				Name: "diff_test_model",
+				Columns: []Column{
+					{Name: "id", Type: "INTEGER"},
+					{Name: "email", Type: "VARCHAR(64)"},
+					{Name: "name", Type: "VARCHAR(32)"},
+					{Name: "age", Type: "INTEGER"},
+					{Name: "status", Type: "VARCHAR(16)", Default: &active},
+				},
+			},
+			wantStmts: []string{
+				`ALTER TABLE "diff_test_model" ADD COLUMN "remark" TEXT;`,
+				`CREATE UNIQUE INDEX "uk_diff_test_model_email" ON "diff_test_model" ("email");`,
+				`CREATE INDEX "idx_name_age" ON "diff_test_model" ("name", "age");`,
+			},
+		}, {
+			name:    "sqlite modify",
+			dialect: SQLite,
+			have: &Table{
+				Name:    "diff_test_model",
+				Columns: []Column{{Name: "id", Type: "TEXT"}},
+			},
+			wantErr: errs.ErrUnsupportedModifyColumn("diff_test_model", "id"),
+		}, {
+			name:    "not exist",
+			dialect: MySQL,
+			wantStmts: []string{
+				"CREATE TABLE `diff_test_model` (`id` BIGINT UNSIGNED NOT NULL, `email` VARCHAR(64) NOT NULL, " +
+					"`name` VARCHAR(32) NOT NULL, `age` TINYINT NOT NULL, " +
+					"`status` VARCHAR(16) NOT NULL DEFAULT 'active', `remark` VARCHAR(255), PRIMARY KEY (`id`));",
+				"CREATE UNIQUE INDEX `uk_diff_test_model_email` ON `diff_test_model` (`email`);",
+				"CREATE INDEX `idx_name_age` ON `diff_test_model` (`name`, `age`);",
+			},
+		},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			db, err := easyorm.OpenDB(nil, easyorm.MySQLDialect)
+			require.NoError(t, err)
+
+			m, err := db.Registry().GetModel(&diffTestModel{})
+			require.NoError(t, err)
+
+			want, err := TableOf(tc.dialect, m)
+			require.NoError(t, err)
+			if tc.have != nil {
+				want.Schema = tc.have.Schema
+			}
+
+			stmts, err := Alter(tc.dialect, tc.have, want, tc.opts...)
+			assert.Equal(t, tc.wantErr, err)
+			assert.Equal(t, tc.wantStmts, stmts)
+		})
+	}
+}
+
+func TestDiff(t *testing.T) {
+	mockDB, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+	require.NoError(t, err)
+	defer func() { _ = mockDB.Close() }()
+
+	db, err := easyorm.OpenDB(mockDB, easyorm.MySQLDialect)
+	require.NoError(t, err)
+
+	mock.ExpectQuery("SELECT column_name AS name, column_type AS type, is_nullable = 'YES' AS nullable, " +
+		"column_default AS dflt FROM information_schema.columns " +
+		"WHERE table_schema = DATABASE() AND table_name = ? ORDER BY ordinal_position;").
+		WithArgs("diff_test_model").
+		WillReturnRows(sqlmock.NewRows([]string{"name", "type", "nullable", "dflt"}).
+			AddRow("id", "bigint unsigned", 0, nil).
+			AddRow("email", "varchar(64)", 0, nil).
+			AddRow("name", "varchar(32)", 0, nil).
+			AddRow("age", "tinyint", 0, nil).
+			AddRow("status", "varchar(16)", 0, "active").
+			AddRow("remark", "varchar(255)", 1, nil))
+	mock.ExpectQuery("SELECT index_name AS name, column_name AS column_name, non_unique = 0 AS is_unique, " +
+		"index_name = 'PRIMARY' AS is_primary FROM information_schema.statistics " +
+		"WHERE table_schema = DATABASE() AND table_name = ? ORDER BY index_name, seq_in_index;").
+		WithArgs("diff_test_model").
+		WillReturnRows(sqlmock.NewRows([]string{"name", "column_name", "is_unique", "is_primary"}).
+			AddRow("PRIMARY", "id", 1, 1).
+			AddRow("idx_name_age", "name", 0, 0).
+			AddRow("idx_name_age", "age", 0, 0))
+
+	stmts, err := Diff(t.Context(), db, []any{&diffTestModel{}})
+	require.NoError(t, err)
+	assert.Equal(t, []string{
+		"CREATE UNIQUE INDEX `uk_diff_test_model_email` ON `diff_test_model` (`email`);",
+	}, stmts)
+
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestInspect_Primary(t *testing.T) {
+	mockDB, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer func() { _ = mockDB.Close() }()
+
+	replicaDB, replicaMock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer func() { _ = replicaDB.Close() }()
+
+	db, err := easyorm.OpenDB(mockDB, easyorm.SQLiteDialect, easyorm.DBWithReplicas(replicaDB))
+	require.NoError(t, err)
+
+	mock.ExpectQuery("SELECT name FROM sqlite_master").
+		WillReturnRows(sqlmock.NewRows([]string{"name"}).AddRow("user"))
+	mock.ExpectQuery("SELECT name, type, .* FROM pragma_table_info").
+		WithArgs("user").
+		WillReturnRows(sqlmock.NewRows([]string{"name", "type", "nullable", "dflt", "pk"}).AddRow("id", "INTEGER", 0, nil, 1))
+	mock.ExpectQuery("SELECT il.name AS name, .* FROM sqlite_master").
+		WithArgs("user").
+		WillReturnRows(sqlmock.NewRows([]string{"name", "column_name", "is_unique", "is_primary"}))
+
+	tables, err := Tables(t.Context(), db, SQLite)
+	require.NoError(t, err)
+	assert.Len(t, tables, 1)
+
+	require.NoError(t, mock.ExpectationsWereMet())
+	require.NoError(t, replicaMock.ExpectationsWereMet())
+}
+
+func TestInspect_NotExist(t *testing.T) {
+	mockDB, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer func() { _ = mockDB.Close() }()
+
+	db, err := easyorm.OpenDB(mockDB, easyorm.SQLiteDialect)
+	require.NoError(t, err)
+
+	m, err := db.Registry().GetModel(&diffTestModel{})
+	require.NoError(t, err)
+
+	mock.ExpectQuery("SELECT name, type, .* FROM pragma_table_info").
+		WithArgs("diff_test_model").
+		WillReturnRows(sqlmock.NewRows([]string{"name", "type", "nullable", "dflt", "pk"}))
+
+	table, err := Inspect(t.Context(), db, SQLite, m)
+	require.NoError(t, err)
+	assert.Nil(t, table)
+
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestTables(t *testing.T) {
+	mockDB, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+	require.NoError(t, err)
+	defer func() { _ = mockDB.Close() }()
+
+	db, err := easyorm.OpenDB(mockDB, easyorm.SQLiteDialect)
+	require.NoError(t, err)
+
+	mock.ExpectQuery("SELECT name FROM sqlite_master WHERE type = 'table' AND name NOT LIKE 'sqlite_%' ORDER BY name;").
+		WillReturnRows(sqlmock.NewRows([]string{"name"}).AddRow("user"))
+	mock.ExpectQuery(`SELECT name, type, "notnull" = 0 AS nullable, dflt_value AS dflt, pk ` +
+		`FROM pragma_table_info(?) ORDER BY cid;`).
+		WithArgs("user").
+		WillReturnRows(sqlmock.NewRows([]string{"name", "type", "nullable", "dflt", "pk"}).
+			AddRow("id", "INTEGER", 0, nil, 1).
+			AddRow("name", "VARCHAR(32)", 1, "''", 0))
+	mock.ExpectQuery(`SELECT il.name AS name, ii.name AS column_name, il."unique" AS is_unique, 0 AS is_primary ` +
+		`FROM sqlite_master m JOIN pragma_index_list(m.name) il JOIN pragma_index_info(il.name) ii ` +
+		`WHERE m.type = 'table' AND m.name = ? AND il.origin = 'c' ORDER BY il.name, ii.seqno;`).
+		WithArgs("user").
+		WillReturnRows(sqlmock.NewRows([]string{"name", "column_name", "is_unique", "is_primary"}).
+			AddRow("idx_user_name", "name", 0, 0))
+
+	tables, err := Tables(t.Context(), db, SQLite)
+	require.NoError(t, err)
+
+	empty := "''"
+	assert.Equal(t, []*Table{
+		{
+			Name: "user",
+			Columns: []Column{
+				{Name: "id", Type: "INTEGER"},
+				{Name: "name", Type: "VARCHAR(32)", Nullable: true, Default: &empty},
+			},
+			PrimaryKey: []string{"id"},
+			Indexes:    []Index{{Name: "idx_user_name", Columns: []string{"name"}}},
+		},
+	}, tables)
+
+	require.NoError(t, mock.ExpectationsWereMet())
+}
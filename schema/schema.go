@@ -0,0 +1,250 @@
+package schema
+
+import (
+	"reflect"
+	"strings"
+
+	"github.com/JrMarcco/easy-orm/internal/errs"
+	"github.com/JrMarcco/easy-orm/model"
+)
+
+// Table the definition of table.
+type Table struct {
+	Catalog string
+	Schema  string
+	Name    string
+
+	Columns    []Column
+	PrimaryKey []string
+	Indexes    []Index
+}
+
+// Column the definition of column.
+type Column struct {
+	Name     string
+	Type     string
+	Nullable bool
+	// Default the default value in SQL, nil if no default.
+	Default *string
+}
+
+// Index the definition of index, which is unique or not.
+type Index struct {
+	Name    string
+	Columns []string
+	Unique  bool
+}
+
+// TableOf returns the definition of table of model,
+// the column type is mapped from go type unless declared by type tag.
+//
+// the index tagged without name is named like "idx_user_name" or "uk_user_email" for unique index,
+// columns tagged with the same index name are composited in the order of fields.
+func TableOf(d Dialect, m *model.Model) (*Table, error) {
+	t := &Table{
+		Catalog: m.Catalog,
+		Schema:  m.Schema,
+		Name:    m.TableName,
+		Columns: make([]Column, 0, len(m.SeqFields)),
+	}
+
+	indexes := make(map[string]int, 4)
+	addIndex := func(name string, col string, unique bool) {
+		if name == model.TagValUnnamed {
+			prefix := "idx_"
+			if unique {
+				prefix = "uk_"
+			}
+			name = prefix + m.TableName + "_" + col
+		}
+
+		if i, ok := indexes[name]; ok {
+			t.Indexes[i].Columns = append(t.Indexes[i].Columns, col)
+			return
+		}
+		indexes[name] = len(t.Indexes)
+		t.Indexes = append(t.Indexes, Index{Name: name, Columns: []string{col}, Unique: unique})
+	}
+
+	for _, field := range m.SeqFields {
+		def := field.Def
+
+		typ := def.Type
+		if typ == "" {
+			var ok bool
+			if typ, ok = d.columnType(valueType(field.Typ), def.Size); !ok {
+				return nil, errs.ErrUnsupportedColumnType(field.FiledName, field.Typ)
+			}
+		}
+
+		t.Columns = append(t.Columns, Column{
+			Name:     field.ColumnName,
+			Type:     typ,
+			Nullable: def.Nullable,
+			Default:  def.Default,
+		})
+
+		if def.PK {
+			t.PrimaryKey = append(t.PrimaryKey, field.ColumnName)
+		}
+		if def.Unique != "" {
+			addIndex(def.Unique, field.ColumnName, true)
+		}
+		if def.Index != "" {
+			addIndex(def.Index, field.ColumnName, false)
+		}
+	}
+	return t, nil
+}
+
+// valueType returns the type of value stored, which is dereferenced and unwrapped from sql.Null* type.
+func valueType(typ reflect.Type) reflect.Type {
+	for typ.Kind() == reflect.Pointer {
+		typ = typ.Elem()
+	}
+	if valTyp, ok := model.NullValueType(typ); ok {
+		return valTyp
+	}
+	return typ
+}
+
+// Create returns the statements creating the table of model and its indexes.
+func Create(d Dialect, m *model.Model) ([]string, error) {
+	t, err := TableOf(d, m)
+	if err != nil {
+		return nil, err
+	}
+
+	stmts := make([]string, 0, len(t.Indexes)+1)
+	stmts = append(stmts, CreateTable(d, t))
+	for _, idx := range t.Indexes {
+		stmts = append(stmts, CreateIndex(d, t, idx))
+	}
+	return stmts, nil
+}
+
+// Drop returns the statement dropping the table of model if exists.
+func Drop(d Dialect, m *model.Model) string {
+	return DropTable(d, &Table{Catalog: m.Catalog, Schema: m.Schema, Name: m.TableName})
+}
+
+// CreateTable returns the "CREATE TABLE" statement of table, indexes are created by CreateIndex.
+func CreateTable(d Dialect, t *Table) string {
+	return createTable(d, t, false)
+}
+
+// CreateTableIfNotExists returns the "CREATE TABLE IF NOT EXISTS" statement of table.
+func CreateTableIfNotExists(d Dialect, t *Table) string {
+	return createTable(d, t, true)
+}
+
+func createTable(d Dialect, t *Table, ifNotExists bool) string {
+	w := newWriter(d)
+
+	w.WriteString("CREATE TABLE ")
+	if ifNotExists {
+		w.WriteString("IF NOT EXISTS ")
+	}
+	w.table(t)
+	w.WriteString(" (")
+	for i, col := range t.Columns {
+		if i > 0 {
+			w.WriteString(", ")
+		}
+		w.column(col)
+	}
+
+	if len(t.PrimaryKey) > 0 {
+		w.WriteString(", PRIMARY KEY ")
+		w.columns(t.PrimaryKey)
+	}
+	w.WriteString(");")
+	return w.String()
+}
+
+// CreateIndex returns the "CREATE INDEX" statement of index on table.
+func CreateIndex(d Dialect, t *Table, idx Index) string {
+	w := newWriter(d)
+
+	w.WriteString("CREATE ")
+	if idx.Unique {
+		w.WriteString("UNIQUE ")
+	}
+	w.WriteString("INDEX ")
+	w.quote(idx.Name)
+	w.WriteString(" ON ")
+	w.table(t)
+	w.WriteByte(' ')
+	w.columns(idx.Columns)
+	w.WriteByte(';')
+	return w.String()
+}
+
+// DropTable returns the "DROP TABLE" statement of table, which succeeds if the table does not exist.
+func DropTable(d Dialect, t *Table) string {
+	w := newWriter(d)
+
+	w.WriteString("DROP TABLE IF EXISTS ")
+	w.table(t)
+	w.WriteByte(';')
+	return w.String()
+}
+
+// TransactionalDDL reports whether DDL statements of dialect can be rolled back in transaction,
+// mysql commits the transaction implicitly on DDL.
+func TransactionalDDL(d Dialect) bool {
+	return d.transactionalDDL()
+}
+
+// writer the buffer writing DDL statement of dialect.
+type writer struct {
+	strings.Builder
+	dialect Dialect
+}
+
+func newWriter(d Dialect) *writer {
+	return &writer{dialect: d}
+}
+
+func (w *writer) quote(name string) {
+	opening, closing := w.dialect.quote()
+	w.WriteByte(opening)
+	w.WriteString(name)
+	w.WriteByte(closing)
+}
+
+// table write the table name qualified by schema and catalog if any.
+func (w *writer) table(t *Table) {
+	for _, segment := range []string{t.Catalog, t.Schema} {
+		if segment != "" {
+			w.quote(segment)
+			w.WriteByte('.')
+		}
+	}
+	w.quote(t.Name)
+}
+
+func (w *writer) column(col Column) {
+	w.quote(col.Name)
+	w.WriteByte(' ')
+	w.WriteString(col.Type)
+	if !col.Nullable {
+		w.WriteString(" NOT NULL")
+	}
+	if col.Default != nil {
+		w.WriteString(" DEFAULT ")
+		w.WriteString(*col.Default)
+	}
+}
+
+// columns write the list of columns in parentheses, like "(`id`, `name`)".
+func (w *writer) columns(cols []string) {
+	w.WriteByte('(')
+	for i, col := range cols {
+		if i > 0 {
+			w.WriteString(", ")
+		}
+		w.quote(col)
+	}
+	w.WriteByte(')')
+}
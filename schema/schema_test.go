@@ -0,0 +1,129 @@
+package schema
+
+import (
+	"database/sql"
+	"testing"
+	"time"
+
+	easyorm "github.com/JrMarcco/easy-orm"
+	"github.com/JrMarcco/easy-orm/internal/errs"
+	"github.com/JrMarcco/easy-orm/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type schemaTestModel struct {
+	Id        uint64         `orm:"pk"`
+	Email     string         `orm:"size=64,unique"`
+	Name      string         `orm:"index=idx_name_age"`
+	Age       int8           `orm:"index=idx_name_age"`
+	Nickname  sql.NullString `orm:"size=32"`
+	Status    string         `orm:"size=16,default='active'"`
+	Price     float64        `orm:"type=DECIMAL(10,2),default=0"`
+	Avatar    []byte         `orm:"nullable"`
+	Enabled   bool
+	DeletedAt *time.Time `orm:"index"`
+}
+
+func TestCreate(t *testing.T) {
+	tcs := []struct {
+		name      string
+		dialect   Dialect
+		opts      []model.Opt
+		wantStmts []string
+	}{
+		{
+			name:    "mysql",
+			dialect: MySQL,
+			wantStmts: []string{
+				"CREATE TABLE `schema_test_model` (`id` BIGINT UNSIGNED NOT NULL, `email` VARCHAR(64) NOT NULL, " +
+					"`name` VARCHAR(255) NOT NULL, `age` TINYINT NOT NULL, `nickname` VARCHAR(32), " +
+					"`status` VARCHAR(16) NOT NULL DEFAULT 'active', `price` DECIMAL(10,2) NOT NULL DEFAULT 0, " +
+					"`avatar` BLOB, `enabled` TINYINT(1) NOT NULL, `deleted_at` DATETIME, PRIMARY KEY (`id`));",
+				"CREATE UNIQUE INDEX `uk_schema_test_model_email` ON `schema_test_model` (`email`);",
+				"CREATE INDEX `idx_name_age` ON `schema_test_model` (`name`, `age`);",
+				"CREATE INDEX `idx_schema_test_model_deleted_at` ON `schema_test_model` (`deleted_at`);",
+			},
+		}, {
+			name:    "postgres",
+			dialect: Postgres,
+			opts:    []model.Opt{model.WithTableOpt("biz.user")},
+			wantStmts: []string{
+				`CREATE TABLE "biz"."user" ("id" NUMERIC(20) NOT NULL, "email" VARCHAR(64) NOT NULL, ` +
+					`"name" TEXT NOT NULL, "age" SMALLINT NOT NULL, "nickname" VARCHAR(32), ` +
+					`"status" VARCHAR(16) NOT NULL DEFAULT 'active', "price" DECIMAL(10,2) NOT NULL DEFAULT 0, ` +
+					`"avatar" BYTEA, "enabled" BOOLEAN NOT NULL, "deleted_at" TIMESTAMP, PRIMARY KEY ("id"));`,
+				`CREATE UNIQUE INDEX "uk_user_email" ON "biz"."user" ("email");`,
+				`CREATE INDEX "idx_name_age" ON "biz"."user" ("name", "age");`,
+				`CREATE INDEX "idx_user_deleted_at" ON "biz"."user" ("deleted_at");`,
+			},
+		}, {
+			name:    "sqlite",
+			dialect: SQLite,
+			wantStmts: []string{
+				`CREATE TABLE "schema_test_model" ("id" INTEGER NOT NULL, "email" VARCHAR(64) NOT NULL, ` +
+					`"name" TEXT NOT NULL, "age" INTEGER NOT NULL, "nickname" VARCHAR(32), ` +
+					`"status" VARCHAR(16) NOT NULL DEFAULT 'active', "price" DECIMAL(10,2) NOT NULL DEFAULT 0, ` +
+					`"avatar" BLOB, "enabled" INTEGER NOT NULL, "deleted_at" DATETIME, PRIMARY KEY ("id"));`,
+				`CREATE UNIQUE INDEX "uk_schema_test_model_email" ON "schema_test_model" ("email");`,
+				`CREATE INDEX "idx_name_age" ON "schema_test_model" ("name", "age");`,
+				`CREATE INDEX "idx_schema_test_model_deleted_at" ON "schema_test_model" ("deleted_at");`,
+			},
+		},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			m, err := model.NewRegistry().RegisterModel(&schemaTestModel{}, tc.opts...)
+			require.NoError(t, err)
+
+			stmts, err := Create(tc.dialect, m)
+			require.NoError(t, err)
+			assert.Equal(t, tc.wantStmts, stmts)
+		})
+	}
+}
+
+func TestCreate_UnsupportedType(t *testing.T) {
+	type unsupported struct {
+		Id    uint64
+		Attrs map[string]string
+	}
+
+	m, err := model.NewRegistry().GetModel(&unsupported{})
+	require.NoError(t, err)
+
+	_, err = Create(MySQL, m)
+	assert.Equal(t, errs.ErrUnsupportedColumnType("Attrs", m.Fields["Attrs"].Typ), err)
+}
+
+func TestDrop(t *testing.T) {
+	m, err := model.NewRegistry().RegisterModel(&schemaTestModel{}, model.WithTableOpt("biz.user"))
+	require.NoError(t, err)
+
+	assert.Equal(t, "DROP TABLE IF EXISTS `biz`.`user`;", Drop(MySQL, m))
+	assert.Equal(t, `DROP TABLE IF EXISTS "biz"."user";`, Drop(Postgres, m))
+}
+
+func TestDialectOf(t *testing.T) {
+	tcs := []struct {
+		name        string
+		dialect     easyorm.Dialect
+		wantDialect Dialect
+		wantErr     error
+	}{
+		{name: "mysql", dialect: easyorm.MySQLDialect, wantDialect: MySQL},
+		{name: "mysql legacy", dialect: easyorm.MySQLLegacyDialect, wantDialect: MySQL},
+		{name: "postgres", dialect: easyorm.PostgresDialect, wantDialect: Postgres},
+		{name: "sqlite", dialect: easyorm.SQLiteDialect, wantDialect: SQLite},
+		{name: "oracle", dialect: easyorm.OracleDialect, wantErr: errs.ErrUnsupportedDialect(easyorm.OracleDialect)},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			d, err := DialectOf(tc.dialect)
+			assert.Equal(t, tc.wantErr, err)
+			assert.Equal(t, tc.wantDialect, d)
+		})
+	}
+}
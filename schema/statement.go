@@ -0,0 +1,87 @@
+package schema
+
+import "strings"
+
+// SplitStatements split the content of sql file into statements by semicolons,
+// except those in quotes, comments or dollar-quoted strings of postgres like $$ ... $$ or $body$ ... $body$.
+// Backslash escapes the quote in single and double quoted strings as mysql does, like 'a\';b'.
+func SplitStatements(content string) []string {
+	var stmts []string
+	appendStmt := func(stmt string) {
+		if stmt = strings.TrimSpace(stmt); stmt != "" {
+			stmts = append(stmts, stmt)
+		}
+	}
+
+	var b strings.Builder
+	for i := 0; i < len(content); i++ {
+		c := content[i]
+		switch {
+		case c == '\'' || c == '"' || c == '`':
+			end := quoteEnd(content, i)
+			b.WriteString(content[i:end])
+			i = end - 1
+		case c == '$' && dollarTag(content[i:]) != "":
+			tag := dollarTag(content[i:])
+			end := strings.Index(content[i+len(tag):], tag)
+			if end < 0 {
+				end = len(content) - i - 2*len(tag)
+			}
+			b.WriteString(content[i:min(i+end+2*len(tag), len(content))])
+			i += end + 2*len(tag) - 1
+		case strings.HasPrefix(content[i:], "--"):
+			// comments are dropped
+			end := strings.IndexByte(content[i:], '\n')
+			if end < 0 {
+				end = len(content) - i
+			}
+			i += end - 1
+		case strings.HasPrefix(content[i:], "/*"):
+			end := strings.Index(content[i+2:], "*/")
+			if end < 0 {
+				end = len(content) - i - 2
+			}
+			i += end + 3
+		case c == ';':
+			appendStmt(b.String())
+			b.Reset()
+		default:
+			b.WriteByte(c)
+		}
+	}
+	appendStmt(b.String())
+	return stmts
+}
+
+// quoteEnd returns the index after the quote closing the one at start, or the length of content if unclosed.
+func quoteEnd(content string, start int) int {
+	quote := content[start]
+	for i := start + 1; i < len(content); i++ {
+		switch content[i] {
+		case '\\':
+			if quote != '`' {
+				i++
+			}
+		case quote:
+			return i + 1
+		}
+	}
+	return len(content)
+}
+
+// dollarTag returns the tag of dollar-quoted string at the beginning of content like $$ or $body$,
+// or empty if content is not started with a tag, e.g. the placeholder $1.
+func dollarTag(content string) string {
+	for i := 1; i < len(content); i++ {
+		c := content[i]
+		switch {
+		case c == '$':
+			return content[:i+1]
+		case c == '_' || 'a' <= c && c <= 'z' || 'A' <= c && c <= 'Z':
+		case '0' <= c && c <= '9' && i > 1:
+		default:
+			return ""
+		}
+	}
+	return ""
+}
@@ -0,0 +1,60 @@
+package schema
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSplitStatements(t *testing.T) {
+	tcs := []struct {
+		name      string
+		content   string
+		wantStmts []string
+	}{
+		{
+			name:      "statements",
+			content:   "CREATE TABLE a (id INT);\n\nCREATE TABLE b (id INT);",
+			wantStmts: []string{"CREATE TABLE a (id INT)", "CREATE TABLE b (id INT)"},
+		}, {
+			name:      "semicolon in quotes",
+			content:   "INSERT INTO a VALUES ('a;b', \"c;d\", `e;f`);",
+			wantStmts: []string{"INSERT INTO a VALUES ('a;b', \"c;d\", `e;f`)"},
+		}, {
+			name:      "comments",
+			content:   "-- create a; and b\nCREATE TABLE a (id INT); /* drop; */\n-- end",
+			wantStmts: []string{"CREATE TABLE a (id INT)"},
+		}, {
+			name:    "dollar quoted",
+			content: "CREATE FUNCTION f() RETURNS INT AS $$ SELECT 1; $$ LANGUAGE SQL;",
+			wantStmts: []string{
+				"CREATE FUNCTION f() RETURNS INT AS $$ SELECT 1; $$ LANGUAGE SQL",
+			},
+		}, {
+			name: "dollar quoted with tag",
+			content: "CREATE FUNCTION f() RETURNS TEXT AS $body$ SELECT '$$;'; $body$ LANGUAGE SQL;\n" +
+				"PREPARE p AS SELECT $1;",
+			wantStmts: []string{
+				"CREATE FUNCTION f() RETURNS TEXT AS $body$ SELECT '$$;'; $body$ LANGUAGE SQL",
+				"PREPARE p AS SELECT $1",
+			},
+		}, {
+			name:      "backslash escaped quote",
+			content:   "INSERT INTO a VALUES ('a\\';b', \"c\\\";d\", 'e\\\\');SELECT 1;",
+			wantStmts: []string{"INSERT INTO a VALUES ('a\\';b', \"c\\\";d\", 'e\\\\')", "SELECT 1"},
+		}, {
+			name:      "unclosed quote",
+			content:   "SELECT 'a;",
+			wantStmts: []string{"SELECT 'a;"},
+		}, {
+			name:    "empty",
+			content: " ;\n-- nothing",
+		},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.wantStmts, SplitStatements(tc.content))
+		})
+	}
+}
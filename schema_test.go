@@ -0,0 +1,246 @@
+package easyorm
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/JrMarcco/easy-orm/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type schemaTestUser struct {
+	Id   uint64
+	Name string
+}
+
+type schemaTestOrder struct {
+	Id     uint64
+	UserId uint64
+}
+
+func TestWithSchema(t *testing.T) {
+	ctx := WithSchema(context.Background(), "tenant_42")
+
+	tcs := []struct {
+		name     string
+		mockFunc func(mock sqlmock.Sqlmock)
+		execFunc func(ctx context.Context, db *DB) error
+	}{
+		{
+			name: "select",
+			mockFunc: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(`SELECT * FROM "tenant_42"."schema_test_user" WHERE "id" = $1;`).
+					WithArgs(1).
+					WillReturnRows(sqlmock.NewRows([]string{"id", "name"}))
+			},
+			execFunc: func(ctx context.Context, db *DB) error {
+				_, err := NewSelector[schemaTestUser](db).Where(Col("Id").Eq(1)).FindMulti(ctx)
+				return err
+			},
+		}, {
+			name: "replace schema of model",
+			mockFunc: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(`SELECT * FROM "tenant_42"."user";`).
+					WillReturnRows(sqlmock.NewRows([]string{"id", "name"}))
+			},
+			execFunc: func(ctx context.Context, db *DB) error {
+				if _, err := db.registry.RegisterModel(&schemaTestUser{}, model.WithTableOpt("public.user")); err != nil {
+					return err
+				}
+
+				_, err := NewSelector[schemaTestUser](db).FindMulti(ctx)
+				return err
+			},
+		}, {
+			name: "join",
+			mockFunc: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(`SELECT * FROM "tenant_42"."schema_test_user" AS "u" ` +
+					`INNER JOIN "tenant_42"."schema_test_order" AS "o" ON "u"."id" = "o"."user_id";`).
+					WillReturnRows(sqlmock.NewRows([]string{"id", "name"}))
+			},
+			execFunc: func(ctx context.Context, db *DB) error {
+				user, order := TableAs(&schemaTestUser{}, "u"), TableAs(&schemaTestOrder{}, "o")
+				_, err := NewSelector[schemaTestUser](db).
+					From(user.InnerJoin(order).On(user.Col("Id").Eq(order.Col("UserId")))).
+					FindMulti(ctx)
+				return err
+			},
+		}, {
+			name: "sub query",
+			mockFunc: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(`SELECT * FROM "tenant_42"."schema_test_user" WHERE "id" IN ` +
+					`(SELECT "user_id" FROM "tenant_42"."schema_test_order");`).
+					WillReturnRows(sqlmock.NewRows([]string{"id", "name"}))
+			},
+			execFunc: func(ctx context.Context, db *DB) error {
+				subQuery, err := NewSelector[schemaTestOrder](db).Select(Col("UserId")).ToSubQuery()
+				if err != nil {
+					return err
+				}
+
+				_, err = NewSelector[schemaTestUser](db).Where(Col("Id").InSubQuery(subQuery)).FindMulti(ctx)
+				return err
+			},
+		}, {
+			name: "insert",
+			mockFunc: func(mock sqlmock.Sqlmock) {
+				mock.ExpectExec(`INSERT INTO "tenant_42"."schema_test_user" ("id", "name") VALUES ($1, $2);`).
+					WithArgs(uint64(1), "foo").
+					WillReturnResult(sqlmock.NewResult(1, 1))
+			},
+			execFunc: func(ctx context.Context, db *DB) error {
+				return NewInserter[schemaTestUser](db).Rows(&schemaTestUser{Id: 1, Name: "foo"}).Exec(ctx).Err()
+			},
+		}, {
+			name: "delete",
+			mockFunc: func(mock sqlmock.Sqlmock) {
+				mock.ExpectExec(`DELETE FROM "tenant_42"."schema_test_user" WHERE "id" = $1;`).
+					WithArgs(1).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+			},
+			execFunc: func(ctx context.Context, db *DB) error {
+				return NewDeleter[schemaTestUser](db).Where(Col("Id").Eq(1)).Exec(ctx).Err()
+			},
+		},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			mockDB, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+			require.NoError(t, err)
+			defer func() {
+				_ = mockDB.Close()
+			}()
+
+			db, err := OpenDB(mockDB, PostgresDialect)
+			require.NoError(t, err)
+
+			tc.mockFunc(mock)
+
+			require.NoError(t, tc.execFunc(ctx, db))
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestDBWithSchemaFunc(t *testing.T) {
+	mockDB, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+	require.NoError(t, err)
+	defer func() {
+		_ = mockDB.Close()
+	}()
+
+	type tenantKey struct{}
+	db, err := OpenDB(mockDB, PostgresDialect, DBWithSchemaFunc(func(ctx context.Context) string {
+		if tenant, ok := ctx.Value(tenantKey{}).(string); ok {
+			return "tenant_" + tenant
+		}
+		return ""
+	}))
+	require.NoError(t, err)
+
+	mock.ExpectQuery(`SELECT * FROM "tenant_7"."schema_test_user";`).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "name"}))
+	mock.ExpectQuery(`SELECT * FROM "schema_test_user";`).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "name"}))
+
+	selector := NewSelector[schemaTestUser](db)
+
+	_, err = selector.FindMulti(context.WithValue(context.Background(), tenantKey{}, "7"))
+	require.NoError(t, err)
+
+	_, err = selector.FindMulti(context.Background())
+	require.NoError(t, err)
+
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestQualifiedTable(t *testing.T) {
+	newDB := func(dialect Dialect, tableName string) *DB {
+		db, err := OpenDB(&sql.DB{}, dialect)
+		require.NoError(t, err)
+
+		_, err = db.registry.RegisterModel(&schemaTestUser{}, model.WithTableOpt(tableName))
+		require.NoError(t, err)
+		_, err = db.registry.RegisterModel(&schemaTestOrder{}, model.WithTableOpt(tableName+"_order"))
+		require.NoError(t, err)
+		return db
+	}
+
+	tcs := []struct {
+		name          string
+		builder       StatementBuilder
+		wantStatement *Statement
+	}{
+		{
+			name:          "mysql select",
+			builder:       NewSelector[schemaTestUser](newDB(MySQLDialect, "app.user")),
+			wantStatement: &Statement{SQL: "SELECT * FROM `app`.`user`;"},
+		}, {
+			name: "mysql insert",
+			builder: NewInserter[schemaTestUser](newDB(MySQLDialect, "app.user")).
+				Rows(&schemaTestUser{Id: 1, Name: "foo"}),
+			wantStatement: &Statement{
+				SQL:  "INSERT INTO `app`.`user` (`id`, `name`) VALUES (?, ?);",
+				Args: []any{uint64(1), "foo"},
+			},
+		}, {
+			name:    "sqlite delete",
+			builder: NewDeleter[schemaTestUser](newDB(SQLiteDialect, "main.user")).Where(Col("Id").Eq(1)),
+			wantStatement: &Statement{
+				SQL:  `DELETE FROM "main"."user" WHERE "id" = ?;`,
+				Args: []any{1},
+			},
+		}, {
+			name: "postgres join",
+			builder: func() StatementBuilder {
+				user, order := TableAs(&schemaTestUser{}, "u"), TableOf(&schemaTestOrder{})
+				return NewSelector[schemaTestUser](newDB(PostgresDialect, "public.user")).
+					From(user.LeftJoin(order).On(user.Col("Id").Eq(order.Col("UserId"))))
+			}(),
+			wantStatement: &Statement{
+				SQL: `SELECT * FROM "public"."user" AS "u" LEFT JOIN "public"."user_order" ON "u"."id" = "user_id";`,
+			},
+		}, {
+			name: "postgres upsert",
+			builder: NewInserter[schemaTestUser](newDB(PostgresDialect, "public.user")).
+				Rows(&schemaTestUser{Id: 1, Name: "foo"}).
+				OnConflict("Id").UpdateWhere(Col("Name").Ne("bar")).Update(Col("Name")),
+			wantStatement: &Statement{
+				SQL: `INSERT INTO "public"."user" ("id", "name") VALUES ($1, $2) ON CONFLICT ("id") ` +
+					`DO UPDATE SET "name" = EXCLUDED."name" WHERE "public"."user"."name" != $3;`,
+				Args: []any{uint64(1), "foo", "bar"},
+			},
+		}, {
+			name:    "sql server select",
+			builder: NewSelector[schemaTestUser](newDB(SQLServerDialect, "app.dbo.user")).Where(Col("Id").Eq(1)),
+			wantStatement: &Statement{
+				SQL:  `SELECT * FROM [app].[dbo].[user] WHERE [id] = @p1;`,
+				Args: []any{1},
+			},
+		}, {
+			name: "oracle merge",
+			builder: NewInserter[schemaTestUser](newDB(OracleDialect, "app.user")).
+				Rows(&schemaTestUser{Id: 1, Name: "foo"}).
+				OnConflict("Id").Update(Col("Name")),
+			wantStatement: &Statement{
+				SQL: `MERGE INTO "app"."user" USING (SELECT :1 "id", :2 "name" FROM DUAL) "source" ` +
+					`ON ("app"."user"."id" = "source"."id") ` +
+					`WHEN MATCHED THEN UPDATE SET "name" = "source"."name" ` +
+					`WHEN NOT MATCHED THEN INSERT ("id", "name") VALUES ("source"."id", "source"."name")`,
+				Args: []any{uint64(1), "foo"},
+			},
+		},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			statement, err := tc.builder.Build()
+			require.NoError(t, err)
+			assert.Equal(t, tc.wantStatement, statement)
+		})
+	}
+}
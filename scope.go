@@ -0,0 +1,178 @@
+package easyorm
+
+import (
+	"reflect"
+	"slices"
+
+	"github.com/JrMarcco/easy-orm/internal/errs"
+	"github.com/JrMarcco/easy-orm/model"
+)
+
+var (
+	_ WhereScoper = (*Selector[any])(nil)
+	_ WhereScoper = (*Deleter[any])(nil)
+	_ WhereScoper = (*Updater[any])(nil)
+	_ TableScoper = (*Selector[any])(nil)
+	_ TableScoper = (*Deleter[any])(nil)
+	_ TableScoper = (*Updater[any])(nil)
+	_ TableScoper = (*Inserter[any])(nil)
+	_ ValueScoper = (*Inserter[any])(nil)
+)
+
+// WhereScoper implemented by builders with WHERE clause.
+// Middlewares restrict the statement through it rather than rewriting the SQL,
+// the scopes are cleared when the statement executed again.
+type WhereScoper interface {
+	// ScopeWhere add predicates combined with the WHERE of statement by AND.
+	ScopeWhere(pds ...Predicate)
+}
+
+// TableScoper implemented by Selector, Deleter, Updater and Inserter.
+// Middlewares restrict the rows of every table in the statement through it,
+// including the joined tables and the tables of sub queries, while WhereScoper restricts the model only.
+// The rows inserted are not restricted, only those selected by the sub query of Inserter.FromSelect.
+// The scope is cleared when the statement executed again.
+type TableScoper interface {
+	// ScopeTables restrict the rows of each table to those whose field equals to the value returned by scope.
+	ScopeTables(scope TableScope)
+}
+
+// TableScope returns the field and the value restricting the rows of the table of model,
+// nil field if the table is not restricted, the error fails the building of statement.
+type TableScope func(m *model.Model) (field *model.Field, val any, err error)
+
+// ValueScoper implemented by Inserter.
+// Middlewares set the value of field for all inserted rows through it.
+type ValueScoper interface {
+	// ScopeValue set the value of field for all inserted rows, and insert the field if fields are specified.
+	ScopeValue(fieldName string, val any) error
+}
+
+func (s *Selector[T]) ScopeWhere(pds ...Predicate) {
+	s.scopes = append(slices.Clip(s.scopes), pds...)
+}
+
+func (d *Deleter[T]) ScopeWhere(pds ...Predicate) {
+	d.scopes = append(slices.Clip(d.scopes), pds...)
+}
+
+func (u *Updater[T]) ScopeWhere(pds ...Predicate) {
+	u.scopes = append(slices.Clip(u.scopes), pds...)
+}
+
+func (s *Selector[T]) ScopeTables(scope TableScope) {
+	s.tableScope = scope
+}
+
+func (d *Deleter[T]) ScopeTables(scope TableScope) {
+	d.tableScope = scope
+}
+
+func (u *Updater[T]) ScopeTables(scope TableScope) {
+	u.tableScope = scope
+}
+
+func (i *Inserter[T]) ScopeTables(scope TableScope) {
+	i.tableScope = scope
+}
+
+// ScopeValue fails with ErrScopeValueWithSelect if the rows are inserted by FromSelect,
+// as the value of selected rows can not be set.
+func (i *Inserter[T]) ScopeValue(fieldName string, val any) error {
+	if err := i.initModel(); err != nil {
+		return err
+	}
+
+	if i.query != nil {
+		return errs.ErrScopeValueWithSelect
+	}
+
+	if _, ok := i.model.Fields[fieldName]; !ok {
+		return errs.ErrInvalidField(fieldName)
+	}
+
+	if len(i.fields) > 0 && !slices.Contains(i.fields, fieldName) {
+		i.fields = append(slices.Clip(i.fields), fieldName)
+	}
+
+	for _, row := range i.rows {
+		if !setField(reflect.ValueOf(row).Elem().FieldByName(fieldName), val) {
+			return errs.ErrInvalidValue(fieldName, val)
+		}
+	}
+	return nil
+}
+
+// setField set the value to field, numeric values are converted to the type of field.
+func setField(field reflect.Value, val any) bool {
+	v := reflect.ValueOf(val)
+	if !field.CanSet() || !v.IsValid() {
+		return false
+	}
+
+	switch {
+	case v.Type().AssignableTo(field.Type()):
+	case isNumeric(v.Kind()) && isNumeric(field.Kind()):
+		v = v.Convert(field.Type())
+	default:
+		return false
+	}
+
+	field.Set(v)
+	return true
+}
+
+func isNumeric(kind reflect.Kind) bool {
+	return kind >= reflect.Int && kind <= reflect.Float64
+}
+
+// scopeTable returns the predicate restricting the rows of the table of model by the table scope,
+// col returns the column of field in statement.
+func (b *builder) scopeTable(m *model.Model, col func(fieldName string) Expr) (Predicate, bool, error) {
+	if b.tableScope == nil {
+		return Predicate{}, false, nil
+	}
+
+	field, val, err := b.tableScope(m)
+	if err != nil || field == nil {
+		return Predicate{}, false, err
+	}
+	return Predicate{left: col(field.FiledName), op: opEq, right: valueOf(val)}, true, nil
+}
+
+// modelScopes returns the scopes added by middlewares with the table scope of model,
+// the column is not qualified as the statement has the table of model only.
+// The scopes returned are clipped, so that appending to them leaves the scopes of middlewares untouched.
+func (b *builder) modelScopes(scopes []Predicate) ([]Predicate, error) {
+	scopes = slices.Clip(scopes)
+	pd, ok, err := b.scopeTable(b.model, func(fieldName string) Expr { return Col(fieldName) })
+	if err != nil || !ok {
+		return scopes, err
+	}
+	return append(scopes, pd), nil
+}
+
+// scopedWhere returns the WHERE conditions combined with the scopes added by middlewares.
+func scopedWhere(where []Condition, scopes []Predicate) []Condition {
+	if len(scopes) == 0 {
+		return where
+	}
+
+	var expr Expr
+	for _, c := range where {
+		if expr == nil {
+			expr = c.expr
+			continue
+		}
+		expr = Predicate{left: expr, op: opAnd, right: c.expr}
+	}
+
+	for _, pd := range scopes {
+		if expr == nil {
+			expr = pd
+			continue
+		}
+		expr = Predicate{left: expr, op: opAnd, right: pd}
+	}
+	return []Condition{{typ: condTypWhere, expr: expr}}
+}
@@ -2,9 +2,10 @@ package easyorm
 
 import (
 	"context"
-	"strconv"
+	"slices"
 
 	"github.com/JrMarcco/easy-orm/internal/errs"
+	"github.com/JrMarcco/easy-orm/model"
 )
 
 // selectable marker interface, used to identify optional query columns (e.g., columns, aggregate functions).
@@ -26,12 +27,23 @@ type Selector[T any] struct {
 
 	selectables []selectable
 	where       []Condition
+	scopes      []Predicate
+	// softDelete how the rows marked deleted are filtered, see WithDeleted and OnlyDeleted.
+	softDelete softDeleteMode
+	// track keeps the snapshot of entities found, see Track.
+	track bool
+	// tableScopes the predicates filtering deleted rows of tables and restricting them by the table scope in WHERE,
+	// collected on building tables.
+	tableScopes []Predicate
 	having      []Condition
 	groupBy     []Column
 	orderBy     []OrderBy
 }
 
 func (s *Selector[T]) FindOne(ctx context.Context) (*T, error) {
+	s.scopes = nil
+	s.tableScope = nil
+	s.schema = s.orm.getCore().schemaOf(ctx)
 	if s.limit != 1 {
 		s.limit = 1
 	}
@@ -40,26 +52,59 @@ func (s *Selector[T]) FindOne(ctx context.Context) (*T, error) {
 		return nil, err
 	}
 
-	return findOne[T](ctx, &OrmContext{
+	if sdb, ok := s.orm.(*ShardingDB); ok {
+		res, err := s.findSharding(ctx, sdb)
+		if err != nil {
+			return nil, err
+		}
+
+		if len(res) == 0 {
+			return nil, errs.ErrEligibleRow
+		}
+		return res[0], s.trackEntities(res[0])
+	}
+
+	res, err := findOne[T](ctx, &OrmContext{
 		Typ:     ScTypSELECT,
 		Model:   s.model,
 		Builder: s,
 	}, s.orm)
+	if err != nil {
+		return nil, err
+	}
+	return res, s.trackEntities(res)
 }
 
 func (s *Selector[T]) FindMulti(ctx context.Context) ([]*T, error) {
+	s.scopes = nil
+	s.tableScope = nil
+	s.schema = s.orm.getCore().schemaOf(ctx)
 	if err := s.initModel(); err != nil {
 		return nil, err
 	}
 
-	return findMulti[T](ctx, &OrmContext{
-		Typ:     ScTypSELECT,
-		Model:   s.model,
-		Builder: s,
-	}, s.orm)
+	var res []*T
+	var err error
+	if sdb, ok := s.orm.(*ShardingDB); ok {
+		res, err = s.findSharding(ctx, sdb)
+	} else {
+		res, err = findMulti[T](ctx, &OrmContext{
+			Typ:     ScTypSELECT,
+			Model:   s.model,
+			Builder: s,
+		}, s.orm)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return res, s.trackEntities(res...)
 }
 
 func (s *Selector[T]) initModel() error {
+	if s.model != nil {
+		return nil
+	}
+
 	var err error
 	s.model, err = s.orm.getCore().registry.GetModel(new(T))
 	return err
@@ -133,6 +178,7 @@ func (s *Selector[T]) ToSubQuery() (SubQuery, error) {
 
 	return SubQuery{
 		statement: statement,
+		query:     s,
 	}, nil
 }
 
@@ -141,7 +187,7 @@ func (s *Selector[T]) AsSubQuery(alias string) (SubQuery, error) {
 	if tableRef == nil {
 		tableRef = TableOf(new(T))
 	}
-	
+
 	statement, err := s.Build()
 	if err != nil {
 		return SubQuery{}, err
@@ -150,6 +196,7 @@ func (s *Selector[T]) AsSubQuery(alias string) (SubQuery, error) {
 	return SubQuery{
 		statement: statement,
 		alias:     alias,
+		query:     s,
 	}, nil
 }
 
@@ -161,6 +208,9 @@ func (s *Selector[T]) Build() (*Statement, error) {
 		}
 	}
 
+	s.reset()
+	s.tableScopes = nil
+
 	s.sqlBuffer.WriteString("SELECT ")
 	if err = s.buildSelectables(); err != nil {
 		return nil, err
@@ -171,8 +221,9 @@ func (s *Selector[T]) Build() (*Statement, error) {
 		return nil, err
 	}
 
-	if len(s.where) > 0 {
-		if err = s.buildConditions(s.where); err != nil {
+	scopes := append(slices.Clip(s.scopes), s.tableScopes...)
+	if where := scopedWhere(s.where, scopes); len(where) > 0 {
+		if err = s.buildConditions(where); err != nil {
 			return nil, err
 		}
 	}
@@ -207,38 +258,47 @@ func (s *Selector[T]) Build() (*Statement, error) {
 		}
 	}
 
-	if s.limit > 0 {
-		s.sqlBuffer.WriteString(" LIMIT ")
-		s.sqlBuffer.WriteString(strconv.FormatInt(s.limit, 10))
-	}
-
-	if s.offset > 0 {
-		s.sqlBuffer.WriteString(" OFFSET ")
-		s.sqlBuffer.WriteString(strconv.FormatInt(s.offset, 10))
-	}
+	s.dialect.paginate(&s.builder, s.limit, s.offset, len(s.orderBy) > 0)
 
-	s.sqlBuffer.WriteByte(';')
+	s.writeTerminator()
 	return &Statement{
 		SQL:  s.sqlBuffer.String(),
 		Args: s.args,
 	}, nil
 }
 
+// buildIn build the statement on a copy of selector with tables in the schema and restricted by the table scope.
+func (s *Selector[T]) buildIn(schema string, scope TableScope) (*Statement, error) {
+	query := *s
+	query.builder = newBuilder(s.orm)
+	query.model = s.model
+	query.table = s.table
+	query.schema = schema
+	query.tableScope = scope
+	return query.Build()
+}
+
 func (s *Selector[T]) buildTable(tableRef TableRef) error {
 	switch refTyp := tableRef.(type) {
 	case nil:
 		s.writeTable()
+
+		// the column is not qualified as the model is the only table
+		return s.scopeModelTable(s.model, func(fieldName string) Expr { return Col(fieldName) })
 	case Table:
 		m, err := s.orm.getCore().registry.GetModel(refTyp.entity)
 		if err != nil {
 			return err
 		}
-		s.writeWithQuote(m.TableName)
+		s.writeModelTable(m, "")
 
 		if tableAlias := tableRef.tableAlias(); tableAlias != "" {
-			s.sqlBuffer.WriteString(" AS ")
-			s.writeWithQuote(tableAlias)
+			s.writeTableAlias(tableAlias)
 		}
+
+		return s.scopeModelTable(m, func(fieldName string) Expr {
+			return tableColumn{table: refTyp, fieldName: fieldName}
+		})
 	case Join:
 		return s.buildJoin(refTyp)
 	case SubQuery:
@@ -247,22 +307,62 @@ func (s *Selector[T]) buildTable(tableRef TableRef) error {
 	return nil
 }
 
+// scopeModelTable collect the predicates filtering deleted rows of the table of model
+// and restricting them by the table scope, col returns the column of field qualified by the table.
+func (s *Selector[T]) scopeModelTable(m *model.Model, col func(fieldName string) Expr) error {
+	if pd, ok := s.softDeleteScope(m, col(fieldNameOf(m.SoftDelete))); ok {
+		s.tableScopes = append(s.tableScopes, pd)
+	}
+
+	pd, ok, err := s.scopeTable(m, col)
+	if ok {
+		s.tableScopes = append(s.tableScopes, pd)
+	}
+	return err
+}
+
 func (s *Selector[T]) buildJoin(join Join) error {
-	if err := s.buildTable(join.left); err != nil {
+	// scopes of the table keeping all rows of outer join are filtered in WHERE,
+	// scopes of the other side are filtered in ON rather than WHERE to keep the outer join,
+	// or in sub query if joined by USING which has no ON. Rows of inner join are filtered in WHERE equally.
+	tableScopes := len(s.tableScopes)
+	if left, ok := join.left.(Table); ok && len(join.using) > 0 && join.typ == JoinTypeRight {
+		if err := s.buildFilteredTable(left); err != nil {
+			return err
+		}
+	} else if err := s.buildTable(join.left); err != nil {
 		return err
 	}
 
+	leftScopes := len(s.tableScopes)
+	if len(join.using) > 0 && join.typ == JoinTypeRight && leftScopes > tableScopes {
+		return errs.ErrUnsupportedJoinScope(join.typ.String())
+	}
+
 	s.sqlBuffer.WriteByte(' ')
 	s.sqlBuffer.WriteString(join.typ.String())
 	s.sqlBuffer.WriteByte(' ')
 
-	if err := s.buildTable(join.right); err != nil {
+	if right, ok := join.right.(Table); ok && len(join.using) > 0 && join.typ == JoinTypeLeft {
+		if err := s.buildFilteredTable(right); err != nil {
+			return err
+		}
+	} else if err := s.buildTable(join.right); err != nil {
 		return err
 	}
 
 	if len(join.on) > 0 {
+		on := slices.Clip(join.on)
+		if join.typ == JoinTypeRight {
+			on = append(on, s.tableScopes[tableScopes:leftScopes]...)
+			s.tableScopes = slices.Delete(s.tableScopes, tableScopes, leftScopes)
+		} else {
+			on = append(on, s.tableScopes[leftScopes:]...)
+			s.tableScopes = s.tableScopes[:leftScopes]
+		}
+
 		s.sqlBuffer.WriteString(" ON ")
-		for i, pd := range join.on {
+		for i, pd := range on {
 			if i > 0 {
 				s.sqlBuffer.WriteString(" AND ")
 			}
@@ -290,6 +390,49 @@ func (s *Selector[T]) buildJoin(join Join) error {
 	return nil
 }
 
+// buildFilteredTable build the table filtered by its table scopes in sub query, like "(SELECT * FROM t WHERE ...) AS t",
+// used by the table outer joined by "USING" which has no "ON" to filter the rows in without breaking the outer join.
+func (s *Selector[T]) buildFilteredTable(t Table) error {
+	m, err := s.orm.getCore().registry.GetModel(t.entity)
+	if err != nil {
+		return err
+	}
+
+	// the alias of table is out of the scope of sub query, columns are qualified by the table name
+	tableScopes := len(s.tableScopes)
+	if err = s.scopeModelTable(m, func(fieldName string) Expr {
+		return tableColumn{table: TableOf(t.entity), fieldName: fieldName}
+	}); err != nil {
+		return err
+	}
+
+	scopes := slices.Clone(s.tableScopes[tableScopes:])
+	s.tableScopes = s.tableScopes[:tableScopes]
+	if len(scopes) == 0 {
+		return s.buildTable(t)
+	}
+
+	s.sqlBuffer.WriteString("(SELECT * FROM ")
+	s.writeModelTable(m, "")
+	s.sqlBuffer.WriteString(" WHERE ")
+	for i, pd := range scopes {
+		if i > 0 {
+			s.sqlBuffer.WriteString(" AND ")
+		}
+		if err = s.buildExpr(pd); err != nil {
+			return err
+		}
+	}
+	s.sqlBuffer.WriteByte(')')
+
+	alias := t.alias
+	if alias == "" {
+		alias = m.TableName
+	}
+	s.writeTableAlias(alias)
+	return nil
+}
+
 func (s *Selector[T]) buildSelectables() error {
 	if len(s.selectables) == 0 {
 		s.sqlBuffer.WriteByte('*')
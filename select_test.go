@@ -339,6 +339,95 @@ type thirdModel struct {
 	Id uint64
 }
 
+func TestSelector_Paginate(t *testing.T) {
+	mysqlDB, err := OpenDB(&sql.DB{}, MySQLDialect)
+	require.NoError(t, err)
+	sqliteDB, err := OpenDB(&sql.DB{}, SQLiteDialect)
+	require.NoError(t, err)
+	sqlServerDB, err := OpenDB(&sql.DB{}, SQLServerDialect)
+	require.NoError(t, err)
+	oracleDB, err := OpenDB(&sql.DB{}, OracleDialect)
+	require.NoError(t, err)
+
+	tcs := []struct {
+		name     string
+		selector *Selector[selectTestModel]
+		wantRes  *Statement
+	}{
+		{
+			name:     "sqlite with limit",
+			selector: NewSelector[selectTestModel](sqliteDB).Limit(10),
+			wantRes: &Statement{
+				SQL: `SELECT * FROM "select_test_model" LIMIT 10;`,
+			},
+		}, {
+			name:     "sqlite with offset",
+			selector: NewSelector[selectTestModel](sqliteDB).Offset(10),
+			wantRes: &Statement{
+				SQL: `SELECT * FROM "select_test_model" LIMIT -1 OFFSET 10;`,
+			},
+		}, {
+			name:     "sqlite with limit and offset",
+			selector: NewSelector[selectTestModel](sqliteDB).Where(Col("Id").Gt(1)).Limit(5).Offset(10),
+			wantRes: &Statement{
+				SQL:  `SELECT * FROM "select_test_model" WHERE "id" > ? LIMIT 5 OFFSET 10;`,
+				Args: []any{1},
+			},
+		}, {
+			name:     "mysql with limit and offset",
+			selector: NewSelector[selectTestModel](mysqlDB).Limit(5).Offset(10),
+			wantRes: &Statement{
+				SQL: "SELECT * FROM `select_test_model` LIMIT 5 OFFSET 10;",
+			},
+		}, {
+			name:     "sql server with limit",
+			selector: NewSelector[selectTestModel](sqlServerDB).Where(Col("Id").Gt(1)).Limit(5),
+			wantRes: &Statement{
+				SQL:  "SELECT * FROM [select_test_model] WHERE [id] > @p1 ORDER BY (SELECT NULL) OFFSET 0 ROWS FETCH NEXT 5 ROWS ONLY;",
+				Args: []any{1},
+			},
+		}, {
+			name:     "sql server with order by, limit and offset",
+			selector: NewSelector[selectTestModel](sqlServerDB).OrderBy(Desc("Id")).Limit(5).Offset(10),
+			wantRes: &Statement{
+				SQL: "SELECT * FROM [select_test_model] ORDER BY [id] DESC OFFSET 10 ROWS FETCH NEXT 5 ROWS ONLY;",
+			},
+		}, {
+			name:     "sql server with offset",
+			selector: NewSelector[selectTestModel](sqlServerDB).OrderBy(Asc("Id")).Offset(10),
+			wantRes: &Statement{
+				SQL: "SELECT * FROM [select_test_model] ORDER BY [id] ASC OFFSET 10 ROWS;",
+			},
+		}, {
+			name:     "oracle with limit and offset",
+			selector: NewSelector[selectTestModel](oracleDB).Where(Col("Id").Gt(1), Col("Age").Lt(18)).Limit(5).Offset(10),
+			wantRes: &Statement{
+				SQL:  `SELECT * FROM "select_test_model" WHERE ("id" > :1) AND ("age" < :2) OFFSET 10 ROWS FETCH NEXT 5 ROWS ONLY`,
+				Args: []any{1, 18},
+			},
+		}, {
+			name: "oracle with table alias",
+			selector: func() *Selector[selectTestModel] {
+				subQuery, err := NewSelector[selectTestModel](oracleDB).AsSubQuery("s")
+				require.NoError(t, err)
+				return NewSelector[selectTestModel](oracleDB).From(subQuery).Where(subQuery.Col("Id").Eq(1)).Limit(1)
+			}(),
+			wantRes: &Statement{
+				SQL:  `SELECT * FROM (SELECT * FROM "select_test_model") "s" WHERE "s"."id" = :1 FETCH NEXT 1 ROWS ONLY`,
+				Args: []any{1},
+			},
+		},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			statement, err := tc.selector.Build()
+			require.NoError(t, err)
+			assert.Equal(t, tc.wantRes, statement)
+		})
+	}
+}
+
 func TestSelector_Join(t *testing.T) {
 	db, err := OpenDB(&sql.DB{}, PostgresDialect)
 	require.NoError(t, err)
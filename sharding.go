@@ -0,0 +1,563 @@
+package easyorm
+
+import (
+	"cmp"
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"reflect"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/JrMarcco/easy-orm/internal/errs"
+	"github.com/JrMarcco/easy-orm/internal/value"
+	"github.com/JrMarcco/easy-orm/model"
+)
+
+var _ orm = (*ShardingDB)(nil)
+
+// Dst the destination of a shard.
+type Dst = model.Dst
+
+// ShardingAlgorithm decide the shard of sharded model by the value of sharding key,
+// which is set on the model registered by model.WithShardingOpt.
+type ShardingAlgorithm = model.ShardingAlgorithm
+
+// ShardingDB route statements of sharded models to the database and table of shards.
+//
+// Statements are routed by the equality and IN predicates on sharding key in WHERE, or the inserted rows.
+// Selector without sharding key is fanned out to all shards, and the results are merged in order of ORDER BY.
+// Statements executed on multiple shards are not atomic.
+type ShardingDB struct {
+	*core
+
+	dbs map[string]*DB
+}
+
+func (sdb *ShardingDB) getCore() *core {
+	return sdb.core
+}
+
+func (sdb *ShardingDB) queryContext(_ context.Context, _ string, _ ...any) (*sql.Rows, error) {
+	return nil, errs.ErrShardingNotRouted
+}
+
+func (sdb *ShardingDB) readContext(_ context.Context, _ string, _ ...any) (*sql.Rows, error) {
+	return nil, errs.ErrShardingNotRouted
+}
+
+func (sdb *ShardingDB) execContext(_ context.Context, _ string, _ ...any) (sql.Result, error) {
+	return nil, errs.ErrShardingNotRouted
+}
+
+// Registry returns the model registry of ShardingDB, sharded models are registered on it with model.WithShardingOpt.
+func (sdb *ShardingDB) Registry() model.Registry {
+	return sdb.registry
+}
+
+func (sdb *ShardingDB) algorithmOf(m *model.Model) (ShardingAlgorithm, error) {
+	if m.Sharding != nil {
+		return m.Sharding, nil
+	}
+	return nil, errs.ErrNoShardingAlgorithm(m.TableName)
+}
+
+func (sdb *ShardingDB) dbOf(dst Dst) (*DB, error) {
+	if db, ok := sdb.dbs[dst.DB]; ok {
+		return db, nil
+	}
+	return nil, errs.ErrShardDBNotFound(dst.DB)
+}
+
+// route returns the destinations of statement by the WHERE conditions.
+func (sdb *ShardingDB) route(m *model.Model, where []Condition) ([]Dst, error) {
+	algorithm, err := sdb.algorithmOf(m)
+	if err != nil {
+		return nil, err
+	}
+
+	var expr Expr
+	for _, c := range where {
+		if expr == nil {
+			expr = c.expr
+			continue
+		}
+		expr = Predicate{left: expr, op: opAnd, right: c.expr}
+	}
+
+	dsts, ok, err := shardsOf(algorithm, expr)
+	if err != nil {
+		return nil, err
+	}
+
+	if !ok {
+		return algorithm.Broadcast(), nil
+	}
+	return dsts, nil
+}
+
+// shardsOf returns the destinations matched by the expression, ok is false if it does not restrict sharding key.
+func shardsOf(algorithm ShardingAlgorithm, expr Expr) (dsts []Dst, ok bool, err error) {
+	pd, isPd := expr.(Predicate)
+	if !isPd {
+		return nil, false, nil
+	}
+
+	switch pd.op {
+	case opAnd, opOr:
+		left, leftOk, err := shardsOf(algorithm, pd.left)
+		if err != nil {
+			return nil, false, err
+		}
+
+		right, rightOk, err := shardsOf(algorithm, pd.right)
+		if err != nil {
+			return nil, false, err
+		}
+
+		if pd.op == opOr {
+			if leftOk && rightOk {
+				return appendDst(left, right...), true, nil
+			}
+			return nil, false, nil
+		}
+
+		switch {
+		case leftOk && rightOk:
+			res := make([]Dst, 0, len(left))
+			for _, dst := range left {
+				if slices.Contains(right, dst) {
+					res = append(res, dst)
+				}
+			}
+			return res, true, nil
+		case leftOk:
+			return left, true, nil
+		case rightOk:
+			return right, true, nil
+		}
+		return nil, false, nil
+	case opEq, opIn:
+		col, isCol := pd.left.(Column)
+		if !isCol || col.tableRef != nil || col.fieldName != algorithm.Key() {
+			return nil, false, nil
+		}
+
+		val, isVal := pd.right.(columnValue)
+		if !isVal {
+			return nil, false, nil
+		}
+
+		vals := []any{val.value}
+		if pd.op == opIn {
+			vals, _ = val.value.([]any)
+		}
+
+		dsts = make([]Dst, 0, len(vals))
+		for _, v := range vals {
+			dst, err := algorithm.Shard(v)
+			if err != nil {
+				return nil, false, err
+			}
+			dsts = appendDst(dsts, dst)
+		}
+		return dsts, true, nil
+	}
+	return nil, false, nil
+}
+
+// appendDst append destinations not in dsts.
+func appendDst(dsts []Dst, elems ...Dst) []Dst {
+	for _, dst := range elems {
+		if !slices.Contains(dsts, dst) {
+			dsts = append(dsts, dst)
+		}
+	}
+	return dsts
+}
+
+// findSharding find on the shards the selector routed to,
+// results of shards are merged in order of ORDER BY, then paginated.
+func (s *Selector[T]) findSharding(ctx context.Context, sdb *ShardingDB) ([]*T, error) {
+	if s.tableRef != nil {
+		return nil, errs.ErrUnsupportedSharding("FROM")
+	}
+
+	dsts, err := sdb.route(s.model, s.where)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(dsts) == 1 {
+		shard, err := s.shard(sdb, dsts[0])
+		if err != nil {
+			return nil, err
+		}
+		return shard.FindMulti(ctx)
+	}
+
+	if len(s.groupBy) > 0 {
+		return nil, errs.ErrUnsupportedSharding("GROUP BY")
+	}
+	for _, sel := range s.selectables {
+		if _, ok := sel.(Aggregate); ok {
+			return nil, errs.ErrUnsupportedSharding("aggregate")
+		}
+	}
+
+	shards := make([]*Selector[T], 0, len(dsts))
+	for _, dst := range dsts {
+		shard, err := s.shard(sdb, dst)
+		if err != nil {
+			return nil, err
+		}
+
+		// shards return rows up to the end of page, pagination is applied after merged.
+		if s.limit > 0 {
+			shard.limit = s.limit + max(s.offset, 0)
+		}
+		shard.offset = -1
+		shards = append(shards, shard)
+	}
+
+	results := make([][]*T, len(shards))
+	shardErrs := make([]error, len(shards))
+
+	var wg sync.WaitGroup
+	for idx, shard := range shards {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			results[idx], shardErrs[idx] = shard.FindMulti(ctx)
+		}()
+	}
+	wg.Wait()
+
+	if err = errors.Join(shardErrs...); err != nil {
+		return nil, err
+	}
+
+	res := slices.Concat(results...)
+	if len(s.orderBy) > 0 {
+		if err = s.sortSharding(sdb.resolverCreator, sdb.dialect.nullsFirst(), res); err != nil {
+			return nil, err
+		}
+	}
+
+	if s.offset > 0 {
+		res = res[min(s.offset, int64(len(res))):]
+	}
+	if s.limit > 0 && int64(len(res)) > s.limit {
+		res = res[:s.limit]
+	}
+	return res, nil
+}
+
+// shard returns a copy of selector executed on the destination.
+func (s *Selector[T]) shard(sdb *ShardingDB, dst Dst) (*Selector[T], error) {
+	db, err := sdb.dbOf(dst)
+	if err != nil {
+		return nil, err
+	}
+
+	shard := *s
+	shard.builder = newBuilder(db)
+	shard.model = s.model
+	shard.table = dst.Table
+	shard.orm = db
+	// entities are tracked by the sharding db
+	shard.track = false
+	return &shard, nil
+}
+
+// sortSharding sort the merged results in order of ORDER BY, NULL is sorted as the dialect does.
+func (s *Selector[T]) sortSharding(resolverCreator value.ResolverCreator, nullsFirst bool, res []*T) error {
+	var err error
+	slices.SortStableFunc(res, func(a, b *T) int {
+		if err != nil {
+			return 0
+		}
+
+		left, right := resolverCreator(s.model, a), resolverCreator(s.model, b)
+		for _, orderBy := range s.orderBy {
+			var lv, rv any
+			if lv, err = left.ReadColumn(orderBy.fieldName); err != nil {
+				return 0
+			}
+			if rv, err = right.ReadColumn(orderBy.fieldName); err != nil {
+				return 0
+			}
+
+			var c int
+			if c, err = compareValue(lv, rv, nullsFirst); err != nil {
+				return 0
+			}
+
+			if c != 0 {
+				if orderBy.typ == orderDesc {
+					return -c
+				}
+				return c
+			}
+		}
+		return 0
+	})
+	return err
+}
+
+// compareValue compare values of the same field,
+// nil is less than any other value if nullsFirst, otherwise greater than any other value.
+func compareValue(left, right any, nullsFirst bool) (int, error) {
+	var err error
+	if left, err = driverValueOf(left); err != nil {
+		return 0, err
+	}
+	if right, err = driverValueOf(right); err != nil {
+		return 0, err
+	}
+
+	switch {
+	case left == nil && right == nil:
+		return 0, nil
+	case left == nil && nullsFirst, right == nil && !nullsFirst:
+		return -1, nil
+	case left == nil, right == nil:
+		return 1, nil
+	}
+
+	if lt, ok := left.(time.Time); ok {
+		if rt, ok := right.(time.Time); ok {
+			return lt.Compare(rt), nil
+		}
+	}
+
+	lv, rv := reflect.ValueOf(left), reflect.ValueOf(right)
+	if lv.Kind() == rv.Kind() {
+		switch lv.Kind() {
+		case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+			return cmp.Compare(lv.Int(), rv.Int()), nil
+		case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+			return cmp.Compare(lv.Uint(), rv.Uint()), nil
+		case reflect.Float32, reflect.Float64:
+			return cmp.Compare(lv.Float(), rv.Float()), nil
+		case reflect.String:
+			return cmp.Compare(lv.String(), rv.String()), nil
+		case reflect.Bool:
+			return cmp.Compare(boolToInt(lv.Bool()), boolToInt(rv.Bool())), nil
+		default:
+		}
+	}
+	return 0, errs.ErrUnsupportedSharding(fmt.Sprintf("ORDER BY on %T", left))
+}
+
+// driverValueOf returns the value of driver.Valuer or the value pointer pointed to, nil for nil pointer.
+func driverValueOf(val any) (any, error) {
+	if valuer, ok := val.(driver.Valuer); ok {
+		return valuer.Value()
+	}
+
+	rv := reflect.ValueOf(val)
+	for rv.Kind() == reflect.Pointer {
+		if rv.IsNil() {
+			return nil, nil
+		}
+		rv = rv.Elem()
+	}
+
+	if !rv.IsValid() {
+		return nil, nil
+	}
+	return rv.Interface(), nil
+}
+
+func boolToInt(b bool) int {
+	if b {
+		return 1
+	}
+	return 0
+}
+
+// execSharding split rows by the shards of their sharding key, and insert them into each shard.
+func (i *Inserter[T]) execSharding(ctx context.Context, sdb *ShardingDB) Result {
+	if i.query != nil {
+		return Result{err: errs.ErrUnsupportedSharding("INSERT ... SELECT")}
+	}
+
+	if len(i.rows) == 0 {
+		return Result{err: errs.ErrInsertWithoutRows}
+	}
+
+	algorithm, err := sdb.algorithmOf(i.model)
+	if err != nil {
+		return Result{err: err}
+	}
+
+	dsts := make([]Dst, 0, 1)
+	rows := make(map[Dst][]*T, 1)
+	for _, row := range i.rows {
+		val, err := sdb.resolverCreator(i.model, row).ReadColumn(algorithm.Key())
+		if err != nil {
+			return Result{err: err}
+		}
+
+		dst, err := algorithm.Shard(val)
+		if err != nil {
+			return Result{err: err}
+		}
+
+		if _, ok := rows[dst]; !ok {
+			dsts = append(dsts, dst)
+		}
+		rows[dst] = append(rows[dst], row)
+	}
+
+	results := make(batchResult, 0, len(dsts))
+	for _, dst := range dsts {
+		db, err := sdb.dbOf(dst)
+		if err != nil {
+			return Result{err: err}
+		}
+
+		shard := &Inserter[T]{
+			builder:   newBuilder(db),
+			orm:       db,
+			rows:      rows[dst],
+			fields:    i.fields,
+			returning: i.returning,
+			batchSize: i.batchSize,
+			conflict:  i.conflict,
+		}
+		shard.model = i.model
+		shard.table = dst.Table
+
+		res := shard.execute(ctx)
+		if res.Err() != nil {
+			return res
+		}
+		results = append(results, res.res)
+	}
+	return Result{res: results}
+}
+
+// execSharding delete on the shards the deleter routed to.
+func (d *Deleter[T]) execSharding(ctx context.Context, sdb *ShardingDB) Result {
+	dsts, err := sdb.route(d.model, d.where)
+	if err != nil {
+		return Result{err: err}
+	}
+
+	results := make(batchResult, 0, len(dsts))
+	for _, dst := range dsts {
+		db, err := sdb.dbOf(dst)
+		if err != nil {
+			return Result{err: err}
+		}
+
+		shard := &Deleter[T]{
+			builder:  newBuilder(db),
+			orm:      db,
+			entities: d.entities,
+			where:    d.where,
+			unscoped: d.unscoped,
+		}
+		shard.model = d.model
+		shard.table = dst.Table
+
+		res := shard.execute(ctx)
+		if res.Err() != nil {
+			return res
+		}
+		results = append(results, res.res)
+	}
+	return Result{res: results}
+}
+
+// execSharding executes the update on the shards routed by WHERE,
+// the version of entity is checked once on the results of all shards.
+func (u *Updater[T]) execSharding(ctx context.Context, sdb *ShardingDB) Result {
+	dsts, err := sdb.route(u.model, u.where)
+	if err != nil {
+		return Result{err: err}
+	}
+
+	results := make(batchResult, 0, len(dsts))
+	for _, dst := range dsts {
+		db, err := sdb.dbOf(dst)
+		if err != nil {
+			return Result{err: err}
+		}
+
+		shard := &Updater[T]{
+			builder:  newBuilder(db),
+			orm:      db,
+			entity:   u.entity,
+			assigns:  u.assigns,
+			where:    u.where,
+			unscoped: u.unscoped,
+			now:      u.now,
+		}
+		shard.model = u.model
+		shard.table = dst.Table
+		shard.schema = u.schema
+
+		res := exec(ctx, &OrmContext{
+			Typ:     ScTypUPDATE,
+			Model:   shard.model,
+			Builder: shard,
+		}, db)
+		if res.Err() != nil {
+			return res
+		}
+		results = append(results, res.res)
+	}
+	return Result{res: results}
+}
+
+func typeOf[T any]() reflect.Type {
+	return reflect.TypeOf((*T)(nil)).Elem()
+}
+
+type ShardingDBOpt func(*ShardingDB)
+
+func ShardingDBWithRegistry(registry model.Registry) ShardingDBOpt {
+	return func(sdb *ShardingDB) {
+		sdb.registry = registry
+	}
+}
+
+func ShardingDBWithValueResolver(resolverCreator value.ResolverCreator) ShardingDBOpt {
+	return func(sdb *ShardingDB) {
+		sdb.resolverCreator = resolverCreator
+	}
+}
+
+// ShardingDBWithClock set the clock filling auto time fields, default is time.Now.
+func ShardingDBWithClock(clock func() time.Time) ShardingDBOpt {
+	return func(sdb *ShardingDB) {
+		sdb.clock = clock
+	}
+}
+
+// OpenShardingDB open a sharding db on databases keyed by the name used in Dst.
+// Statements routed to a database are executed with its own middleware chain and transaction in context.
+// Sharded models are registered on Registry with model.WithShardingOpt before used, like:
+//
+//	sdb.Registry().RegisterModel(&Order{}, model.WithShardingOpt(&ModSharding{ShardingKey: "UserId", ...}))
+func OpenShardingDB(dialect Dialect, dbs map[string]*DB, opts ...ShardingDBOpt) (*ShardingDB, error) {
+	sdb := &ShardingDB{
+		core: &core{
+			dialect:         dialect,
+			registry:        model.NewRegistry(),
+			resolverCreator: value.NewUnsafeResolver,
+		},
+		dbs: dbs,
+	}
+
+	for _, opt := range opts {
+		opt(sdb)
+	}
+	return sdb, nil
+}
@@ -0,0 +1,160 @@
+package easyorm
+
+import (
+	"fmt"
+	"hash/fnv"
+	"math"
+	"reflect"
+
+	"github.com/JrMarcco/easy-orm/internal/errs"
+)
+
+var (
+	_ ShardingAlgorithm = (*ModSharding)(nil)
+	_ ShardingAlgorithm = (*RangeSharding)(nil)
+)
+
+// ModSharding shard by the value of sharding key modulo the number of tables,
+// tables are spread over databases in contiguous blocks,
+// e.g. with 4 databases and 64 tables, table 0 ~ 15 are in database 0, table 16 ~ 31 are in database 1 and so on.
+type ModSharding struct {
+	// ShardingKey the field name of sharding key.
+	ShardingKey string
+
+	DBCount    int
+	TableCount int
+
+	// DBPattern and TablePattern format the index of database and table into name,
+	// e.g. "order_db_%d" and "order_%02d".
+	DBPattern    string
+	TablePattern string
+
+	// Hash hash the value of sharding key before modulo, e.g. FNVHash for string keys.
+	// The value of integer type is used directly if nil.
+	Hash func(val any) (uint64, error)
+}
+
+func (m *ModSharding) Key() string {
+	return m.ShardingKey
+}
+
+// Validate check the number of tables and databases, called when the model is registered by model.WithShardingOpt.
+func (m *ModSharding) Validate() error {
+	if m.TableCount <= 0 {
+		return errs.ErrInvalidShardingAlgorithm(fmt.Sprintf("table count %d must be positive", m.TableCount))
+	}
+	if m.DBCount > m.TableCount {
+		return errs.ErrInvalidShardingAlgorithm(
+			fmt.Sprintf("db count %d must not exceed table count %d", m.DBCount, m.TableCount),
+		)
+	}
+	return nil
+}
+
+func (m *ModSharding) Shard(val any) (Dst, error) {
+	idx, err := m.index(val)
+	if err != nil {
+		return Dst{}, err
+	}
+	return m.dstOf(idx), nil
+}
+
+func (m *ModSharding) Broadcast() []Dst {
+	dsts := make([]Dst, 0, m.TableCount)
+	for idx := 0; idx < m.TableCount; idx++ {
+		dsts = append(dsts, m.dstOf(idx))
+	}
+	return dsts
+}
+
+func (m *ModSharding) index(val any) (int, error) {
+	if m.Hash != nil {
+		hash, err := m.Hash(val)
+		if err != nil {
+			return 0, err
+		}
+		return int(hash % uint64(m.TableCount)), nil
+	}
+
+	rv := reflect.Indirect(reflect.ValueOf(val))
+	switch rv.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		n := int64(m.TableCount)
+		return int((rv.Int()%n + n) % n), nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return int(rv.Uint() % uint64(m.TableCount)), nil
+	default:
+		return 0, errs.ErrInvalidShardingKey(val)
+	}
+}
+
+func (m *ModSharding) dstOf(idx int) Dst {
+	dbCount := max(m.DBCount, 1)
+	return Dst{
+		DB:    fmt.Sprintf(m.DBPattern, idx*dbCount/m.TableCount),
+		Table: fmt.Sprintf(m.TablePattern, idx),
+	}
+}
+
+// FNVHash hash the value of sharding key with FNV-1a, values other than string and []byte are hashed by fmt.Sprint.
+func FNVHash(val any) (uint64, error) {
+	h := fnv.New64a()
+	switch v := val.(type) {
+	case string:
+		_, _ = h.Write([]byte(v))
+	case []byte:
+		_, _ = h.Write(v)
+	default:
+		_, _ = h.Write([]byte(fmt.Sprint(v)))
+	}
+	return h.Sum64(), nil
+}
+
+// RangeSharding shard by the range the value of sharding key falls in.
+type RangeSharding struct {
+	// ShardingKey the field name of sharding key.
+	ShardingKey string
+	// Ranges the ranges in ascending order of Upper.
+	Ranges []ShardRange
+}
+
+// ShardRange values less than Upper and not less than the Upper of previous range belong to Dst.
+type ShardRange struct {
+	Upper int64
+	Dst   Dst
+}
+
+func (r *RangeSharding) Key() string {
+	return r.ShardingKey
+}
+
+func (r *RangeSharding) Shard(val any) (Dst, error) {
+	var key int64
+	rv := reflect.Indirect(reflect.ValueOf(val))
+	switch rv.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		key = rv.Int()
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		if rv.Uint() > math.MaxInt64 {
+			return Dst{}, errs.ErrNoShard(val)
+		}
+		key = int64(rv.Uint())
+	default:
+		return Dst{}, errs.ErrInvalidShardingKey(val)
+	}
+
+	for _, rg := range r.Ranges {
+		if key < rg.Upper {
+			return rg.Dst, nil
+		}
+	}
+	return Dst{}, errs.ErrNoShard(val)
+}
+
+func (r *RangeSharding) Broadcast() []Dst {
+	dsts := make([]Dst, 0, len(r.Ranges))
+	for _, rg := range r.Ranges {
+		dsts = appendDst(dsts, rg.Dst)
+	}
+	return dsts
+}
@@ -0,0 +1,416 @@
+package easyorm
+
+import (
+	"context"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/JrMarcco/easy-orm/internal/errs"
+	"github.com/JrMarcco/easy-orm/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type shardingTestModel struct {
+	Id     uint64
+	UserId int64
+	Amount int64
+}
+
+func shardingTestAlgorithm() *ModSharding {
+	return &ModSharding{
+		ShardingKey:  "UserId",
+		DBCount:      2,
+		TableCount:   4,
+		DBPattern:    "db_%d",
+		TablePattern: "order_%02d",
+	}
+}
+
+func TestModSharding(t *testing.T) {
+	tcs := []struct {
+		name      string
+		algorithm *ModSharding
+		val       any
+		wantDst   Dst
+		wantErr   error
+	}{
+		{
+			name:      "first db",
+			algorithm: shardingTestAlgorithm(),
+			val:       int64(5),
+			wantDst:   Dst{DB: "db_0", Table: "order_01"},
+		}, {
+			name:      "second db",
+			algorithm: shardingTestAlgorithm(),
+			val:       uint32(7),
+			wantDst:   Dst{DB: "db_1", Table: "order_03"},
+		}, {
+			name:      "negative",
+			algorithm: shardingTestAlgorithm(),
+			val:       -2,
+			wantDst:   Dst{DB: "db_1", Table: "order_02"},
+		}, {
+			name:      "invalid key",
+			algorithm: shardingTestAlgorithm(),
+			val:       "foo",
+			wantErr:   errs.ErrInvalidShardingKey("foo"),
+		}, {
+			name: "hash",
+			algorithm: &ModSharding{
+				ShardingKey:  "Name",
+				TableCount:   4,
+				DBPattern:    "db_%d",
+				TablePattern: "user_%d",
+				Hash: func(val any) (uint64, error) {
+					return uint64(len(val.(string))), nil
+				},
+			},
+			val:     "foo",
+			wantDst: Dst{DB: "db_0", Table: "user_3"},
+		},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			dst, err := tc.algorithm.Shard(tc.val)
+			assert.Equal(t, tc.wantErr, err)
+			if err != nil {
+				return
+			}
+			assert.Equal(t, tc.wantDst, dst)
+		})
+	}
+
+	assert.Equal(t, []Dst{
+		{DB: "db_0", Table: "order_00"},
+		{DB: "db_0", Table: "order_01"},
+		{DB: "db_1", Table: "order_02"},
+		{DB: "db_1", Table: "order_03"},
+	}, shardingTestAlgorithm().Broadcast())
+}
+
+func TestWithShardingOpt(t *testing.T) {
+	tcs := []struct {
+		name      string
+		algorithm *ModSharding
+		wantErr   error
+	}{
+		{
+			name:      "valid",
+			algorithm: shardingTestAlgorithm(),
+		}, {
+			name:      "zero table count",
+			algorithm: &ModSharding{ShardingKey: "UserId", DBCount: 2, DBPattern: "db_%d", TablePattern: "order_%02d"},
+			wantErr:   errs.ErrInvalidShardingAlgorithm("table count 0 must be positive"),
+		}, {
+			name:      "invalid key",
+			algorithm: &ModSharding{ShardingKey: "Invalid", TableCount: 4, DBPattern: "db_%d", TablePattern: "order_%02d"},
+			wantErr:   errs.ErrInvalidField("Invalid"),
+		}, {
+			name: "more dbs than tables",
+			algorithm: &ModSharding{
+				ShardingKey: "UserId", DBCount: 4, TableCount: 2, DBPattern: "db_%d", TablePattern: "order_%02d",
+			},
+			wantErr: errs.ErrInvalidShardingAlgorithm("db count 4 must not exceed table count 2"),
+		},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			sdb, err := OpenShardingDB(MySQLDialect, nil)
+			require.NoError(t, err)
+
+			_, err = sdb.Registry().RegisterModel(&shardingTestModel{}, model.WithShardingOpt(tc.algorithm))
+			assert.Equal(t, tc.wantErr, err)
+		})
+	}
+}
+
+func TestFNVHash(t *testing.T) {
+	foo, err := FNVHash("foo")
+	require.NoError(t, err)
+
+	bytes, err := FNVHash([]byte("foo"))
+	require.NoError(t, err)
+	assert.Equal(t, foo, bytes)
+
+	bar, err := FNVHash("bar")
+	require.NoError(t, err)
+	assert.NotEqual(t, foo, bar)
+}
+
+func TestRangeSharding(t *testing.T) {
+	algorithm := &RangeSharding{
+		ShardingKey: "Id",
+		Ranges: []ShardRange{
+			{Upper: 100, Dst: Dst{DB: "db_0", Table: "order_0"}},
+			{Upper: 200, Dst: Dst{DB: "db_0", Table: "order_1"}},
+			{Upper: 300, Dst: Dst{DB: "db_1", Table: "order_2"}},
+		},
+	}
+
+	tcs := []struct {
+		name    string
+		val     any
+		wantDst Dst
+		wantErr error
+	}{
+		{
+			name:    "lower bound",
+			val:     100,
+			wantDst: Dst{DB: "db_0", Table: "order_1"},
+		}, {
+			name:    "upper bound",
+			val:     uint64(299),
+			wantDst: Dst{DB: "db_1", Table: "order_2"},
+		}, {
+			name:    "out of range",
+			val:     300,
+			wantErr: errs.ErrNoShard(300),
+		}, {
+			name:    "invalid key",
+			val:     1.5,
+			wantErr: errs.ErrInvalidShardingKey(1.5),
+		},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			dst, err := algorithm.Shard(tc.val)
+			assert.Equal(t, tc.wantErr, err)
+			if err != nil {
+				return
+			}
+			assert.Equal(t, tc.wantDst, dst)
+		})
+	}
+}
+
+func TestShardingDB_route(t *testing.T) {
+	sdb, err := OpenShardingDB(MySQLDialect, nil)
+	require.NoError(t, err)
+
+	m, err := sdb.Registry().RegisterModel(&shardingTestModel{}, model.WithShardingOpt(shardingTestAlgorithm()))
+	require.NoError(t, err)
+
+	tcs := []struct {
+		name     string
+		where    []Predicate
+		wantDsts []Dst
+	}{
+		{
+			name:     "eq",
+			where:    []Predicate{Col("UserId").Eq(5)},
+			wantDsts: []Dst{{DB: "db_0", Table: "order_01"}},
+		}, {
+			name:  "in",
+			where: []Predicate{Col("UserId").In(1, 5, 2)},
+			wantDsts: []Dst{
+				{DB: "db_0", Table: "order_01"},
+				{DB: "db_1", Table: "order_02"},
+			},
+		}, {
+			name:     "and",
+			where:    []Predicate{Col("Id").Eq(1), Col("UserId").In(1, 2).And(Col("UserId").Eq(6))},
+			wantDsts: []Dst{{DB: "db_1", Table: "order_02"}},
+		}, {
+			name:  "or",
+			where: []Predicate{Col("UserId").Eq(1).Or(Col("UserId").Eq(3))},
+			wantDsts: []Dst{
+				{DB: "db_0", Table: "order_01"},
+				{DB: "db_1", Table: "order_03"},
+			},
+		}, {
+			name:     "or without key",
+			where:    []Predicate{Col("UserId").Eq(1).Or(Col("Id").Eq(3))},
+			wantDsts: shardingTestAlgorithm().Broadcast(),
+		}, {
+			name:     "not",
+			where:    []Predicate{Col("UserId").Eq(1).Not()},
+			wantDsts: shardingTestAlgorithm().Broadcast(),
+		}, {
+			name:     "range",
+			where:    []Predicate{Col("UserId").Gt(1)},
+			wantDsts: shardingTestAlgorithm().Broadcast(),
+		},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			dsts, err := sdb.route(m, []Condition{NewCondition(condTypWhere, tc.where)})
+			require.NoError(t, err)
+			assert.Equal(t, tc.wantDsts, dsts)
+		})
+	}
+
+	unsharded, err := sdb.Registry().GetModel(&txTestModel{})
+	require.NoError(t, err)
+	_, err = sdb.route(unsharded, nil)
+	assert.Equal(t, errs.ErrNoShardingAlgorithm("tx_test_model"), err)
+}
+
+func newShardingTestDB(t *testing.T) (*ShardingDB, sqlmock.Sqlmock, sqlmock.Sqlmock) {
+	dbs := make(map[string]*DB, 2)
+	mocks := make([]sqlmock.Sqlmock, 0, 2)
+	for _, name := range []string{"db_0", "db_1"} {
+		mockDB, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+		require.NoError(t, err)
+		t.Cleanup(func() {
+			_ = mockDB.Close()
+		})
+		mock.MatchExpectationsInOrder(false)
+
+		db, err := OpenDB(mockDB, MySQLDialect)
+		require.NoError(t, err)
+
+		dbs[name] = db
+		mocks = append(mocks, mock)
+	}
+
+	sdb, err := OpenShardingDB(MySQLDialect, dbs)
+	require.NoError(t, err)
+
+	_, err = sdb.Registry().RegisterModel(&shardingTestModel{}, model.WithShardingOpt(shardingTestAlgorithm()))
+	require.NoError(t, err)
+	return sdb, mocks[0], mocks[1]
+}
+
+func TestSelector_Sharding(t *testing.T) {
+	cols := []string{"id", "user_id", "amount"}
+
+	t.Run("single shard", func(t *testing.T) {
+		sdb, mock0, mock1 := newShardingTestDB(t)
+
+		mock1.ExpectQuery("SELECT * FROM `order_03` WHERE `user_id` = ? LIMIT 1;").
+			WithArgs(7).
+			WillReturnRows(sqlmock.NewRows(cols).AddRow(1, 7, 100))
+
+		res, err := NewSelector[shardingTestModel](sdb).Where(Col("UserId").Eq(7)).FindOne(context.Background())
+		require.NoError(t, err)
+		assert.Equal(t, &shardingTestModel{Id: 1, UserId: 7, Amount: 100}, res)
+
+		assert.NoError(t, mock0.ExpectationsWereMet())
+		assert.NoError(t, mock1.ExpectationsWereMet())
+	})
+
+	t.Run("fan out", func(t *testing.T) {
+		sdb, mock0, mock1 := newShardingTestDB(t)
+
+		for _, tc := range []struct {
+			mock  sqlmock.Sqlmock
+			table string
+			rows  *sqlmock.Rows
+		}{
+			{mock: mock0, table: "order_00", rows: sqlmock.NewRows(cols).AddRow(1, 4, 400).AddRow(2, 8, 100)},
+			{mock: mock0, table: "order_01", rows: sqlmock.NewRows(cols).AddRow(3, 1, 300)},
+			{mock: mock1, table: "order_02", rows: sqlmock.NewRows(cols)},
+			{mock: mock1, table: "order_03", rows: sqlmock.NewRows(cols).AddRow(4, 3, 200).AddRow(5, 7, 50)},
+		} {
+			tc.mock.ExpectQuery("SELECT * FROM `" + tc.table + "` WHERE `amount` > ? ORDER BY `amount` DESC LIMIT 3;").
+				WithArgs(10).
+				WillReturnRows(tc.rows)
+		}
+
+		res, err := NewSelector[shardingTestModel](sdb).
+			Where(Col("Amount").Gt(10)).
+			OrderBy(Desc("Amount")).
+			Limit(2).
+			Offset(1).
+			FindMulti(context.Background())
+		require.NoError(t, err)
+		assert.Equal(t, []*shardingTestModel{
+			{Id: 3, UserId: 1, Amount: 300},
+			{Id: 4, UserId: 3, Amount: 200},
+		}, res)
+
+		assert.NoError(t, mock0.ExpectationsWereMet())
+		assert.NoError(t, mock1.ExpectationsWereMet())
+	})
+
+	t.Run("unsupported", func(t *testing.T) {
+		sdb, _, _ := newShardingTestDB(t)
+
+		_, err := NewSelector[shardingTestModel](sdb).GroupBy(Col("UserId")).FindMulti(context.Background())
+		assert.Equal(t, errs.ErrUnsupportedSharding("GROUP BY"), err)
+	})
+}
+
+func TestSelector_sortSharding(t *testing.T) {
+	type nullsTestModel struct {
+		Id    uint64
+		Score *int64
+	}
+
+	one, two := int64(1), int64(2)
+	tcs := []struct {
+		name    string
+		dialect Dialect
+		orderBy OrderBy
+		wantIds []uint64
+	}{
+		{name: "mysql asc", dialect: MySQLDialect, orderBy: Asc("Score"), wantIds: []uint64{2, 3, 1}},
+		{name: "mysql desc", dialect: MySQLDialect, orderBy: Desc("Score"), wantIds: []uint64{1, 3, 2}},
+		{name: "postgres asc", dialect: PostgresDialect, orderBy: Asc("Score"), wantIds: []uint64{3, 1, 2}},
+		{name: "postgres desc", dialect: PostgresDialect, orderBy: Desc("Score"), wantIds: []uint64{2, 1, 3}},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			sdb, err := OpenShardingDB(tc.dialect, nil)
+			require.NoError(t, err)
+
+			s := NewSelector[nullsTestModel](sdb).OrderBy(tc.orderBy)
+			require.NoError(t, s.initModel())
+
+			res := []*nullsTestModel{{Id: 1, Score: &two}, {Id: 2}, {Id: 3, Score: &one}}
+			require.NoError(t, s.sortSharding(sdb.resolverCreator, sdb.dialect.nullsFirst(), res))
+
+			ids := make([]uint64, 0, len(res))
+			for _, r := range res {
+				ids = append(ids, r.Id)
+			}
+			assert.Equal(t, tc.wantIds, ids)
+		})
+	}
+}
+
+func TestInserter_Sharding(t *testing.T) {
+	sdb, mock0, mock1 := newShardingTestDB(t)
+
+	mock0.ExpectExec("INSERT INTO `order_01` (`id`, `user_id`, `amount`) VALUES (?, ?, ?), (?, ?, ?);").
+		WithArgs(uint64(1), int64(1), int64(100), uint64(3), int64(5), int64(300)).
+		WillReturnResult(sqlmock.NewResult(3, 2))
+	mock1.ExpectExec("INSERT INTO `order_02` (`id`, `user_id`, `amount`) VALUES (?, ?, ?);").
+		WithArgs(uint64(2), int64(2), int64(200)).
+		WillReturnResult(sqlmock.NewResult(2, 1))
+
+	res := NewInserter[shardingTestModel](sdb).Rows(
+		&shardingTestModel{Id: 1, UserId: 1, Amount: 100},
+		&shardingTestModel{Id: 2, UserId: 2, Amount: 200},
+		&shardingTestModel{Id: 3, UserId: 5, Amount: 300},
+	).Exec(context.Background())
+	require.NoError(t, res.Err())
+	assert.Equal(t, int64(3), res.RowsAffected())
+
+	assert.NoError(t, mock0.ExpectationsWereMet())
+	assert.NoError(t, mock1.ExpectationsWereMet())
+}
+
+func TestDeleter_Sharding(t *testing.T) {
+	sdb, mock0, mock1 := newShardingTestDB(t)
+
+	mock0.ExpectExec("DELETE FROM `order_01` WHERE `user_id` IN (?,?);").
+		WithArgs(1, 6).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock1.ExpectExec("DELETE FROM `order_02` WHERE `user_id` IN (?,?);").
+		WithArgs(1, 6).
+		WillReturnResult(sqlmock.NewResult(0, 2))
+
+	res := NewDeleter[shardingTestModel](sdb).Where(Col("UserId").In(1, 6)).Exec(context.Background())
+	require.NoError(t, res.Err())
+	assert.Equal(t, int64(3), res.RowsAffected())
+
+	assert.NoError(t, mock0.ExpectationsWereMet())
+	assert.NoError(t, mock1.ExpectationsWereMet())
+}
@@ -0,0 +1,149 @@
+package easyorm
+
+import (
+	"context"
+	"reflect"
+	"slices"
+	"time"
+
+	"github.com/JrMarcco/easy-orm/model"
+)
+
+// softDeleteMode how the rows marked deleted are filtered by Selector.
+type softDeleteMode uint8
+
+const (
+	// softDeleteExcluded excludes the deleted rows, the default mode.
+	softDeleteExcluded softDeleteMode = iota
+	// softDeleteIncluded includes the deleted rows.
+	softDeleteIncluded
+	// softDeleteOnly queries the deleted rows only.
+	softDeleteOnly
+)
+
+// Unscoped queries the rows marked deleted as well, same as WithDeleted.
+func (s *Selector[T]) Unscoped() *Selector[T] {
+	return s.WithDeleted()
+}
+
+// WithDeleted queries the rows marked deleted as well.
+func (s *Selector[T]) WithDeleted() *Selector[T] {
+	s.softDelete = softDeleteIncluded
+	return s
+}
+
+// OnlyDeleted queries the rows of model marked deleted only, rows of joined tables are still filtered.
+func (s *Selector[T]) OnlyDeleted() *Selector[T] {
+	s.softDelete = softDeleteOnly
+	return s
+}
+
+// softDeleteScope returns the predicate filtering the rows of table by the soft delete field,
+// col is the column of the soft delete field in statement.
+func (s *Selector[T]) softDeleteScope(m *model.Model, col Expr) (Predicate, bool) {
+	if m.SoftDelete == nil || s.softDelete == softDeleteIncluded {
+		return Predicate{}, false
+	}
+	return softDeleted(m.SoftDelete, col, s.softDelete == softDeleteOnly && m == s.model), true
+}
+
+// Unscoped deletes the rows physically even if the model is soft deleted.
+func (d *Deleter[T]) Unscoped() *Deleter[T] {
+	d.unscoped = true
+	return d
+}
+
+// ForceDelete deletes the rows physically even if the model is soft deleted.
+func (d *Deleter[T]) ForceDelete(ctx context.Context) Result {
+	return d.Unscoped().Exec(ctx)
+}
+
+// buildSoftDelete build the "UPDATE" statement marking the rows deleted,
+// rows already deleted are left untouched so that they keep the time deleted.
+// scopes are the scopes of middlewares restricting the rows.
+func (d *Deleter[T]) buildSoftDelete(scopes []Predicate) error {
+	field := d.model.SoftDelete
+
+	d.sqlBuffer.WriteString("UPDATE ")
+	d.writeTable()
+	d.sqlBuffer.WriteString(" SET ")
+	d.writeWithQuote(field.ColumnName)
+	d.sqlBuffer.WriteString(" = ")
+	d.addArgs(softDeleteMarker(field, d.orm.getCore().now()))
+	d.dialect.bindArg(&d.builder)
+
+	// keep the statement without WHERE unconditional, so that it is still caught as unsafe
+	if len(d.where) == 0 && len(scopes) == 0 {
+		return nil
+	}
+
+	scopes = append(slices.Clip(scopes), softDeleted(field, Col(field.FiledName), false))
+	return d.buildCondition(scopedWhere(d.where, scopes))
+}
+
+// softDeleted returns the predicate on the soft delete field whether the row is deleted:
+// deletion time is NULL, deleted flag is false or deletion unix time is 0 if the row is not deleted.
+func softDeleted(field *model.Field, col Expr, deleted bool) Predicate {
+	switch softDeleteKind(field) {
+	case reflect.Bool:
+		return Predicate{left: col, op: opEq, right: valueOf(deleted)}
+	case reflect.Int64:
+		if deleted {
+			return Predicate{left: col, op: opNe, right: valueOf(0)}
+		}
+		return Predicate{left: col, op: opEq, right: valueOf(0)}
+	default:
+		if deleted {
+			return Predicate{left: col, op: opIsNotNull}
+		}
+		return Predicate{left: col, op: opIsNull}
+	}
+}
+
+// softDeleteMarker returns the value marking the row deleted at now.
+func softDeleteMarker(field *model.Field, now time.Time) any {
+	switch softDeleteKind(field) {
+	case reflect.Bool:
+		return true
+	case reflect.Int64:
+		return now.Unix()
+	default:
+		return now
+	}
+}
+
+// softDeleteKind returns reflect.Bool for deleted flag, reflect.Int64 for deletion unix time
+// and reflect.Struct for deletion time.
+func softDeleteKind(field *model.Field) reflect.Kind {
+	typ := field.Typ
+	if typ.Kind() == reflect.Pointer {
+		typ = typ.Elem()
+	}
+
+	switch typ.Kind() {
+	case reflect.Bool:
+		return reflect.Bool
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return reflect.Int64
+	default:
+		return reflect.Struct
+	}
+}
+
+// tableColumn the column qualified by table, by the table name if the table has no alias.
+// Used where the column of joined tables is ambiguous.
+type tableColumn struct {
+	table     Table
+	fieldName string
+}
+
+func (t tableColumn) expr() {}
+
+// fieldNameOf returns the name of field, empty if the field is nil.
+func fieldNameOf(field *model.Field) string {
+	if field == nil {
+		return ""
+	}
+	return field.FiledName
+}
@@ -0,0 +1,315 @@
+package easyorm
+
+import (
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/JrMarcco/easy-orm/internal/errs"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type softDeleteUser struct {
+	Id        uint64
+	Name      string
+	DeletedAt *time.Time `orm:"soft_delete"`
+}
+
+type softDeleteOrder struct {
+	Id        uint64
+	UserId    uint64
+	IsDeleted bool `orm:"soft_delete"`
+}
+
+type softDeleteItem struct {
+	Id        uint64
+	OrderId   uint64
+	DeletedAt int64 `orm:"soft_delete"`
+}
+
+func TestSelector_SoftDelete(t *testing.T) {
+	db, err := OpenDB(&sql.DB{}, PostgresDialect)
+	require.NoError(t, err)
+
+	tcs := []struct {
+		name     string
+		selector StatementBuilder
+		wantRes  *Statement
+		wantErr  error
+	}{
+		{
+			name:     "basic",
+			selector: NewSelector[softDeleteUser](db),
+			wantRes: &Statement{
+				SQL: `SELECT * FROM "soft_delete_user" WHERE "deleted_at" IS NULL;`,
+			},
+		}, {
+			name:     "with where",
+			selector: NewSelector[softDeleteUser](db).Where(Col("Id").Eq(1)),
+			wantRes: &Statement{
+				SQL:  `SELECT * FROM "soft_delete_user" WHERE ("id" = $1) AND ("deleted_at" IS NULL);`,
+				Args: []any{1},
+			},
+		}, {
+			name:     "deleted flag",
+			selector: NewSelector[softDeleteOrder](db),
+			wantRes: &Statement{
+				SQL:  `SELECT * FROM "soft_delete_order" WHERE "is_deleted" = $1;`,
+				Args: []any{false},
+			},
+		}, {
+			name:     "deletion unix time",
+			selector: NewSelector[softDeleteItem](db),
+			wantRes: &Statement{
+				SQL:  `SELECT * FROM "soft_delete_item" WHERE "deleted_at" = $1;`,
+				Args: []any{0},
+			},
+		}, {
+			name:     "unscoped",
+			selector: NewSelector[softDeleteUser](db).Unscoped(),
+			wantRes: &Statement{
+				SQL: `SELECT * FROM "soft_delete_user";`,
+			},
+		}, {
+			name:     "with deleted",
+			selector: NewSelector[softDeleteOrder](db).WithDeleted(),
+			wantRes: &Statement{
+				SQL: `SELECT * FROM "soft_delete_order";`,
+			},
+		}, {
+			name:     "only deleted",
+			selector: NewSelector[softDeleteUser](db).OnlyDeleted().Where(Col("Id").Eq(1)),
+			wantRes: &Statement{
+				SQL:  `SELECT * FROM "soft_delete_user" WHERE ("id" = $1) AND ("deleted_at" IS NOT NULL);`,
+				Args: []any{1},
+			},
+		}, {
+			name:     "only deleted unix time",
+			selector: NewSelector[softDeleteItem](db).OnlyDeleted(),
+			wantRes: &Statement{
+				SQL:  `SELECT * FROM "soft_delete_item" WHERE "deleted_at" != $1;`,
+				Args: []any{0},
+			},
+		}, {
+			name:     "from table with alias",
+			selector: NewSelector[softDeleteUser](db).From(TableAs(&softDeleteUser{}, "u")),
+			wantRes: &Statement{
+				SQL: `SELECT * FROM "soft_delete_user" AS "u" WHERE "u"."deleted_at" IS NULL;`,
+			},
+		}, {
+			name: "join",
+			selector: func() StatementBuilder {
+				u := TableAs(&softDeleteUser{}, "u")
+				o := TableAs(&softDeleteOrder{}, "o")
+				return NewSelector[softDeleteUser](db).
+					From(u.LeftJoin(o).On(u.Col("Id").Eq(o.Col("UserId")))).
+					Where(u.Col("Name").Eq("Tom"))
+			}(),
+			wantRes: &Statement{
+				SQL: `SELECT * FROM "soft_delete_user" AS "u" LEFT JOIN "soft_delete_order" AS "o" ` +
+					`ON "u"."id" = "o"."user_id" AND "o"."is_deleted" = $1 ` +
+					`WHERE ("u"."name" = $2) AND ("u"."deleted_at" IS NULL);`,
+				Args: []any{false, "Tom"},
+			},
+		}, {
+			name: "join without alias",
+			selector: func() StatementBuilder {
+				o := TableOf(&softDeleteOrder{})
+				i := TableOf(&softDeleteItem{})
+				return NewSelector[softDeleteOrder](db).
+					From(o.InnerJoin(i).On(o.Col("Id").Eq(i.Col("OrderId"))))
+			}(),
+			wantRes: &Statement{
+				SQL: `SELECT * FROM "soft_delete_order" INNER JOIN "soft_delete_item" ` +
+					`ON "id" = "order_id" AND "soft_delete_item"."deleted_at" = $1 ` +
+					`WHERE "soft_delete_order"."is_deleted" = $2;`,
+				Args: []any{0, false},
+			},
+		}, {
+			name: "join using",
+			selector: func() StatementBuilder {
+				o := TableAs(&softDeleteOrder{}, "o")
+				i := TableAs(&softDeleteItem{}, "i")
+				return NewSelector[softDeleteOrder](db).From(o.InnerJoin(i).Using(o.Col("Id")))
+			}(),
+			wantRes: &Statement{
+				SQL: `SELECT * FROM "soft_delete_order" AS "o" INNER JOIN "soft_delete_item" AS "i" USING ("id") ` +
+					`WHERE ("o"."is_deleted" = $1) AND ("i"."deleted_at" = $2);`,
+				Args: []any{false, 0},
+			},
+		}, {
+			name: "left join using",
+			selector: func() StatementBuilder {
+				o := TableAs(&softDeleteOrder{}, "o")
+				i := TableAs(&softDeleteItem{}, "i")
+				return NewSelector[softDeleteOrder](db).From(o.LeftJoin(i).Using(o.Col("Id")))
+			}(),
+			wantRes: &Statement{
+				SQL: `SELECT * FROM "soft_delete_order" AS "o" LEFT JOIN ` +
+					`(SELECT * FROM "soft_delete_item" WHERE "soft_delete_item"."deleted_at" = $1) AS "i" USING ("id") ` +
+					`WHERE "o"."is_deleted" = $2;`,
+				Args: []any{0, false},
+			},
+		}, {
+			name: "left join using without alias",
+			selector: func() StatementBuilder {
+				o := TableOf(&softDeleteOrder{})
+				i := TableOf(&softDeleteItem{})
+				return NewSelector[softDeleteOrder](db).From(o.LeftJoin(i).Using(o.Col("Id")))
+			}(),
+			wantRes: &Statement{
+				SQL: `SELECT * FROM "soft_delete_order" LEFT JOIN ` +
+					`(SELECT * FROM "soft_delete_item" WHERE "soft_delete_item"."deleted_at" = $1) AS "soft_delete_item" ` +
+					`USING ("id") WHERE "soft_delete_order"."is_deleted" = $2;`,
+				Args: []any{0, false},
+			},
+		}, {
+			name: "right join",
+			selector: func() StatementBuilder {
+				u := TableAs(&softDeleteUser{}, "u")
+				o := TableAs(&softDeleteOrder{}, "o")
+				return NewSelector[softDeleteUser](db).
+					From(u.RightJoin(o).On(u.Col("Id").Eq(o.Col("UserId")))).
+					Where(o.Col("Id").Eq(1))
+			}(),
+			wantRes: &Statement{
+				SQL: `SELECT * FROM "soft_delete_user" AS "u" RIGHT JOIN "soft_delete_order" AS "o" ` +
+					`ON "u"."id" = "o"."user_id" AND "u"."deleted_at" IS NULL ` +
+					`WHERE ("o"."id" = $1) AND ("o"."is_deleted" = $2);`,
+				Args: []any{1, false},
+			},
+		}, {
+			name: "right join using",
+			selector: func() StatementBuilder {
+				o := TableAs(&softDeleteOrder{}, "o")
+				i := TableAs(&softDeleteItem{}, "i")
+				return NewSelector[softDeleteOrder](db).From(o.RightJoin(i).Using(o.Col("Id")))
+			}(),
+			wantRes: &Statement{
+				SQL: `SELECT * FROM (SELECT * FROM "soft_delete_order" WHERE "soft_delete_order"."is_deleted" = $1) AS "o" ` +
+					`RIGHT JOIN "soft_delete_item" AS "i" USING ("id") WHERE "i"."deleted_at" = $2;`,
+				Args: []any{false, 0},
+			},
+		}, {
+			name: "right join using joined tables",
+			selector: func() StatementBuilder {
+				u := TableAs(&softDeleteUser{}, "u")
+				o := TableAs(&softDeleteOrder{}, "o")
+				i := TableAs(&softDeleteItem{}, "i")
+				return NewSelector[softDeleteOrder](db).
+					From(u.InnerJoin(o).On(u.Col("Id").Eq(o.Col("UserId"))).RightJoin(i).Using(o.Col("Id")))
+			}(),
+			wantErr: errs.ErrUnsupportedJoinScope("RIGHT JOIN"),
+		}, {
+			name: "join only deleted",
+			selector: func() StatementBuilder {
+				u := TableAs(&softDeleteUser{}, "u")
+				o := TableAs(&softDeleteOrder{}, "o")
+				return NewSelector[softDeleteUser](db).
+					From(u.InnerJoin(o).On(u.Col("Id").Eq(o.Col("UserId")))).
+					OnlyDeleted()
+			}(),
+			wantRes: &Statement{
+				SQL: `SELECT * FROM "soft_delete_user" AS "u" INNER JOIN "soft_delete_order" AS "o" ` +
+					`ON "u"."id" = "o"."user_id" AND "o"."is_deleted" = $1 ` +
+					`WHERE "u"."deleted_at" IS NOT NULL;`,
+				Args: []any{false},
+			},
+		},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			statement, err := tc.selector.Build()
+			assert.Equal(t, tc.wantErr, err)
+
+			if err == nil {
+				assert.Equal(t, tc.wantRes, statement)
+			}
+		})
+	}
+}
+
+func TestDeleter_SoftDelete(t *testing.T) {
+	db, err := OpenDB(&sql.DB{}, MySQLDialect)
+	require.NoError(t, err)
+
+	tcs := []struct {
+		name     string
+		deleter  StatementBuilder
+		wantSQL  string
+		wantArgs func(t *testing.T, args []any)
+	}{
+		{
+			name:    "deletion time",
+			deleter: NewDeleter[softDeleteUser](db).Where(Col("Id").Eq(1)),
+			wantSQL: "UPDATE `soft_delete_user` SET `deleted_at` = ? WHERE (`id` = ?) AND (`deleted_at` IS NULL);",
+			wantArgs: func(t *testing.T, args []any) {
+				require.Len(t, args, 2)
+				assert.IsType(t, time.Time{}, args[0])
+				assert.Equal(t, 1, args[1])
+			},
+		}, {
+			name:    "deleted flag",
+			deleter: NewDeleter[softDeleteOrder](db).Where(Col("UserId").Eq(1)),
+			wantSQL: "UPDATE `soft_delete_order` SET `is_deleted` = ? WHERE (`user_id` = ?) AND (`is_deleted` = ?);",
+			wantArgs: func(t *testing.T, args []any) {
+				assert.Equal(t, []any{true, 1, false}, args)
+			},
+		}, {
+			name:    "deletion unix time",
+			deleter: NewDeleter[softDeleteItem](db).Where(Col("Id").Eq(1)),
+			wantSQL: "UPDATE `soft_delete_item` SET `deleted_at` = ? WHERE (`id` = ?) AND (`deleted_at` = ?);",
+			wantArgs: func(t *testing.T, args []any) {
+				require.Len(t, args, 3)
+				assert.IsType(t, int64(0), args[0])
+				assert.Equal(t, []any{1, 0}, args[1:])
+			},
+		}, {
+			name:    "without where",
+			deleter: NewDeleter[softDeleteOrder](db),
+			wantSQL: "UPDATE `soft_delete_order` SET `is_deleted` = ?;",
+			wantArgs: func(t *testing.T, args []any) {
+				assert.Equal(t, []any{true}, args)
+			},
+		}, {
+			name:    "unscoped",
+			deleter: NewDeleter[softDeleteUser](db).Unscoped().Where(Col("Id").Eq(1)),
+			wantSQL: "DELETE FROM `soft_delete_user` WHERE `id` = ?;",
+			wantArgs: func(t *testing.T, args []any) {
+				assert.Equal(t, []any{1}, args)
+			},
+		},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			statement, err := tc.deleter.Build()
+			require.NoError(t, err)
+
+			assert.Equal(t, tc.wantSQL, statement.SQL)
+			tc.wantArgs(t, statement.Args)
+		})
+	}
+}
+
+func TestDeleter_ForceDelete(t *testing.T) {
+	mockDB, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer func() { _ = mockDB.Close() }()
+
+	db, err := OpenDB(mockDB, MySQLDialect)
+	require.NoError(t, err)
+
+	mock.ExpectExec("DELETE FROM `soft_delete_user` WHERE `id` = \\?;").
+		WithArgs(1).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	res := NewDeleter[softDeleteUser](db).Where(Col("Id").Eq(1)).ForceDelete(t.Context())
+	require.NoError(t, res.Err())
+
+	assert.Equal(t, int64(1), res.RowsAffected())
+	require.NoError(t, mock.ExpectationsWereMet())
+}
@@ -149,6 +149,14 @@ type SubQuery struct {
 
 	statement *Statement
 	alias     string
+
+	// query rebuilds the statement in the schema and with the table scope of outer statement.
+	query subQueryBuilder
+}
+
+// subQueryBuilder build the statement with tables in the schema and restricted by the table scope.
+type subQueryBuilder interface {
+	buildIn(schema string, scope TableScope) (*Statement, error)
 }
 
 func (s SubQuery) selectable() {}
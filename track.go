@@ -0,0 +1,134 @@
+package easyorm
+
+import (
+	"context"
+	"reflect"
+	"runtime"
+	"weak"
+
+	"github.com/JrMarcco/easy-orm/internal/errs"
+	"github.com/JrMarcco/easy-orm/model"
+)
+
+// snapshot the values of fields of entity when it is found, keyed by field name.
+type snapshot map[string]any
+
+// Track keeps the snapshot of entities found, so that UpdateChanged updates the changed fields only.
+// The snapshot is released when the entity is garbage collected.
+func (s *Selector[T]) Track() *Selector[T] {
+	s.track = true
+	return s
+}
+
+// trackEntities take the snapshot of entities found if tracking.
+func (s *Selector[T]) trackEntities(entities ...*T) error {
+	if !s.track {
+		return nil
+	}
+	return trackEntities(s.orm.getCore(), s.model, entities...)
+}
+
+// UpdateChanged updates the fields of entity changed since it was found by a tracking Selector,
+// no statement is executed if nothing changed.
+// The update is executed as Update(entity).Set(changed columns), and the snapshot is refreshed on success.
+func (u *Updater[T]) UpdateChanged(ctx context.Context, entity *T) Result {
+	if err := u.initModel(); err != nil {
+		return Result{err: err}
+	}
+
+	c := u.orm.getCore()
+	snap, ok := snapshotOf(c, entity)
+	if !ok {
+		return Result{err: errs.ErrUntrackedEntity}
+	}
+
+	current, err := takeSnapshot(c, u.model, entity)
+	if err != nil {
+		return Result{err: err}
+	}
+
+	assigns := make([]Assignable, 0, len(u.model.SeqFields))
+	for _, field := range u.model.SeqFields {
+		if !reflect.DeepEqual(snap[field.FiledName], current[field.FiledName]) {
+			assigns = append(assigns, Col(field.FiledName))
+		}
+	}
+
+	if len(assigns) == 0 {
+		return Result{res: batchResult{}}
+	}
+
+	res := u.Update(entity).Set(assigns...).Exec(ctx)
+	if res.Err() == nil {
+		if err = trackEntities(c, u.model, entity); err != nil {
+			return Result{res: res.res, err: err}
+		}
+	}
+	return res
+}
+
+// trackEntities take the snapshot of entities.
+func trackEntities[T any](c *core, m *model.Model, entities ...*T) error {
+	for _, entity := range entities {
+		snap, err := takeSnapshot(c, m, entity)
+		if err != nil {
+			return err
+		}
+
+		key := weak.Make(entity)
+		if _, loaded := c.snapshots.Swap(key, snap); !loaded {
+			runtime.AddCleanup(entity, func(key weak.Pointer[T]) {
+				releaseSnapshot(c, key)
+			}, key)
+		}
+	}
+	return nil
+}
+
+// releaseSnapshot drop the snapshot of entity, invoked once the entity tracked is garbage collected.
+func releaseSnapshot[T any](c *core, key weak.Pointer[T]) {
+	c.snapshots.Delete(key)
+}
+
+// snapshotOf returns the snapshot of entity tracked.
+func snapshotOf[T any](c *core, entity *T) (snapshot, bool) {
+	if entity == nil {
+		return nil, false
+	}
+
+	snap, ok := c.snapshots.Load(weak.Make(entity))
+	if !ok {
+		return nil, false
+	}
+	return snap.(snapshot), true
+}
+
+// takeSnapshot read the values of fields through the value resolver,
+// values referenced by pointers are copied so that changes through pointers are detected.
+func takeSnapshot(c *core, m *model.Model, entity any) (snapshot, error) {
+	resolver := c.resolverCreator(m, entity)
+
+	snap := make(snapshot, len(m.SeqFields))
+	for _, field := range m.SeqFields {
+		val, err := resolver.ReadColumn(field.FiledName)
+		if err != nil {
+			return nil, err
+		}
+		snap[field.FiledName] = copyValue(val)
+	}
+	return snap, nil
+}
+
+// copyValue copy the value referenced by pointer or the bytes of slice.
+func copyValue(val any) any {
+	rv := reflect.ValueOf(val)
+	switch {
+	case rv.Kind() == reflect.Pointer && !rv.IsNil():
+		return rv.Elem().Interface()
+	case rv.Kind() == reflect.Slice && !rv.IsNil():
+		cp := reflect.MakeSlice(rv.Type(), rv.Len(), rv.Len())
+		reflect.Copy(cp, rv)
+		return cp.Interface()
+	}
+	return val
+}
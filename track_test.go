@@ -0,0 +1,151 @@
+package easyorm
+
+import (
+	"database/sql"
+	"runtime"
+	"testing"
+	"time"
+	"weak"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/JrMarcco/easy-orm/internal/errs"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type trackTestModel struct {
+	Id       uint64
+	Name     string
+	Age      int8
+	NickName *sql.NullString
+	Version  int64 `orm:"version"`
+}
+
+func TestUpdater_UpdateChanged(t *testing.T) {
+	mockDB, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+	require.NoError(t, err)
+	defer func() { _ = mockDB.Close() }()
+
+	db, err := OpenDB(mockDB, MySQLDialect)
+	require.NoError(t, err)
+
+	columns := []string{"id", "name", "age", "nick_name", "version"}
+
+	mock.ExpectQuery("SELECT * FROM `track_test_model` WHERE `id` = ? LIMIT 1;").
+		WithArgs(1).
+		WillReturnRows(sqlmock.NewRows(columns).AddRow(1, "Tom", 18, "tom", 1))
+
+	entity, err := NewSelector[trackTestModel](db).Where(Col("Id").Eq(1)).Track().FindOne(t.Context())
+	require.NoError(t, err)
+
+	t.Run("unchanged", func(t *testing.T) {
+		res := NewUpdater[trackTestModel](db).Where(Col("Id").Eq(1)).UpdateChanged(t.Context(), entity)
+		require.NoError(t, res.Err())
+		assert.Equal(t, int64(0), res.RowsAffected())
+	})
+
+	t.Run("changed", func(t *testing.T) {
+		entity.Age = 19
+		entity.NickName.String = "tommy"
+
+		mock.ExpectExec("UPDATE `track_test_model` SET `age` = ?, `nick_name` = ?, `version` = `version` + ? "+
+			"WHERE (`id` = ?) AND (`version` = ?);").
+			WithArgs(int8(19), &sql.NullString{String: "tommy", Valid: true}, 1, 1, int64(1)).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+
+		res := NewUpdater[trackTestModel](db).Where(Col("Id").Eq(1)).UpdateChanged(t.Context(), entity)
+		require.NoError(t, res.Err())
+		assert.Equal(t, int64(2), entity.Version)
+	})
+
+	t.Run("snapshot refreshed", func(t *testing.T) {
+		entity.Name = "Jerry"
+
+		mock.ExpectExec("UPDATE `track_test_model` SET `name` = ?, `version` = `version` + ? "+
+			"WHERE (`id` = ?) AND (`version` = ?);").
+			WithArgs("Jerry", 1, 1, int64(2)).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+
+		res := NewUpdater[trackTestModel](db).Where(Col("Id").Eq(1)).UpdateChanged(t.Context(), entity)
+		require.NoError(t, res.Err())
+	})
+
+	t.Run("untracked", func(t *testing.T) {
+		res := NewUpdater[trackTestModel](db).UpdateChanged(t.Context(), &trackTestModel{Id: 1})
+		assert.Equal(t, errs.ErrUntrackedEntity, res.Err())
+	})
+
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestSelector_Track(t *testing.T) {
+	mockDB, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer func() { _ = mockDB.Close() }()
+
+	db, err := OpenDB(mockDB, MySQLDialect)
+	require.NoError(t, err)
+
+	columns := []string{"id", "name", "age", "nick_name", "version"}
+
+	mock.ExpectQuery("SELECT .*").
+		WillReturnRows(sqlmock.NewRows(columns).AddRow(1, "Tom", 18, "tom", 1).AddRow(2, "Jerry", 20, nil, 1))
+
+	res, err := NewSelector[trackTestModel](db).Track().FindMulti(t.Context())
+	require.NoError(t, err)
+	require.Len(t, res, 2)
+	for _, entity := range res {
+		_, ok := snapshotOf(db.core, entity)
+		assert.True(t, ok)
+	}
+
+	mock.ExpectQuery("SELECT .*").
+		WillReturnRows(sqlmock.NewRows(columns).AddRow(3, "Spike", 3, nil, 1))
+
+	untracked, err := NewSelector[trackTestModel](db).FindOne(t.Context())
+	require.NoError(t, err)
+	_, ok := snapshotOf(db.core, untracked)
+	assert.False(t, ok)
+
+	releaseSnapshot(db.core, weak.Make(res[0]))
+	_, ok = snapshotOf(db.core, res[0])
+	assert.False(t, ok)
+	_, ok = snapshotOf(db.core, res[1])
+	assert.True(t, ok)
+
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestSelector_TrackReleased(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping the snapshots released by garbage collection in short mode")
+	}
+
+	mockDB, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer func() { _ = mockDB.Close() }()
+
+	db, err := OpenDB(mockDB, MySQLDialect)
+	require.NoError(t, err)
+
+	mock.ExpectQuery("SELECT .*").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "name", "age", "nick_name", "version"}).AddRow(1, "Tom", 18, "tom", 1))
+
+	res, err := NewSelector[trackTestModel](db).Track().FindMulti(t.Context())
+	require.NoError(t, err)
+	require.Len(t, res, 1)
+
+	// snapshots are released with the entities
+	res = nil
+	assert.Eventually(t, func() bool {
+		runtime.GC()
+		count := 0
+		db.core.snapshots.Range(func(_, _ any) bool {
+			count++
+			return true
+		})
+		return count == 0
+	}, 5*time.Second, 10*time.Millisecond)
+
+	require.NoError(t, mock.ExpectationsWereMet())
+}
@@ -5,13 +5,62 @@ import (
 	"database/sql"
 	"database/sql/driver"
 	"errors"
+	"strconv"
+
+	"github.com/JrMarcco/easy-orm/internal/errs"
 )
 
 var _ driver.Tx = (*Tx)(nil)
 
+// txKey context key of the transaction started by DB.DoTx, distinguished by the core of DB.
+type txKey struct {
+	core *core
+}
+
+// bypassTxKey context key to bypass the transaction in context.
+type bypassTxKey struct{}
+
+func contextWithTx(ctx context.Context, tx *Tx) context.Context {
+	ctx = context.WithValue(ctx, bypassTxKey{}, false)
+	return context.WithValue(ctx, txKey{core: tx.core}, tx)
+}
+
+// WithoutTx returns a context whose statements bypass the transaction stored by DB.DoTx,
+// builders created on DB execute on DB directly.
+func WithoutTx(ctx context.Context) context.Context {
+	return context.WithValue(ctx, bypassTxKey{}, true)
+}
+
+// ormOf returns the transaction in context if orm is the DB started it, otherwise orm itself.
+func ormOf(ctx context.Context, orm orm) orm {
+	db, ok := orm.(*DB)
+	if !ok {
+		return orm
+	}
+
+	if tx, ok := db.txFromContext(ctx); ok {
+		return tx
+	}
+	return orm
+}
+
 type Tx struct {
 	*core
 	sqlTx *sql.Tx
+
+	// ctx the context transaction began with, passed to middleware chain on committing and rolling back.
+	ctx context.Context
+	// done whether the transaction is committed or rolled back.
+	done bool
+
+	// savepointSeq sequence of savepoints created by nested transactions.
+	savepointSeq int
+
+	onCommit   []func(ctx context.Context)
+	onRollback []func(ctx context.Context, err error)
+	// nestedRollback rollback hooks of nested transactions rolled back,
+	// executed after the transaction is committed or rolled back.
+	nestedRollback []func(ctx context.Context)
 }
 
 func (t *Tx) getCore() *core {
@@ -22,21 +71,137 @@ func (t *Tx) queryContext(ctx context.Context, sql string, args ...any) (*sql.Ro
 	return t.sqlTx.QueryContext(ctx, sql, args...)
 }
 
+// readContext query in transaction, reads in transaction are never routed to replicas.
+func (t *Tx) readContext(ctx context.Context, sql string, args ...any) (*sql.Rows, error) {
+	return t.sqlTx.QueryContext(ctx, sql, args...)
+}
+
 func (t *Tx) execContext(ctx context.Context, sql string, args ...any) (sql.Result, error) {
 	return t.sqlTx.ExecContext(ctx, sql, args...)
 }
 
+// DoTx run the business function in a nested transaction backed by savepoint.
+// The savepoint is rolled back if the business function returns error or panics, and released otherwise,
+// the outer transaction is not affected by the rollback of nested transaction.
+//
+// Hooks registered in the nested transaction are discarded when the savepoint is rolled back or fails to release,
+// and the rollback hooks of them are executed with the error of nested transaction
+// after the transaction started by DB.DoTx is committed or rolled back.
+func (t *Tx) DoTx(ctx context.Context, bizFunc func(ctx context.Context, tx *Tx) error) (err error) {
+	t.savepointSeq++
+	create, rollback, release := t.dialect.savepoint("sp_" + strconv.Itoa(t.savepointSeq))
+
+	if _, err = t.sqlTx.ExecContext(ctx, create); err != nil {
+		return err
+	}
+
+	commitMark, rollbackMark := len(t.onCommit), len(t.onRollback)
+
+	panicked := true
+	defer func() {
+		if !panicked && err == nil {
+			if release == "" {
+				return
+			}
+			if _, err = t.sqlTx.ExecContext(ctx, release); err == nil {
+				return
+			}
+		} else {
+			_, rbErr := t.sqlTx.ExecContext(ctx, rollback)
+			err = errs.ErrRollback(err, rbErr, panicked)
+		}
+
+		nestedErr := err
+		for _, hook := range t.onRollback[rollbackMark:] {
+			t.nestedRollback = append(t.nestedRollback, func(ctx context.Context) { hook(ctx, nestedErr) })
+		}
+		t.onCommit, t.onRollback = t.onCommit[:commitMark], t.onRollback[:rollbackMark]
+	}()
+
+	err = bizFunc(ctx, t)
+	panicked = false
+
+	return err
+}
+
+// OnCommit register a hook executed in order after the transaction started by DB.DoTx is committed.
+// Panics of hooks are recovered and reported by the error returned from DB.DoTx.
+func (t *Tx) OnCommit(hook func(ctx context.Context)) {
+	t.onCommit = append(t.onCommit, hook)
+}
+
+// OnRollback register a hook executed in order after the transaction started by DB.DoTx is rolled back,
+// err is the error returned from DB.DoTx, or the error of nested transaction the hook registered in, see Tx.DoTx.
+// Panics of hooks are recovered and reported by the error returned from DB.DoTx.
+func (t *Tx) OnRollback(hook func(ctx context.Context, err error)) {
+	t.onRollback = append(t.onRollback, hook)
+}
+
+func runCommitHooks(ctx context.Context, hooks []func(ctx context.Context)) error {
+	var hookErrs []error
+	for _, hook := range hooks {
+		if err := runHook(func() { hook(ctx) }); err != nil {
+			hookErrs = append(hookErrs, err)
+		}
+	}
+	return errors.Join(hookErrs...)
+}
+
+func runRollbackHooks(ctx context.Context, hooks []func(ctx context.Context, err error), txErr error) error {
+	var hookErrs []error
+	for _, hook := range hooks {
+		if err := runHook(func() { hook(ctx, txErr) }); err != nil {
+			hookErrs = append(hookErrs, err)
+		}
+	}
+	return errors.Join(hookErrs...)
+}
+
+func runHook(hook func()) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = errs.ErrTxHookPanic(r)
+		}
+	}()
+
+	hook()
+	return nil
+}
+
 func (t *Tx) Commit() error {
-	return t.sqlTx.Commit()
+	return t.finish(ScTypCOMMIT, t.sqlTx.Commit)
 }
 
 func (t *Tx) Rollback() error {
-	return t.sqlTx.Rollback()
+	return t.finish(ScTypROLLBACK, t.sqlTx.Rollback)
 }
 
 func (t *Tx) RollbackIfNotCommit() error {
-	if err := t.sqlTx.Rollback(); !errors.Is(err, sql.ErrTxDone) {
+	if t.done {
+		return nil
+	}
+
+	if err := t.Rollback(); !errors.Is(err, sql.ErrTxDone) {
 		return err
 	}
 	return nil
 }
+
+// finish commit or rollback the transaction through middleware chain.
+func (t *Tx) finish(typ string, finishFunc func() error) error {
+	res := t.handle(t.ctx, &OrmContext{
+		Typ:     typ,
+		Builder: txStatement(typ),
+	}, func(_ context.Context, _ *OrmContext) *OrmResult {
+		t.done = true
+		return &OrmResult{Err: finishFunc()}
+	})
+	return res.Err
+}
+
+// txStatement the statement of transaction operation passed to middleware chain.
+type txStatement string
+
+func (s txStatement) Build() (*Statement, error) {
+	return &Statement{SQL: string(s)}, nil
+}
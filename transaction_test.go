@@ -0,0 +1,632 @@
+package easyorm
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/JrMarcco/easy-orm/internal/errs"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type txTestModel struct {
+	Id   uint64
+	Name string
+}
+
+func TestTx_DoTx(t *testing.T) {
+	tcs := []struct {
+		name     string
+		dialect  Dialect
+		mockFunc func(mock sqlmock.Sqlmock)
+		bizFunc  func(ctx context.Context, tx *Tx) error
+		wantErr  error
+	}{
+		{
+			name:    "release savepoint",
+			dialect: MySQLDialect,
+			mockFunc: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectExec("SAVEPOINT sp_1").WillReturnResult(sqlmock.NewResult(0, 0))
+				mock.ExpectExec("INSERT INTO `tx_test_model` (`id`, `name`) VALUES (?, ?);").
+					WithArgs(uint64(1), "foo").
+					WillReturnResult(sqlmock.NewResult(1, 1))
+				mock.ExpectExec("RELEASE SAVEPOINT sp_1").WillReturnResult(sqlmock.NewResult(0, 0))
+				mock.ExpectCommit()
+			},
+			bizFunc: func(ctx context.Context, tx *Tx) error {
+				return tx.DoTx(ctx, func(ctx context.Context, tx *Tx) error {
+					return NewInserter[txTestModel](tx).Rows(&txTestModel{Id: 1, Name: "foo"}).Exec(ctx).Err()
+				})
+			},
+		}, {
+			name:    "rollback to savepoint",
+			dialect: PostgresDialect,
+			mockFunc: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectExec("SAVEPOINT sp_1").WillReturnResult(sqlmock.NewResult(0, 0))
+				mock.ExpectExec("ROLLBACK TO SAVEPOINT sp_1").WillReturnResult(sqlmock.NewResult(0, 0))
+				mock.ExpectExec("SAVEPOINT sp_2").WillReturnResult(sqlmock.NewResult(0, 0))
+				mock.ExpectExec("RELEASE SAVEPOINT sp_2").WillReturnResult(sqlmock.NewResult(0, 0))
+				mock.ExpectCommit()
+			},
+			bizFunc: func(ctx context.Context, tx *Tx) error {
+				err := tx.DoTx(ctx, func(ctx context.Context, tx *Tx) error {
+					return errors.New("biz error")
+				})
+				if err == nil {
+					return errors.New("nested transaction should fail")
+				}
+
+				// the outer transaction is still usable
+				return tx.DoTx(ctx, func(ctx context.Context, tx *Tx) error {
+					return nil
+				})
+			},
+		}, {
+			name:    "sql server savepoint",
+			dialect: SQLServerDialect,
+			mockFunc: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectExec("SAVE TRANSACTION sp_1").WillReturnResult(sqlmock.NewResult(0, 0))
+				mock.ExpectExec("ROLLBACK TRANSACTION sp_1").WillReturnResult(sqlmock.NewResult(0, 0))
+				mock.ExpectRollback()
+			},
+			bizFunc: func(ctx context.Context, tx *Tx) error {
+				return tx.DoTx(ctx, func(ctx context.Context, tx *Tx) error {
+					return errors.New("biz error")
+				})
+			},
+			wantErr: errs.ErrRollback(errs.ErrRollback(errors.New("biz error"), nil, false), nil, false),
+		},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			mockDB, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+			require.NoError(t, err)
+			defer func() {
+				_ = mockDB.Close()
+			}()
+
+			db, err := OpenDB(mockDB, tc.dialect)
+			require.NoError(t, err)
+
+			tc.mockFunc(mock)
+
+			err = db.DoTx(context.Background(), tc.bizFunc, nil)
+			assert.Equal(t, tc.wantErr, err)
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestTx_DoTx_Panic(t *testing.T) {
+	mockDB, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+	require.NoError(t, err)
+	defer func() {
+		_ = mockDB.Close()
+	}()
+
+	db, err := OpenDB(mockDB, SQLiteDialect)
+	require.NoError(t, err)
+
+	mock.ExpectBegin()
+	mock.ExpectExec("SAVEPOINT sp_1").WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectExec("ROLLBACK TO SAVEPOINT sp_1").WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectRollback()
+
+	assert.Panics(t, func() {
+		_ = db.DoTx(context.Background(), func(ctx context.Context, tx *Tx) error {
+			return tx.DoTx(ctx, func(ctx context.Context, tx *Tx) error {
+				panic("biz panic")
+			})
+		}, nil)
+	})
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestDB_DoTx_Context(t *testing.T) {
+	mockDB, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+	require.NoError(t, err)
+	defer func() {
+		_ = mockDB.Close()
+	}()
+
+	db, err := OpenDB(mockDB, MySQLDialect)
+	require.NoError(t, err)
+
+	mock.ExpectBegin()
+	mock.ExpectExec("INSERT INTO `tx_test_model` (`id`, `name`) VALUES (?, ?);").
+		WithArgs(uint64(1), "foo").
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectQuery("SELECT * FROM `tx_test_model` WHERE `id` = ? LIMIT 1;").
+		WithArgs(1).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "name"}).AddRow(1, "foo"))
+	mock.ExpectExec("SAVEPOINT sp_1").WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectExec("DELETE FROM `tx_test_model` WHERE `id` = ?;").
+		WithArgs(1).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec("RELEASE SAVEPOINT sp_1").WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectCommit()
+
+	// repository functions only know about DB
+	insert := func(ctx context.Context) error {
+		return NewInserter[txTestModel](db).Rows(&txTestModel{Id: 1, Name: "foo"}).Exec(ctx).Err()
+	}
+	find := func(ctx context.Context) (*txTestModel, error) {
+		return NewSelector[txTestModel](db).Where(Col("Id").Eq(1)).FindOne(ctx)
+	}
+	remove := func(ctx context.Context) error {
+		return db.DoTx(ctx, func(ctx context.Context, _ *Tx) error {
+			return NewDeleter[txTestModel](db).Where(Col("Id").Eq(1)).Exec(ctx).Err()
+		}, nil)
+	}
+
+	err = db.DoTx(context.Background(), func(ctx context.Context, _ *Tx) error {
+		if err := insert(ctx); err != nil {
+			return err
+		}
+		if _, err := find(ctx); err != nil {
+			return err
+		}
+		return remove(ctx)
+	}, nil)
+	require.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestWithoutTx(t *testing.T) {
+	mockDB, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+	require.NoError(t, err)
+	defer func() {
+		_ = mockDB.Close()
+	}()
+
+	db, err := OpenDB(mockDB, MySQLDialect)
+	require.NoError(t, err)
+
+	mock.ExpectBegin()
+	tx, err := db.beginTx(context.Background(), nil)
+	require.NoError(t, err)
+
+	ctx := contextWithTx(context.Background(), tx)
+	assert.Equal(t, tx, ormOf(ctx, db))
+	assert.Equal(t, db, ormOf(WithoutTx(ctx), db))
+
+	// transaction of another DB is ignored
+	anotherDB, err := OpenDB(mockDB, MySQLDialect)
+	require.NoError(t, err)
+	assert.Equal(t, anotherDB, ormOf(ctx, anotherDB))
+
+	// a transaction started in bypassed context is visible again
+	assert.Equal(t, tx, ormOf(contextWithTx(WithoutTx(ctx), tx), db))
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+type mysqlTestError struct {
+	Number   uint16
+	SQLState [5]byte
+	Message  string
+}
+
+func (e *mysqlTestError) Error() string {
+	return e.Message
+}
+
+type pgTestError struct {
+	Code string
+}
+
+func (e *pgTestError) Error() string {
+	return "pg error: " + e.Code
+}
+
+func (e *pgTestError) SQLState() string {
+	return e.Code
+}
+
+func TestDB_DoTx_Retry(t *testing.T) {
+	deadlock := &mysqlTestError{Number: 1213, SQLState: [5]byte{'4', '0', '0', '0', '1'}, Message: "deadlock"}
+
+	tcs := []struct {
+		name         string
+		txOpts       []TxOpt
+		mockFunc     func(mock sqlmock.Sqlmock)
+		bizErrs      []error
+		wantAttempts int
+		wantErr      error
+	}{
+		{
+			name:   "retry on deadlock",
+			txOpts: []TxOpt{TxWithRetry(3), TxWithBackoff(time.Millisecond, 2*time.Millisecond)},
+			mockFunc: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectRollback()
+				mock.ExpectBegin()
+				mock.ExpectCommit()
+			},
+			bizErrs:      []error{deadlock, nil},
+			wantAttempts: 2,
+		}, {
+			name:   "exceed max attempts",
+			txOpts: []TxOpt{TxWithRetry(2), TxWithBackoff(0, 0)},
+			mockFunc: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectRollback()
+				mock.ExpectBegin()
+				mock.ExpectRollback()
+			},
+			bizErrs:      []error{deadlock, deadlock},
+			wantAttempts: 2,
+			wantErr:      errs.ErrRollback(deadlock, nil, false),
+		}, {
+			name:   "not retryable",
+			txOpts: []TxOpt{TxWithRetry(3)},
+			mockFunc: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectRollback()
+			},
+			bizErrs:      []error{errors.New("biz error")},
+			wantAttempts: 1,
+			wantErr:      errs.ErrRollback(errors.New("biz error"), nil, false),
+		}, {
+			name: "custom classifier",
+			txOpts: []TxOpt{
+				TxWithRetry(3),
+				TxWithBackoff(0, 0),
+				TxWithRetryClassifier(func(err error) bool {
+					return err.Error() == "biz error"
+				}),
+			},
+			mockFunc: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectRollback()
+				mock.ExpectBegin()
+				mock.ExpectCommit()
+			},
+			bizErrs:      []error{errors.New("biz error"), nil},
+			wantAttempts: 2,
+		}, {
+			name: "retry on commit error",
+			txOpts: []TxOpt{
+				TxWithRetry(2),
+				TxWithBackoff(0, 0),
+			},
+			mockFunc: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectCommit().WillReturnError(deadlock)
+				mock.ExpectBegin()
+				mock.ExpectCommit()
+			},
+			bizErrs:      []error{nil, nil},
+			wantAttempts: 2,
+		},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			mockDB, mock, err := sqlmock.New()
+			require.NoError(t, err)
+			defer func() {
+				_ = mockDB.Close()
+			}()
+
+			db, err := OpenDB(mockDB, MySQLDialect)
+			require.NoError(t, err)
+
+			tc.mockFunc(mock)
+
+			attempts := 0
+			err = db.DoTx(context.Background(), func(ctx context.Context, tx *Tx) error {
+				attempts++
+				return tc.bizErrs[attempts-1]
+			}, nil, tc.txOpts...)
+			assert.Equal(t, tc.wantErr, err)
+			assert.Equal(t, tc.wantAttempts, attempts)
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestDialect_retryable(t *testing.T) {
+	tcs := []struct {
+		name    string
+		dialect Dialect
+		err     error
+		want    bool
+	}{
+		{
+			name:    "postgres serialization failure",
+			dialect: PostgresDialect,
+			err:     fmt.Errorf("wrapped: %w", &pgTestError{Code: "40001"}),
+			want:    true,
+		}, {
+			name:    "postgres deadlock",
+			dialect: PostgresDialect,
+			err:     &pgTestError{Code: "40P01"},
+			want:    true,
+		}, {
+			name:    "postgres unique violation",
+			dialect: PostgresDialect,
+			err:     &pgTestError{Code: "23505"},
+			want:    false,
+		}, {
+			name:    "mysql deadlock",
+			dialect: MySQLDialect,
+			err:     &mysqlTestError{Number: 1213},
+			want:    true,
+		}, {
+			name:    "mysql duplicate entry",
+			dialect: MySQLDialect,
+			err:     &mysqlTestError{Number: 1062, SQLState: [5]byte{'2', '3', '0', '0', '0'}},
+			want:    false,
+		}, {
+			name:    "sql server deadlock",
+			dialect: SQLServerDialect,
+			err:     &mysqlTestError{Number: 1205},
+			want:    true,
+		}, {
+			name:    "plain error",
+			dialect: MySQLDialect,
+			err:     errors.New("plain error"),
+			want:    false,
+		},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.want, tc.dialect.retryable(tc.err))
+		})
+	}
+}
+
+func TestTx_Hooks(t *testing.T) {
+	tcs := []struct {
+		name      string
+		mockFunc  func(mock sqlmock.Sqlmock)
+		bizFunc   func(ctx context.Context, tx *Tx, calls *[]string) error
+		wantCalls []string
+		wantErr   error
+	}{
+		{
+			name: "commit",
+			mockFunc: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectCommit()
+			},
+			bizFunc: func(ctx context.Context, tx *Tx, calls *[]string) error {
+				tx.OnCommit(func(ctx context.Context) { *calls = append(*calls, "commit 1") })
+				tx.OnRollback(func(ctx context.Context, err error) { *calls = append(*calls, "rollback") })
+				tx.OnCommit(func(ctx context.Context) { *calls = append(*calls, "commit 2") })
+				return nil
+			},
+			wantCalls: []string{"commit 1", "commit 2"},
+		}, {
+			name: "rollback",
+			mockFunc: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectRollback()
+			},
+			bizFunc: func(ctx context.Context, tx *Tx, calls *[]string) error {
+				tx.OnCommit(func(ctx context.Context) { *calls = append(*calls, "commit") })
+				tx.OnRollback(func(ctx context.Context, err error) { *calls = append(*calls, "rollback: "+err.Error()) })
+				return errors.New("biz error")
+			},
+			wantCalls: []string{"rollback: " + errs.ErrRollback(errors.New("biz error"), nil, false).Error()},
+			wantErr:   errs.ErrRollback(errors.New("biz error"), nil, false),
+		}, {
+			name: "commit failed",
+			mockFunc: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectCommit().WillReturnError(errors.New("commit error"))
+			},
+			bizFunc: func(ctx context.Context, tx *Tx, calls *[]string) error {
+				tx.OnCommit(func(ctx context.Context) { *calls = append(*calls, "commit") })
+				tx.OnRollback(func(ctx context.Context, err error) { *calls = append(*calls, "rollback: "+err.Error()) })
+				return nil
+			},
+			wantCalls: []string{"rollback: commit error"},
+			wantErr:   errors.New("commit error"),
+		}, {
+			name: "nested rollback",
+			mockFunc: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectExec("SAVEPOINT sp_1").WillReturnResult(sqlmock.NewResult(0, 0))
+				mock.ExpectExec("ROLLBACK TO SAVEPOINT sp_1").WillReturnResult(sqlmock.NewResult(0, 0))
+				mock.ExpectCommit()
+			},
+			bizFunc: func(ctx context.Context, tx *Tx, calls *[]string) error {
+				tx.OnCommit(func(ctx context.Context) { *calls = append(*calls, "outer commit") })
+				_ = tx.DoTx(ctx, func(ctx context.Context, tx *Tx) error {
+					tx.OnCommit(func(ctx context.Context) { *calls = append(*calls, "nested commit") })
+					tx.OnRollback(func(ctx context.Context, err error) { *calls = append(*calls, "nested rollback: "+err.Error()) })
+					return errors.New("biz error")
+				})
+				*calls = append(*calls, "outer biz")
+				return nil
+			},
+			wantCalls: []string{
+				"outer biz",
+				"nested rollback: " + errs.ErrRollback(errors.New("biz error"), nil, false).Error(),
+				"outer commit",
+			},
+		}, {
+			name: "nested rollback and outer rollback",
+			mockFunc: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectExec("SAVEPOINT sp_1").WillReturnResult(sqlmock.NewResult(0, 0))
+				mock.ExpectExec("ROLLBACK TO SAVEPOINT sp_1").WillReturnResult(sqlmock.NewResult(0, 0))
+				mock.ExpectRollback()
+			},
+			bizFunc: func(ctx context.Context, tx *Tx, calls *[]string) error {
+				tx.OnRollback(func(ctx context.Context, err error) { *calls = append(*calls, "outer rollback") })
+				_ = tx.DoTx(ctx, func(ctx context.Context, tx *Tx) error {
+					tx.OnRollback(func(ctx context.Context, err error) { *calls = append(*calls, "nested rollback") })
+					return errors.New("nested error")
+				})
+				return errors.New("biz error")
+			},
+			wantCalls: []string{"nested rollback", "outer rollback"},
+			wantErr:   errs.ErrRollback(errors.New("biz error"), nil, false),
+		}, {
+			name: "nested release failed",
+			mockFunc: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectExec("SAVEPOINT sp_1").WillReturnResult(sqlmock.NewResult(0, 0))
+				mock.ExpectExec("RELEASE SAVEPOINT sp_1").WillReturnError(errors.New("release error"))
+				mock.ExpectCommit()
+			},
+			bizFunc: func(ctx context.Context, tx *Tx, calls *[]string) error {
+				tx.OnCommit(func(ctx context.Context) { *calls = append(*calls, "outer commit") })
+				_ = tx.DoTx(ctx, func(ctx context.Context, tx *Tx) error {
+					tx.OnCommit(func(ctx context.Context) { *calls = append(*calls, "nested commit") })
+					tx.OnRollback(func(ctx context.Context, err error) { *calls = append(*calls, "nested rollback: "+err.Error()) })
+					return nil
+				})
+				return nil
+			},
+			wantCalls: []string{"nested rollback: release error", "outer commit"},
+		}, {
+			name: "hook panic",
+			mockFunc: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectCommit()
+			},
+			bizFunc: func(ctx context.Context, tx *Tx, calls *[]string) error {
+				tx.OnCommit(func(ctx context.Context) { panic("hook error") })
+				tx.OnCommit(func(ctx context.Context) { *calls = append(*calls, "commit") })
+				return nil
+			},
+			wantCalls: []string{"commit"},
+			wantErr:   errors.Join(errs.ErrTxHookPanic("hook error")),
+		},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			mockDB, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+			require.NoError(t, err)
+			defer func() {
+				_ = mockDB.Close()
+			}()
+
+			db, err := OpenDB(mockDB, PostgresDialect)
+			require.NoError(t, err)
+
+			tc.mockFunc(mock)
+
+			var calls []string
+			err = db.DoTx(context.Background(), func(ctx context.Context, tx *Tx) error {
+				return tc.bizFunc(ctx, tx, &calls)
+			}, nil)
+			if tc.wantErr != nil {
+				require.Error(t, err)
+				assert.Equal(t, tc.wantErr.Error(), err.Error())
+			} else {
+				require.NoError(t, err)
+			}
+			assert.Equal(t, tc.wantCalls, calls)
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+type txCtxKey struct{}
+
+func TestDB_DoTx_Middleware(t *testing.T) {
+	tcs := []struct {
+		name     string
+		mockFunc func(mock sqlmock.Sqlmock)
+		bizErr   error
+		wantTyps []string
+		wantErr  error
+	}{
+		{
+			name: "commit",
+			mockFunc: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectExec("INSERT INTO `tx_test_model` (`id`, `name`) VALUES (?, ?);").
+					WithArgs(uint64(1), "foo").
+					WillReturnResult(sqlmock.NewResult(1, 1))
+				mock.ExpectCommit()
+			},
+			wantTyps: []string{ScTypBEGIN, ScTypINSERT, ScTypCOMMIT},
+		}, {
+			name: "rollback",
+			mockFunc: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectExec("INSERT INTO `tx_test_model` (`id`, `name`) VALUES (?, ?);").
+					WithArgs(uint64(1), "foo").
+					WillReturnResult(sqlmock.NewResult(1, 1))
+				mock.ExpectRollback()
+			},
+			bizErr:   errors.New("biz error"),
+			wantTyps: []string{ScTypBEGIN, ScTypINSERT, ScTypROLLBACK},
+			wantErr:  errs.ErrRollback(errors.New("biz error"), nil, false),
+		}, {
+			name: "begin failed",
+			mockFunc: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin().WillReturnError(errors.New("begin error"))
+			},
+			wantTyps: []string{ScTypBEGIN},
+			wantErr:  errors.New("begin error"),
+		},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			mockDB, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+			require.NoError(t, err)
+			defer func() {
+				_ = mockDB.Close()
+			}()
+
+			var typs []string
+			var sqls []string
+			db, err := OpenDB(mockDB, MySQLDialect, DBWithMiddlewareChain(MiddlewareChain{
+				func(next HandleFunc) HandleFunc {
+					return func(ctx context.Context, ormCtx *OrmContext) *OrmResult {
+						typs = append(typs, ormCtx.Typ)
+						if ormCtx.Typ == ScTypBEGIN {
+							ctx = context.WithValue(ctx, txCtxKey{}, "tx")
+						} else {
+							// statements in transaction see the context passed on beginning.
+							assert.Equal(t, "tx", ctx.Value(txCtxKey{}))
+						}
+
+						statement, err := ormCtx.Builder.Build()
+						require.NoError(t, err)
+						sqls = append(sqls, statement.SQL)
+
+						return next(ctx, ormCtx)
+					}
+				},
+			}))
+			require.NoError(t, err)
+
+			tc.mockFunc(mock)
+
+			err = db.DoTx(context.Background(), func(ctx context.Context, tx *Tx) error {
+				if err := NewInserter[txTestModel](db).Rows(&txTestModel{Id: 1, Name: "foo"}).Exec(ctx).Err(); err != nil {
+					return err
+				}
+				return tc.bizErr
+			}, nil)
+			if tc.wantErr != nil {
+				require.Error(t, err)
+				assert.Equal(t, tc.wantErr.Error(), err.Error())
+			} else {
+				require.NoError(t, err)
+			}
+
+			assert.Equal(t, tc.wantTyps, typs)
+			assert.Equal(t, tc.wantTyps[0], sqls[0])
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
@@ -1,18 +1,325 @@
 package easyorm
 
-import "context"
+import (
+	"context"
+	"errors"
+	"reflect"
+	"slices"
+	"time"
+
+	"github.com/JrMarcco/easy-orm/internal/errs"
+	"github.com/JrMarcco/easy-orm/model"
+)
 
 var _ Executor[any] = (*Updater[any])(nil)
 
+// ErrOptimisticLock returned by Result.Err when the update by entity of versioned model affects no rows,
+// i.e. the row is modified or deleted since the entity was read.
+var ErrOptimisticLock = errs.ErrOptimisticLock
+
 type Updater[T any] struct {
 	builder
 
-	orm orm
+	orm     orm
+	entity  *T
+	assigns []Assignable
+	where   []Condition
+	scopes  []Predicate
+	// unscoped updates the rows marked deleted as well.
+	unscoped bool
+	// now the time of auto update time field, the current time of clock if not executed.
+	now time.Time
+}
+
+// Exec executes the update statement.
+// The update by entity of versioned model fails with ErrOptimisticLock if no rows affected,
+// otherwise the new version is written back into the entity.
+// The auto update time field is always updated and written back into the entity,
+// and the fields of entity updated are validated before any SQL is sent.
+// BeforeUpdate and AfterUpdate hooks are invoked on the entity, and skipped if updated by condition only.
+func (u *Updater[T]) Exec(ctx context.Context) Result {
+	if err := u.initModel(); err != nil {
+		return Result{err: err}
+	}
+
+	if u.entity == nil {
+		return u.execute(ctx)
+	}
+	return execWithHooks(ctx, u.model, model.HookBeforeUpdate, model.HookAfterUpdate, []*T{u.entity}, func() Result {
+		return u.execute(ctx)
+	})
+}
+
+func (u *Updater[T]) execute(ctx context.Context) Result {
+	u.scopes = nil
+	u.tableScope = nil
+	u.now = u.orm.getCore().now()
+	u.schema = u.orm.getCore().schemaOf(ctx)
+	if err := u.initModel(); err != nil {
+		return Result{err: err}
+	}
+
+	if err := u.validate(); err != nil {
+		return Result{err: err}
+	}
+
+	var res Result
+	if sdb, ok := u.orm.(*ShardingDB); ok {
+		res = u.execSharding(ctx, sdb)
+	} else {
+		res = exec(ctx, &OrmContext{
+			Typ:     ScTypUPDATE,
+			Model:   u.model,
+			Builder: u,
+		}, u.orm)
+	}
+
+	res = u.checkVersion(res)
+	if res.Err() == nil {
+		u.writeAutoTime()
+	}
+	return res
+}
+
+func (u *Updater[T]) initModel() error {
+	if u.model != nil {
+		return nil
+	}
+
+	var err error
+	u.model, err = u.orm.getCore().registry.GetModel(new(T))
+	return err
+}
+
+// Update updates the row by entity, all fields of entity except the primary key and tenant are updated unless Set specified.
+// The row is specified by the primary key of entity unless Where specified,
+// the update fails with ErrUpdateWithoutWhere if the model has no primary key.
+func (u *Updater[T]) Update(entity *T) *Updater[T] {
+	u.entity = entity
+	return u
 }
 
-func (u Updater[T]) Exec(ctx context.Context) Result {
-	//TODO implement me
-	panic("implement me")
+// Set the assignments, Column assigns the column to the value of field in the entity,
+// like Set(Col("Name"), Assign("Age", 18), Assign("Count", Col("Count").Add(1))).
+func (u *Updater[T]) Set(assigns ...Assignable) *Updater[T] {
+	u.assigns = assigns
+	return u
+}
+
+func (u *Updater[T]) Where(pds ...Predicate) *Updater[T] {
+	if len(pds) == 0 {
+		return u
+	}
+
+	if u.where == nil {
+		u.where = make([]Condition, 0, 1)
+	}
+
+	u.where = append(u.where, NewCondition(condTypWhere, pds))
+	return u
+}
+
+// Unscoped updates the rows marked deleted as well.
+func (u *Updater[T]) Unscoped() *Updater[T] {
+	u.unscoped = true
+	return u
+}
+
+func (u *Updater[T]) Build() (*Statement, error) {
+	var err error
+	if u.model == nil {
+		if err = u.initModel(); err != nil {
+			return nil, err
+		}
+	}
+
+	u.reset()
+
+	where, err := u.entityWhere()
+	if err != nil {
+		return nil, err
+	}
+
+	assigns, err := u.assignments()
+	if err != nil {
+		return nil, err
+	}
+
+	u.sqlBuffer.WriteString("UPDATE ")
+	u.writeTable()
+	u.sqlBuffer.WriteString(" SET ")
+	if err = u.buildAssigns(assigns); err != nil {
+		return nil, err
+	}
+
+	scopes, err := u.modelScopes(u.scopes)
+	if err != nil {
+		return nil, err
+	}
+	if u.locked() {
+		version, err := u.orm.getCore().resolverCreator(u.model, u.entity).ReadColumn(u.model.Version.FiledName)
+		if err != nil {
+			return nil, err
+		}
+		scopes = append(scopes, Col(u.model.Version.FiledName).Eq(version))
+	}
+	if u.model.SoftDelete != nil && !u.unscoped {
+		scopes = append(scopes, softDeleted(u.model.SoftDelete, Col(u.model.SoftDelete.FiledName), false))
+	}
+
+	if where = scopedWhere(where, scopes); len(where) > 0 {
+		if err = u.buildCondition(where); err != nil {
+			return nil, err
+		}
+	}
+
+	u.writeTerminator()
+	return &Statement{
+		SQL:  u.sqlBuffer.String(),
+		Args: u.args,
+	}, nil
+}
+
+// entityWhere returns the where of update, which is the primary key of entity if updated by entity without Where.
+func (u *Updater[T]) entityWhere() ([]Condition, error) {
+	if u.entity == nil || len(u.where) > 0 {
+		return u.where, nil
+	}
+
+	pd, err := primaryKeyOf(u.orm.getCore(), u.model, []*T{u.entity})
+	if err != nil {
+		if errors.Is(err, errs.ErrWithoutPrimaryKey) {
+			return nil, errs.ErrUpdateWithoutWhere
+		}
+		return nil, err
+	}
+	return []Condition{NewCondition(condTypWhere, []Predicate{pd})}, nil
+}
+
+// assignments returns the assignments with Column resolved to the value of entity,
+// the auto update time set to now and the version increased unless they are assigned.
+func (u *Updater[T]) assignments() ([]Assignable, error) {
+	assigns := u.assigns
+	if len(assigns) == 0 {
+		if u.entity == nil {
+			return nil, errs.ErrUpdateWithoutAssigns
+		}
+
+		pks := u.model.PrimaryKeys()
+		assigns = make([]Assignable, 0, len(u.model.SeqFields))
+		for _, field := range u.model.SeqFields {
+			if field != u.model.Version && field != u.model.Tenant && !slices.Contains(pks, field) {
+				assigns = append(assigns, Col(field.FiledName))
+			}
+		}
+	}
+
+	autoTime := u.model.AutoUpdateTime
+	if autoTime != nil && u.assigned(autoTime.FiledName) {
+		autoTime = nil
+	}
+
+	res := make([]Assignable, 0, len(assigns)+2)
+	for _, assign := range assigns {
+		switch assignTyp := assign.(type) {
+		case Column:
+			if autoTime != nil && assignTyp.fieldName == autoTime.FiledName {
+				continue
+			}
+			if u.entity == nil {
+				return nil, errs.ErrUpdateWithoutEntity
+			}
+
+			val, err := u.orm.getCore().resolverCreator(u.model, u.entity).ReadColumn(assignTyp.fieldName)
+			if err != nil {
+				return nil, err
+			}
+			res = append(res, Assign(assignTyp.fieldName, val))
+		default:
+			res = append(res, assign)
+		}
+	}
+
+	if autoTime != nil {
+		now := u.now
+		if now.IsZero() {
+			now = u.orm.getCore().now()
+		}
+		res = append(res, Assign(autoTime.FiledName, autoTimeValue(autoTime, now)))
+	}
+
+	if u.increased() {
+		fieldName := u.model.Version.FiledName
+		res = append(res, Assign(fieldName, Col(fieldName).Add(1)))
+	}
+	return res, nil
+}
+
+// increased reports whether the version is increased by the update.
+func (u *Updater[T]) increased() bool {
+	return u.model.Version != nil && !u.assigned(u.model.Version.FiledName)
+}
+
+// assigned reports whether the field is assigned explicitly by Assign.
+func (u *Updater[T]) assigned(fieldName string) bool {
+	for _, assign := range u.assigns {
+		if a, ok := assign.(Assignment); ok && a.filedName == fieldName {
+			return true
+		}
+	}
+	return false
+}
+
+// locked reports whether the update is guarded by the version of entity.
+func (u *Updater[T]) locked() bool {
+	return u.entity != nil && u.model.Version != nil
+}
+
+// checkVersion fails the update guarded by version if no rows affected,
+// and write the increased version back into the entity.
+func (u *Updater[T]) checkVersion(res Result) Result {
+	if res.Err() != nil || !u.locked() {
+		return res
+	}
+
+	affected, err := res.res.RowsAffected()
+	if err != nil {
+		return Result{res: res.res, err: err}
+	}
+	if affected == 0 {
+		return Result{res: res.res, err: ErrOptimisticLock}
+	}
+
+	if u.increased() {
+		version := reflect.ValueOf(u.entity).Elem().FieldByName(u.model.Version.FiledName)
+		if version.CanInt() {
+			version.SetInt(version.Int() + 1)
+		} else {
+			version.SetUint(version.Uint() + 1)
+		}
+	}
+	return res
+}
+
+// writeAutoTime write the time updated back into the auto update time field of entity.
+func (u *Updater[T]) writeAutoTime() {
+	autoTime := u.model.AutoUpdateTime
+	if u.entity == nil || autoTime == nil || u.assigned(autoTime.FiledName) {
+		return
+	}
+
+	field := reflect.ValueOf(u.entity).Elem().FieldByName(autoTime.FiledName)
+	setAutoTime(field, autoTime, u.now)
+}
+
+func (u *Updater[T]) buildCondition(where []Condition) error {
+	for _, c := range where {
+		u.sqlBuffer.WriteString(c.typ.String())
+		if err := u.buildExpr(c.expr); err != nil {
+			return err
+		}
+	}
+	return nil
 }
 
 func NewUpdater[T any](session orm) *Updater[T] {
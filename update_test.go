@@ -1 +1,247 @@
 package easyorm
+
+import (
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/JrMarcco/easy-orm/internal/errs"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type updateTestModel struct {
+	Id   uint64
+	Age  int8
+	Name string
+}
+
+type versionTestModel struct {
+	Id      uint64
+	Name    string
+	Version int64 `orm:"version"`
+}
+
+type softDeleteVersionModel struct {
+	Id        uint64
+	Name      string
+	Version   uint32     `orm:"version"`
+	DeletedAt *time.Time `orm:"soft_delete"`
+}
+
+func TestUpdater_Build(t *testing.T) {
+	db, err := OpenDB(&sql.DB{}, MySQLDialect)
+	require.NoError(t, err)
+
+	tcs := []struct {
+		name    string
+		updater *Updater[updateTestModel]
+		wantRes *Statement
+		wantErr error
+	}{
+		{
+			name:    "set",
+			updater: NewUpdater[updateTestModel](db).Set(Assign("Age", 18), Assign("Name", "Tom")),
+			wantRes: &Statement{
+				SQL:  "UPDATE `update_test_model` SET `age` = ?, `name` = ?;",
+				Args: []any{18, "Tom"},
+			},
+		}, {
+			name: "set with where",
+			updater: NewUpdater[updateTestModel](db).
+				Set(Assign("Age", Col("Age").Add(1))).
+				Where(Col("Id").Eq(1)),
+			wantRes: &Statement{
+				SQL:  "UPDATE `update_test_model` SET `age` = `age` + ? WHERE `id` = ?;",
+				Args: []any{1, 1},
+			},
+		}, {
+			name:    "update entity",
+			updater: NewUpdater[updateTestModel](db).Update(&updateTestModel{Id: 1, Age: 18, Name: "Tom"}).Where(Col("Id").Eq(1)),
+			wantRes: &Statement{
+				SQL:  "UPDATE `update_test_model` SET `age` = ?, `name` = ? WHERE `id` = ?;",
+				Args: []any{int8(18), "Tom", 1},
+			},
+		}, {
+			name: "update columns of entity",
+			updater: NewUpdater[updateTestModel](db).
+				Update(&updateTestModel{Id: 1, Age: 18, Name: "Tom"}).
+				Set(Col("Name"), Assign("Age", 20)).
+				Where(Col("Id").Eq(1)),
+			wantRes: &Statement{
+				SQL:  "UPDATE `update_test_model` SET `name` = ?, `age` = ? WHERE `id` = ?;",
+				Args: []any{"Tom", 20, 1},
+			},
+		}, {
+			name:    "without assigns",
+			updater: NewUpdater[updateTestModel](db),
+			wantErr: errs.ErrUpdateWithoutAssigns,
+		}, {
+			name:    "entity by primary key",
+			updater: NewUpdater[updateTestModel](db).Update(&updateTestModel{Id: 1, Age: 18, Name: "Tom"}),
+			wantRes: &Statement{
+				SQL:  "UPDATE `update_test_model` SET `age` = ?, `name` = ? WHERE `id` = ?;",
+				Args: []any{int8(18), "Tom", uint64(1)},
+			},
+		}, {
+			name:    "column without entity",
+			updater: NewUpdater[updateTestModel](db).Set(Col("Name")),
+			wantErr: errs.ErrUpdateWithoutEntity,
+		}, {
+			name:    "invalid field",
+			updater: NewUpdater[updateTestModel](db).Set(Assign("Invalid", 1)),
+			wantErr: errs.ErrInvalidField("Invalid"),
+		},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			statement, err := tc.updater.Build()
+			assert.Equal(t, tc.wantErr, err)
+
+			if err == nil {
+				assert.Equal(t, tc.wantRes, statement)
+			}
+		})
+	}
+}
+
+func TestUpdater_PrimaryKey(t *testing.T) {
+	type pkTestModel struct {
+		Code   string `orm:"pk"`
+		Region string `orm:"pk"`
+		Name   string
+	}
+	type noPKTestModel struct {
+		Name string
+	}
+
+	db, err := OpenDB(&sql.DB{}, MySQLDialect)
+	require.NoError(t, err)
+
+	statement, err := NewUpdater[pkTestModel](db).Update(&pkTestModel{Code: "a", Region: "cn", Name: "Tom"}).Build()
+	require.NoError(t, err)
+	assert.Equal(t, &Statement{
+		SQL:  "UPDATE `pk_test_model` SET `name` = ? WHERE (`code` = ?) AND (`region` = ?);",
+		Args: []any{"Tom", "a", "cn"},
+	}, statement)
+
+	_, err = NewUpdater[noPKTestModel](db).Update(&noPKTestModel{Name: "Tom"}).Build()
+	assert.Equal(t, errs.ErrUpdateWithoutWhere, err)
+}
+
+func TestUpdater_Version(t *testing.T) {
+	db, err := OpenDB(&sql.DB{}, PostgresDialect)
+	require.NoError(t, err)
+
+	tcs := []struct {
+		name    string
+		updater StatementBuilder
+		wantRes *Statement
+	}{
+		{
+			name: "update entity",
+			updater: NewUpdater[versionTestModel](db).
+				Update(&versionTestModel{Id: 1, Name: "Tom", Version: 3}).
+				Where(Col("Id").Eq(1)),
+			wantRes: &Statement{
+				SQL: `UPDATE "version_test_model" SET "name" = $1, "version" = "version" + $2 ` +
+					`WHERE ("id" = $3) AND ("version" = $4);`,
+				Args: []any{"Tom", 1, 1, int64(3)},
+			},
+		}, {
+			name:    "set without entity",
+			updater: NewUpdater[versionTestModel](db).Set(Assign("Name", "Tom")).Where(Col("Id").Eq(1)),
+			wantRes: &Statement{
+				SQL:  `UPDATE "version_test_model" SET "name" = $1, "version" = "version" + $2 WHERE "id" = $3;`,
+				Args: []any{"Tom", 1, 1},
+			},
+		}, {
+			name:    "assign version",
+			updater: NewUpdater[versionTestModel](db).Set(Assign("Version", 0)),
+			wantRes: &Statement{
+				SQL:  `UPDATE "version_test_model" SET "version" = $1;`,
+				Args: []any{0},
+			},
+		}, {
+			name: "soft delete",
+			updater: NewUpdater[softDeleteVersionModel](db).
+				Update(&softDeleteVersionModel{Id: 1, Name: "Tom", Version: 3}).
+				Set(Col("Name")).
+				Where(Col("Id").Eq(1)),
+			wantRes: &Statement{
+				SQL: `UPDATE "soft_delete_version_model" SET "name" = $1, "version" = "version" + $2 ` +
+					`WHERE (("id" = $3) AND ("version" = $4)) AND ("deleted_at" IS NULL);`,
+				Args: []any{"Tom", 1, 1, uint32(3)},
+			},
+		}, {
+			name: "unscoped",
+			updater: NewUpdater[softDeleteVersionModel](db).
+				Set(Assign("DeletedAt", nil)).
+				Where(Col("Id").Eq(1)).
+				Unscoped(),
+			wantRes: &Statement{
+				SQL:  `UPDATE "soft_delete_version_model" SET "deleted_at" = $1, "version" = "version" + $2 WHERE "id" = $3;`,
+				Args: []any{nil, 1, 1},
+			},
+		},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			statement, err := tc.updater.Build()
+			require.NoError(t, err)
+			assert.Equal(t, tc.wantRes, statement)
+		})
+	}
+}
+
+func TestUpdater_Exec(t *testing.T) {
+	mockDB, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer func() { _ = mockDB.Close() }()
+
+	db, err := OpenDB(mockDB, MySQLDialect)
+	require.NoError(t, err)
+
+	tcs := []struct {
+		name        string
+		mockFunc    func()
+		entity      *versionTestModel
+		wantErr     error
+		wantVersion int64
+	}{
+		{
+			name: "updated",
+			mockFunc: func() {
+				mock.ExpectExec("UPDATE `version_test_model` SET .*").
+					WithArgs("Tom", 1, uint64(1), int64(3)).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+			},
+			entity:      &versionTestModel{Id: 1, Name: "Tom", Version: 3},
+			wantVersion: 4,
+		}, {
+			name: "optimistic lock",
+			mockFunc: func() {
+				mock.ExpectExec("UPDATE `version_test_model` SET .*").
+					WithArgs("Tom", 1, uint64(1), int64(3)).
+					WillReturnResult(sqlmock.NewResult(0, 0))
+			},
+			entity:      &versionTestModel{Id: 1, Name: "Tom", Version: 3},
+			wantErr:     ErrOptimisticLock,
+			wantVersion: 3,
+		},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			tc.mockFunc()
+
+			res := NewUpdater[versionTestModel](db).Update(tc.entity).Exec(t.Context())
+			assert.ErrorIs(t, res.Err(), tc.wantErr)
+			assert.Equal(t, tc.wantVersion, tc.entity.Version)
+			require.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
@@ -0,0 +1,64 @@
+package easyorm
+
+import (
+	"errors"
+
+	"github.com/JrMarcco/easy-orm/model"
+)
+
+// ValidationError returned before any SQL is sent when the rows inserted or the entity updated
+// fail the validation rules declared on fields, like `orm:"not_null,size=64"` or `validate:"min=1,max=100"`.
+type ValidationError = model.ValidationError
+
+// FieldError the field failing the validation rule.
+type FieldError = model.FieldError
+
+// validate check the inserted fields of rows, returns the errors of all invalid rows joined by errors.Join,
+// each *ValidationError carrying the index of its row.
+func (i *Inserter[T]) validate() error {
+	if len(i.rows) == 0 {
+		return nil
+	}
+
+	fields, err := i.insertFields()
+	if err != nil {
+		return err
+	}
+
+	var rowErrs []error
+	for idx, row := range i.rows {
+		if err = i.model.Validate(row, fields...); err != nil {
+			if validationErr, ok := err.(*ValidationError); ok {
+				validationErr.Row = idx
+			}
+			rowErrs = append(rowErrs, err)
+		}
+	}
+	return errors.Join(rowErrs...)
+}
+
+// validate check the fields of entity updated.
+func (u *Updater[T]) validate() error {
+	if u.entity == nil {
+		return nil
+	}
+
+	fields := make([]*model.Field, 0, len(u.model.SeqFields))
+	if len(u.assigns) == 0 {
+		fields = append(fields, u.model.SeqFields...)
+	}
+	for _, assign := range u.assigns {
+		if col, ok := assign.(Column); ok {
+			field, ok := u.model.Fields[col.fieldName]
+			if !ok {
+				continue
+			}
+			fields = append(fields, field)
+		}
+	}
+
+	if len(fields) == 0 {
+		return nil
+	}
+	return u.model.Validate(u.entity, fields...)
+}
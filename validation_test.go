@@ -0,0 +1,103 @@
+package easyorm
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type validationTestModel struct {
+	Id   uint64
+	Name string `orm:"not_null,size=4"`
+	Age  int8   `validate:"min=1,max=150"`
+}
+
+func TestInserter_Validate(t *testing.T) {
+	mockDB, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+	require.NoError(t, err)
+	defer func() { _ = mockDB.Close() }()
+
+	db, err := OpenDB(mockDB, MySQLDialect)
+	require.NoError(t, err)
+
+	t.Run("invalid", func(t *testing.T) {
+		res := NewInserter[validationTestModel](db).Rows(
+			&validationTestModel{Id: 1, Name: "Tom", Age: 18},
+			&validationTestModel{Id: 2, Name: "Jerry", Age: -1},
+		).Exec(t.Context())
+
+		var validationErr *ValidationError
+		require.True(t, errors.As(res.Err(), &validationErr))
+		assert.Equal(t, &ValidationError{
+			Row: 1,
+			Fields: []FieldError{
+				{Field: "Name", Rule: "size", Param: "4", Value: "Jerry"},
+				{Field: "Age", Rule: "min", Param: "1", Value: int8(-1)},
+			},
+		}, validationErr)
+	})
+
+	t.Run("invalid rows", func(t *testing.T) {
+		res := NewInserter[validationTestModel](db).Rows(
+			&validationTestModel{Id: 1, Name: "Jerry", Age: 18},
+			&validationTestModel{Id: 2, Name: "Tom", Age: 18},
+			&validationTestModel{Id: 3, Name: "Tom", Age: -1},
+		).Exec(t.Context())
+
+		joined, ok := res.Err().(interface{ Unwrap() []error })
+		require.True(t, ok)
+		assert.Equal(t, []error{
+			&ValidationError{
+				Row:    0,
+				Fields: []FieldError{{Field: "Name", Rule: "size", Param: "4", Value: "Jerry"}},
+			},
+			&ValidationError{
+				Row:    2,
+				Fields: []FieldError{{Field: "Age", Rule: "min", Param: "1", Value: int8(-1)}},
+			},
+		}, joined.Unwrap())
+	})
+
+	t.Run("fields not inserted", func(t *testing.T) {
+		mock.ExpectExec("INSERT INTO `validation_test_model` (`id`, `name`) VALUES (?, ?);").
+			WithArgs(uint64(1), "Tom").
+			WillReturnResult(sqlmock.NewResult(1, 1))
+
+		res := NewInserter[validationTestModel](db).Fields("Id", "Name").Rows(&validationTestModel{Id: 1, Name: "Tom"}).Exec(t.Context())
+		require.NoError(t, res.Err())
+	})
+
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestUpdater_Validate(t *testing.T) {
+	mockDB, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+	require.NoError(t, err)
+	defer func() { _ = mockDB.Close() }()
+
+	db, err := OpenDB(mockDB, MySQLDialect)
+	require.NoError(t, err)
+
+	t.Run("invalid", func(t *testing.T) {
+		entity := &validationTestModel{Id: 1, Name: "Jerry", Age: 18}
+		res := NewUpdater[validationTestModel](db).Update(entity).Where(Col("Id").Eq(1)).Exec(t.Context())
+		assert.Equal(t, &ValidationError{
+			Fields: []FieldError{{Field: "Name", Rule: "size", Param: "4", Value: "Jerry"}},
+		}, res.Err())
+	})
+
+	t.Run("fields not updated", func(t *testing.T) {
+		mock.ExpectExec("UPDATE `validation_test_model` SET `age` = ? WHERE `id` = ?;").
+			WithArgs(int8(18), 1).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+
+		entity := &validationTestModel{Id: 1, Name: "Jerry", Age: 18}
+		res := NewUpdater[validationTestModel](db).Update(entity).Set(Col("Age")).Where(Col("Id").Eq(1)).Exec(t.Context())
+		require.NoError(t, res.Err())
+	})
+
+	require.NoError(t, mock.ExpectationsWereMet())
+}